@@ -13,6 +13,33 @@ type CompassManagerMappingStatus struct {
 	Registered bool   `json:"registered"`
 	Configured bool   `json:"configured"`
 	State      string `json:"state,omitempty"`
+	// NotReadySince is when the state changed from Ready, or was first set to another state, it's cleared once the mapping is Ready
+	NotReadySince *metav1.Time `json:"notReadySince,omitempty"`
+	// ConfiguredTime is when the Compass Runtime Agent was configured for the first time, it's kept when the runtime is registered again
+	ConfiguredTime *metav1.Time `json:"configuredTime,omitempty"`
+	// RuntimeContexts are contexts of the runtime created in Compass for additional subaccounts of the Kyma
+	RuntimeContexts []RuntimeContext `json:"runtimeContexts,omitempty"`
+	// Formations are names of Compass formations the runtime is assigned to
+	Formations []string `json:"formations,omitempty"`
+	// Assignments summarise formations and applications Compass assigned to the runtime, they're read periodically
+	Assignments *CompassAssignments `json:"assignments,omitempty"`
+}
+
+// CompassAssignments are formations and applications assigned to the runtime in Compass
+type CompassAssignments struct {
+	FormationCount int      `json:"formationCount"`
+	Formations     []string `json:"formations,omitempty"`
+	// ApplicationCount counts all applications, while Applications lists the names of up to 200 of them
+	ApplicationCount int      `json:"applicationCount"`
+	Applications     []string `json:"applications,omitempty"`
+	// LastReadTime is when the assignments were read from Compass
+	LastReadTime metav1.Time `json:"lastReadTime"`
+}
+
+// RuntimeContext is a context of the runtime created in Compass for a subaccount
+type RuntimeContext struct {
+	ID         string `json:"id"`
+	Subaccount string `json:"subaccount"`
 }
 
 //+kubebuilder:object:root=true
@@ -8,12 +8,38 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompassAssignments) DeepCopyInto(out *CompassAssignments) {
+	*out = *in
+	if in.Formations != nil {
+		in, out := &in.Formations, &out.Formations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastReadTime.DeepCopyInto(&out.LastReadTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompassAssignments.
+func (in *CompassAssignments) DeepCopy() *CompassAssignments {
+	if in == nil {
+		return nil
+	}
+	out := new(CompassAssignments)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompassManagerMapping) DeepCopyInto(out *CompassManagerMapping) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	out.Spec = in.Spec
 }
 
@@ -85,6 +111,29 @@ func (in *CompassManagerMappingSpec) DeepCopy() *CompassManagerMappingSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompassManagerMappingStatus) DeepCopyInto(out *CompassManagerMappingStatus) {
 	*out = *in
+	if in.NotReadySince != nil {
+		in, out := &in.NotReadySince, &out.NotReadySince
+		*out = (*in).DeepCopy()
+	}
+	if in.ConfiguredTime != nil {
+		in, out := &in.ConfiguredTime, &out.ConfiguredTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RuntimeContexts != nil {
+		in, out := &in.RuntimeContexts, &out.RuntimeContexts
+		*out = make([]RuntimeContext, len(*in))
+		copy(*out, *in)
+	}
+	if in.Formations != nil {
+		in, out := &in.Formations, &out.Formations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Assignments != nil {
+		in, out := &in.Assignments, &out.Assignments
+		*out = new(CompassAssignments)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompassManagerMappingStatus.
@@ -96,3 +145,18 @@ func (in *CompassManagerMappingStatus) DeepCopy() *CompassManagerMappingStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeContext) DeepCopyInto(out *RuntimeContext) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeContext.
+func (in *RuntimeContext) DeepCopy() *RuntimeContext {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeContext)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,461 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/controllers"
+	"github.com/kyma-project/compass-manager/internal/backup"
+	"github.com/kyma-project/compass-manager/internal/consistency"
+	"github.com/kyma-project/compass-manager/internal/director"
+	kyma "github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputCSV   = "csv"
+
+	// lastErrorsCount is the number of the most recent errors shown by describe
+	lastErrorsCount = 5
+)
+
+// cli runs commands against the control plane, and Director which is created only for commands calling it
+type cli struct {
+	client    client.Client
+	namespace string
+	out       io.Writer
+	log       *logrus.Logger
+	opts      options
+	directors func() (*director.Registry, error)
+}
+
+func (c *cli) run(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "list":
+		return c.list(ctx, args)
+	case "describe":
+		return c.describe(ctx, args)
+	case controllers.OperationReregister, controllers.OperationReconfigure, controllers.OperationDeregister:
+		return c.requestOperation(ctx, command, args)
+	case "check":
+		return c.check(ctx, args)
+	case "export":
+		return c.export(ctx, args)
+	case "import":
+		return c.importMappings(args)
+	case "rebuild":
+		return c.rebuild(ctx, args)
+	default:
+		return errors.Errorf("unknown command %q, run cmctl -help for the list of commands", command)
+	}
+}
+
+// mappingRow is a mapping as listed by the list command
+type mappingRow struct {
+	KymaName      string `json:"kymaName"`
+	GlobalAccount string `json:"globalAccount"`
+	RuntimeID     string `json:"runtimeID"`
+	Director      string `json:"director"`
+	Registered    bool   `json:"registered"`
+	Configured    bool   `json:"configured"`
+	State         string `json:"state"`
+}
+
+func (c *cli) list(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	globalAccount := flags.String("global-account", "", "Lists mappings of the global account only.")
+	state := flags.String("state", "", "Lists mappings in the state only: Ready, Processing or Failed.")
+	output := flags.String("output", outputTable, "Output format: table or json.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	selector := map[string]string{}
+	if *globalAccount != "" {
+		selector[controllers.LabelGlobalAccountID] = *globalAccount
+	}
+	mappings := v1beta1.CompassManagerMappingList{}
+	err := c.client.List(ctx, &mappings, &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(selector),
+		Namespace:     c.namespace,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list Compass Manager Mappings")
+	}
+
+	rows := []mappingRow{}
+	for _, mapping := range mappings.Items {
+		if *state != "" && mapping.Status.State != *state {
+			continue
+		}
+		rows = append(rows, mappingRow{
+			KymaName:      mapping.Labels[controllers.LabelKymaName],
+			GlobalAccount: mapping.Labels[controllers.LabelGlobalAccountID],
+			RuntimeID:     mapping.Labels[controllers.LabelCompassID],
+			Director:      mapping.Labels[controllers.LabelCompassDirector],
+			Registered:    mapping.Status.Registered,
+			Configured:    mapping.Status.Configured,
+			State:         mapping.Status.State,
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].KymaName < rows[j].KymaName })
+
+	switch *output {
+	case outputJSON:
+		return writeJSON(c.out, rows)
+	case outputTable:
+		w := tabwriter.NewWriter(c.out, 0, 0, 3, ' ', 0) //nolint:mnd
+		fmt.Fprintln(w, "KYMA\tGLOBAL ACCOUNT\tRUNTIME ID\tDIRECTOR\tREGISTERED\tCONFIGURED\tSTATE")
+		for _, row := range rows {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\t%t\t%s\n", row.KymaName, row.GlobalAccount, orNone(row.RuntimeID), orNone(row.Director), row.Registered, row.Configured, orNone(row.State))
+		}
+		return w.Flush()
+	default:
+		return errors.Errorf("unknown output format %q", *output)
+	}
+}
+
+func (c *cli) describe(ctx context.Context, args []string) error {
+	kymaName, err := c.kymaArgument("describe", args)
+	if err != nil {
+		return err
+	}
+
+	cluster := controllers.NewControlPlaneInterface(c.client, c.log, false)
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0) //nolint:mnd
+	defer w.Flush()
+
+	fmt.Fprintf(w, "Kyma:\t%s\n", kymaName.Name)
+	kymaCR, err := cluster.GetKyma(kymaName)
+	switch {
+	case controllers.IsNotFound(err):
+		fmt.Fprintln(w, "  Kyma not found")
+	case err != nil:
+		return errors.Wrapf(err, "failed to get Kyma %s", kymaName.Name)
+	default:
+		writeLabels(w, "  ", kymaCR.Labels)
+	}
+
+	fmt.Fprintln(w, "Compass Manager Mapping:")
+	mapping, err := cluster.GetCompassMapping(kymaName)
+	if controllers.IsNotFound(err) {
+		fmt.Fprintln(w, "  Mapping not found")
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get Compass Manager Mapping of Kyma %s", kymaName.Name)
+	}
+	compassRuntimeID := mapping.Labels[controllers.LabelCompassID]
+	fmt.Fprintf(w, "  Runtime ID:\t%s\n", orNone(compassRuntimeID))
+	fmt.Fprintf(w, "  Director:\t%s\n", orNone(mapping.Labels[controllers.LabelCompassDirector]))
+	fmt.Fprintf(w, "  Tenant:\t%s\n", controllers.MappingTenant(mapping))
+	fmt.Fprintf(w, "  Registered:\t%t\n", mapping.Status.Registered)
+	fmt.Fprintf(w, "  Configured:\t%t\n", mapping.Status.Configured)
+	fmt.Fprintf(w, "  State:\t%s\n", orNone(mapping.Status.State))
+	for _, runtimeContext := range mapping.Status.RuntimeContexts {
+		fmt.Fprintf(w, "  Runtime context:\t%s (subaccount %s)\n", runtimeContext.ID, runtimeContext.Subaccount)
+	}
+	fmt.Fprintf(w, "  Formations:\t%s\n", orNone(strings.Join(mapping.Status.Formations, ", ")))
+
+	fmt.Fprintln(w, "Compass runtime:")
+	c.describeRuntime(w, mapping)
+
+	fmt.Fprintln(w, "Last errors:")
+	lastErrors, err := c.lastErrors(ctx, mapping)
+	if err != nil {
+		return err
+	}
+	if len(lastErrors) == 0 {
+		fmt.Fprintln(w, "  <none>")
+	}
+	for _, event := range lastErrors {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", lastObserved(event).Format(time.RFC3339), event.Reason, event.Note)
+	}
+	return nil
+}
+
+// describeRuntime writes the runtime of the mapping as read from Director, or why it isn't read
+func (c *cli) describeRuntime(w io.Writer, mapping v1beta1.CompassManagerMapping) {
+	compassRuntimeID := mapping.Labels[controllers.LabelCompassID]
+	if compassRuntimeID == "" {
+		fmt.Fprintln(w, "  Runtime isn't registered")
+		return
+	}
+
+	directors, err := c.directors()
+	if err != nil {
+		fmt.Fprintf(w, "  Runtime isn't read: %v\n", err)
+		return
+	}
+	endpoint, err := directors.Get(mapping.Labels[controllers.LabelCompassDirector])
+	if err != nil {
+		fmt.Fprintf(w, "  Runtime isn't read: %v\n", err)
+		return
+	}
+
+	runtime, appErr := director.WithKymaName(endpoint.Client, mapping.Labels[controllers.LabelKymaName]).GetRuntime(compassRuntimeID, controllers.MappingTenant(mapping))
+	if appErr != nil {
+		fmt.Fprintf(w, "  Failed to read runtime: %v\n", appErr)
+		return
+	}
+	fmt.Fprintf(w, "  Name:\t%s\n", runtime.Name)
+	if runtime.Status != nil {
+		fmt.Fprintf(w, "  Condition:\t%s\n", runtime.Status.Condition)
+	}
+	runtimeLabels := map[string]string{}
+	for key, value := range runtime.Labels {
+		runtimeLabels[key] = fmt.Sprint(value)
+	}
+	writeLabels(w, "  ", runtimeLabels)
+}
+
+// lastErrors returns the most recent Warning events the controller recorded for the mapping, newest first
+func (c *cli) lastErrors(ctx context.Context, mapping v1beta1.CompassManagerMapping) ([]eventsv1.Event, error) {
+	events := eventsv1.EventList{}
+	if err := c.client.List(ctx, &events, client.InNamespace(mapping.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list events")
+	}
+
+	var warnings []eventsv1.Event
+	for _, event := range events.Items {
+		if event.Type == corev1.EventTypeWarning && event.Regarding.Kind == "CompassManagerMapping" && event.Regarding.Name == mapping.Name {
+			warnings = append(warnings, event)
+		}
+	}
+	sort.Slice(warnings, func(i, j int) bool {
+		return lastObserved(warnings[i]).After(lastObserved(warnings[j]))
+	})
+	if len(warnings) > lastErrorsCount {
+		warnings = warnings[:lastErrorsCount]
+	}
+	return warnings, nil
+}
+
+// requestOperation requests the operation on the Kyma from Compass Manager, which performs it the way it performs the mutations of the API
+func (c *cli) requestOperation(ctx context.Context, operation string, args []string) error {
+	kymaName, err := c.kymaArgument(operation, args)
+	if err != nil {
+		return err
+	}
+
+	kymaCR := kyma.Kyma{}
+	if err := c.client.Get(ctx, kymaName, &kymaCR); err != nil {
+		return errors.Wrapf(err, "failed to get Kyma %s", kymaName.Name)
+	}
+	if !controllers.HasApplicationConnectorModule(kymaCR) {
+		return errors.Errorf("Kyma %s doesn't have the %s module, Compass Manager doesn't reconcile it", kymaName.Name, controllers.ApplicationConnectorModuleName)
+	}
+	if requested := kymaCR.Annotations[controllers.AnnotationCompassOperation]; requested != "" {
+		return errors.Errorf("%s is already requested for Kyma %s", requested, kymaName.Name)
+	}
+
+	patch := client.MergeFromWithOptions(kymaCR.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	if kymaCR.Annotations == nil {
+		kymaCR.Annotations = map[string]string{}
+	}
+	kymaCR.Annotations[controllers.AnnotationCompassOperation] = operation
+	if err := c.client.Patch(ctx, &kymaCR, patch); err != nil {
+		return errors.Wrapf(err, "failed to request %s for Kyma %s", operation, kymaName.Name)
+	}
+	fmt.Fprintf(c.out, "Kyma %s: %s requested, Compass Manager records its outcome as an event of the mapping\n", kymaName.Name, operation)
+	return nil
+}
+
+func (c *cli) check(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	output := flags.String("output", outputTable, "Output format: table, json or csv.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	directors, err := c.directors()
+	if err != nil {
+		return err
+	}
+
+	report, err := consistency.NewChecker(c.client, directors, c.namespace).Check(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch *output {
+	case outputJSON:
+		return writeJSON(c.out, report)
+	case outputCSV:
+		w := csv.NewWriter(c.out)
+		_ = w.Write([]string{"problem", "kymaName", "globalAccount", "runtimeID", "director", "details"})
+		for _, finding := range report.Findings {
+			_ = w.Write([]string{string(finding.Problem), finding.KymaName, finding.GlobalAccount, finding.RuntimeID, finding.Director, finding.Details})
+		}
+		w.Flush()
+		return w.Error()
+	case outputTable:
+		w := tabwriter.NewWriter(c.out, 0, 0, 3, ' ', 0) //nolint:mnd
+		fmt.Fprintln(w, "PROBLEM\tKYMA\tGLOBAL ACCOUNT\tRUNTIME ID\tDETAILS")
+		for _, finding := range report.Findings {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", finding.Problem, orNone(finding.KymaName), orNone(finding.GlobalAccount), orNone(finding.RuntimeID), finding.Details)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "Checked %d Kymas, %d mappings and %d runtimes, found %d inconsistencies\n",
+			report.CheckedKymas, report.CheckedMappings, report.CheckedRuntimes, len(report.Findings))
+		return nil
+	default:
+		return errors.Errorf("unknown output format %q", *output)
+	}
+}
+
+func (c *cli) export(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	file := flags.String("file", "", "File the document is written to, it's written to the output when not set.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	document, err := backup.Export(ctx, c.client, c.namespace, time.Now())
+	if err != nil {
+		return err
+	}
+	if *file == "" {
+		return writeJSON(c.out, document)
+	}
+
+	out, err := os.Create(*file)
+	if err != nil {
+		return errors.Wrap(err, "failed to create file of the document")
+	}
+	defer out.Close()
+	if err := writeJSON(out, document); err != nil {
+		return errors.Wrap(err, "failed to write the document")
+	}
+	fmt.Fprintf(c.out, "Exported %d mappings to %s\n", len(document.Mappings), *file)
+	return nil
+}
+
+func (c *cli) importMappings(args []string) error {
+	if len(args) != 1 {
+		return errors.New("import requires the file of the document")
+	}
+	in, err := os.Open(args[0])
+	if err != nil {
+		return errors.Wrap(err, "failed to open file of the document")
+	}
+	defer in.Close()
+
+	document, err := backup.Read(in)
+	if err != nil {
+		return err
+	}
+
+	outcomes := backup.Import(c.client, c.log, document, c.namespace)
+	failed, err := c.writeOutcomes(outcomes)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return errors.Errorf("failed to import %d of %d mappings", failed, len(outcomes))
+	}
+	return nil
+}
+
+func (c *cli) rebuild(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("rebuild", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "Reports runtimes matched to Kymas without changing mappings.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	directors, err := c.directors()
+	if err != nil {
+		return err
+	}
+
+	outcomes, err := backup.Rebuild(ctx, c.client, directors, c.log, backup.RebuildOptions{
+		Namespace:            c.namespace,
+		RegisterInSubaccount: c.opts.registerInSubaccount,
+		DryRun:               *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+	failed, err := c.writeOutcomes(outcomes)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return errors.Errorf("failed to rebuild %d of %d mappings", failed, len(outcomes))
+	}
+	return nil
+}
+
+// writeOutcomes writes the table of outcomes and returns the number of failed ones
+func (c *cli) writeOutcomes(outcomes []backup.Outcome) (int, error) {
+	w := tabwriter.NewWriter(c.out, 0, 0, 3, ' ', 0) //nolint:mnd
+	fmt.Fprintln(w, "KYMA\tACTION\tDETAILS")
+	failed := 0
+	for _, outcome := range outcomes {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", outcome.KymaName, outcome.Action, outcome.Details)
+		if outcome.Action == backup.Failed {
+			failed++
+		}
+	}
+	return failed, w.Flush()
+}
+
+func (c *cli) kymaArgument(command string, args []string) (types.NamespacedName, error) {
+	if len(args) != 1 {
+		return types.NamespacedName{}, errors.Errorf("%s requires the name of the Kyma", command)
+	}
+	return types.NamespacedName{Name: args[0], Namespace: c.namespace}, nil
+}
+
+func lastObserved(event eventsv1.Event) time.Time {
+	if event.Series != nil {
+		return event.Series.LastObservedTime.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.DeprecatedLastTimestamp.Time
+}
+
+func writeLabels(w io.Writer, indent string, labels map[string]string) {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s%s:\t%s\n", indent, key, labels[key])
+	}
+}
+
+func writeJSON(w io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/controllers"
+	"github.com/kyma-project/compass-manager/internal/director"
+	kyma "github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const namespace = "kcp-system"
+
+func TestCLI_List(t *testing.T) {
+	ready := newMapping("ready", "globalAccount", "runtime-id", "Ready")
+	failed := newMapping("failed", "globalAccount", "", "Failed")
+	other := newMapping("other", "otherAccount", "other-runtime-id", "Ready")
+
+	t.Run("should list mappings sorted by the Kyma name", func(t *testing.T) {
+		// given
+		c, out := newTestCLI(t, ready, failed, other)
+
+		// when
+		err := c.run(context.Background(), "list", nil)
+
+		// then
+		require.NoError(t, err)
+		lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+		require.Len(t, lines, 4)
+		assert.Contains(t, string(lines[0]), "KYMA")
+		assert.Contains(t, string(lines[1]), "failed")
+		assert.Contains(t, string(lines[1]), "<none>")
+		assert.Contains(t, string(lines[2]), "other")
+		assert.Contains(t, string(lines[3]), "ready")
+	})
+
+	t.Run("should filter mappings by the global account and the state as JSON", func(t *testing.T) {
+		// given
+		c, out := newTestCLI(t, ready, failed, other)
+
+		// when
+		err := c.run(context.Background(), "list", []string{"-global-account", "globalAccount", "-state", "Ready", "-output", "json"})
+
+		// then
+		require.NoError(t, err)
+		var rows []mappingRow
+		require.NoError(t, json.Unmarshal(out.Bytes(), &rows))
+		require.Len(t, rows, 1)
+		assert.Equal(t, "ready", rows[0].KymaName)
+		assert.Equal(t, "runtime-id", rows[0].RuntimeID)
+	})
+}
+
+func TestCLI_Describe(t *testing.T) {
+	t.Run("should describe the Kyma, its mapping and its last errors", func(t *testing.T) {
+		// given
+		kymaCR := &kyma.Kyma{ObjectMeta: metav1.ObjectMeta{
+			Name:      "kyma",
+			Namespace: namespace,
+			Labels:    map[string]string{controllers.LabelGlobalAccountID: "globalAccount"},
+		}}
+		mapping := newMapping("kyma", "globalAccount", "runtime-id", "Failed")
+		older := newWarningEvent("older", "kyma", controllers.ReasonRegistrationFailed, "first failure", time.Now().Add(-time.Hour))
+		newer := newWarningEvent("newer", "kyma", controllers.ReasonConfigurationFailed, "second failure", time.Now())
+		otherKyma := newWarningEvent("other", "other", controllers.ReasonRegistrationFailed, "failure of other Kyma", time.Now())
+		c, out := newTestCLI(t, kymaCR, mapping, older, newer, otherKyma)
+
+		// when
+		err := c.run(context.Background(), "describe", []string{"kyma"})
+
+		// then
+		require.NoError(t, err)
+		description := out.String()
+		assert.Contains(t, description, "globalAccount")
+		assert.Contains(t, description, "runtime-id")
+		assert.Contains(t, description, "Director isn't configured")
+		assert.Less(t, bytes.Index(out.Bytes(), []byte("second failure")), bytes.Index(out.Bytes(), []byte("first failure")))
+		assert.NotContains(t, description, "failure of other Kyma")
+	})
+
+	t.Run("should describe Kyma without mapping", func(t *testing.T) {
+		// given
+		kymaCR := &kyma.Kyma{ObjectMeta: metav1.ObjectMeta{Name: "kyma", Namespace: namespace}}
+		c, out := newTestCLI(t, kymaCR)
+
+		// when
+		err := c.run(context.Background(), "describe", []string{"kyma"})
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "Mapping not found")
+	})
+
+	t.Run("should fail without the name of the Kyma", func(t *testing.T) {
+		// given
+		c, _ := newTestCLI(t)
+
+		// when
+		err := c.run(context.Background(), "describe", nil)
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func TestCLI_RequestOperation(t *testing.T) {
+	newKyma := func(modules ...string) *kyma.Kyma {
+		kymaCR := &kyma.Kyma{ObjectMeta: metav1.ObjectMeta{Name: "kyma", Namespace: namespace}}
+		for _, module := range modules {
+			kymaCR.Status.Modules = append(kymaCR.Status.Modules, kyma.ModuleStatus{Name: module})
+		}
+		return kymaCR
+	}
+
+	t.Run("should request the operation from Compass Manager", func(t *testing.T) {
+		// given
+		c, out := newTestCLI(t, newKyma(controllers.ApplicationConnectorModuleName))
+
+		// when
+		err := c.run(context.Background(), "reregister", []string{"kyma"})
+
+		// then
+		require.NoError(t, err)
+		kymaCR := kyma.Kyma{}
+		require.NoError(t, c.client.Get(context.Background(), client.ObjectKey{Name: "kyma", Namespace: namespace}, &kymaCR))
+		assert.Equal(t, controllers.OperationReregister, kymaCR.Annotations[controllers.AnnotationCompassOperation])
+		assert.Contains(t, out.String(), "reregister requested")
+	})
+
+	t.Run("should refuse to request another operation before the first one is claimed", func(t *testing.T) {
+		// given
+		c, _ := newTestCLI(t, newKyma(controllers.ApplicationConnectorModuleName))
+		require.NoError(t, c.run(context.Background(), "deregister", []string{"kyma"}))
+
+		// when
+		err := c.run(context.Background(), "reconfigure", []string{"kyma"})
+
+		// then
+		require.ErrorContains(t, err, "deregister is already requested")
+	})
+
+	t.Run("should refuse to request the operation for Kyma which isn't reconciled", func(t *testing.T) {
+		// given
+		c, _ := newTestCLI(t, newKyma())
+
+		// when
+		err := c.run(context.Background(), "reconfigure", []string{"kyma"})
+
+		// then
+		require.ErrorContains(t, err, "doesn't have the application-connector module")
+	})
+}
+
+func TestCLI_UnknownCommand(t *testing.T) {
+	// given
+	c, _ := newTestCLI(t)
+
+	// when
+	err := c.run(context.Background(), "unknown", nil)
+
+	// then
+	require.Error(t, err)
+}
+
+func newTestCLI(t *testing.T, objects ...client.Object) (*cli, *bytes.Buffer) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	require.NoError(t, kyma.AddToScheme(scheme))
+
+	out := &bytes.Buffer{}
+	return &cli{
+		client:    ctrlfake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithStatusSubresource(&v1beta1.CompassManagerMapping{}).Build(),
+		namespace: namespace,
+		out:       out,
+		log:       logrus.New(),
+		directors: func() (*director.Registry, error) {
+			return nil, errors.New("Director isn't configured")
+		},
+	}, out
+}
+
+func newMapping(kymaName, globalAccount, compassID, state string) *v1beta1.CompassManagerMapping {
+	labels := map[string]string{
+		controllers.LabelKymaName:        kymaName,
+		controllers.LabelGlobalAccountID: globalAccount,
+	}
+	if compassID != "" {
+		labels[controllers.LabelCompassID] = compassID
+	}
+	return &v1beta1.CompassManagerMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kymaName,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Status: v1beta1.CompassManagerMappingStatus{State: state},
+	}
+}
+
+func newWarningEvent(name, mappingName, reason, note string, eventTime time.Time) *eventsv1.Event {
+	return &eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		EventTime:  metav1.NewMicroTime(eventTime),
+		Type:       corev1.EventTypeWarning,
+		Reason:     reason,
+		Note:       note,
+		Regarding: corev1.ObjectReference{
+			Kind:      "CompassManagerMapping",
+			Name:      mappingName,
+			Namespace: namespace,
+		},
+	}
+}
@@ -0,0 +1,161 @@
+// Command cmctl inspects and operates Compass Manager Mappings of Kymas in the control plane, and their runtimes in Compass.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/internal/director"
+	"github.com/kyma-project/compass-manager/internal/directorconfig"
+	kyma "github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/vrischmann/envconfig"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const usage = `Usage: cmctl [flags] <command> [arguments]
+
+Commands:
+  list [-global-account ID] [-state STATE] [-output table|json]
+        Lists Compass Manager Mappings with their state
+  describe KYMA
+        Describes the Kyma, its mapping, its runtime in Compass, and the last errors of its reconciliation
+  reregister KYMA
+        Requests Compass Manager to deregister the runtime of the Kyma, register a new one and configure the Compass Runtime Agent
+  reconfigure KYMA
+        Requests Compass Manager to write the configuration of the Compass Runtime Agent again with a new one-time token
+  deregister KYMA
+        Requests Compass Manager to delete the runtime of the Kyma from Compass and reset its mapping
+  check [-output table|json|csv]
+        Reports Kymas without mappings, mappings without runtimes, orphaned runtimes, label mismatches
+        and duplicate registrations, joining Kymas and mappings with runtimes registered in Director
+  export [-file FILE]
+        Exports mappings of Kymas to their Compass runtimes to a versioned JSON document
+  import FILE
+        Creates mappings of the exported document which don't exist, without registering runtimes again
+  rebuild [-dry-run]
+        Recreates missing mappings from runtimes registered by compass-manager in Director, matched to Kymas
+        by the broker instance ID or the shoot name; ambiguous matches are reported and left unchanged
+
+Commands calling Director read the Directors from the APP_ envs of Compass Manager, e.g. APP_DIRECTORS_CONFIG_PATH,
+APP_DIRECTOR_URL, APP_DIRECTOR_AUTH_MODE and APP_DIRECTOR_OAUTH_PATH. OAuth credentials kept in a Secret are read
+from the control plane.
+
+Flags:
+`
+
+type options struct {
+	namespace            string
+	registerInSubaccount bool
+}
+
+func main() {
+	opts := options{}
+	flag.StringVar(&opts.namespace, "namespace", "kcp-system", "Namespace of Kymas and Compass Manager Mappings.")
+	flag.BoolVar(&opts.registerInSubaccount, "register-in-subaccount", false, "Registers runtimes under the subaccount tenant of the Kyma, as compass-manager configured with APP_REGISTER_IN_SUBACCOUNT.")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2) //nolint:mnd
+	}
+
+	// Director client logs with the standard logger, only warnings are shown not to mix logs into the output
+	log := logrus.StandardLogger()
+	log.SetOutput(os.Stderr)
+	log.SetLevel(logrus.WarnLevel)
+
+	kubectl, err := newKubeClient()
+	if err != nil {
+		log.Fatalf("Failed to create client of the control plane: %v", err)
+	}
+
+	c := &cli{
+		client:    kubectl,
+		namespace: opts.namespace,
+		out:       os.Stdout,
+		log:       log,
+		opts:      opts,
+		directors: func() (*director.Registry, error) {
+			return newDirectorRegistry(context.Background(), kubectl)
+		},
+	}
+	if err := c.run(context.Background(), flag.Arg(0), flag.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func newKubeClient() (client.Client, error) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := v1beta1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := kyma.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	return client.New(config, client.Options{Scheme: scheme})
+}
+
+// newDirectorRegistry creates the registry of the Directors configured with the envs of Compass Manager
+func newDirectorRegistry(ctx context.Context, kubectl client.Reader) (*director.Registry, error) {
+	cfg := directorconfig.Config{}
+	if err := envconfig.InitWithPrefix(&cfg, "APP"); err != nil {
+		return nil, errors.Wrap(err, "failed to read Directors config from the envs")
+	}
+	directors, err := directorconfig.Load(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return directors.NewRegistry(cfg, func(name string, cfg directorconfig.Config) (director.Endpoint, error) {
+		directorClient, err := newDirectorClient(ctx, kubectl, cfg)
+		if err != nil {
+			return director.Endpoint{}, err
+		}
+		return director.Endpoint{Name: name, ConnectorURLPattern: cfg.ConnectorURLPattern, Client: directorClient}, nil
+	})
+}
+
+// newDirectorClient creates the client of the Director, OAuth credentials kept in a Secret are read once, as cmctl doesn't run long enough to see them rotated
+func newDirectorClient(ctx context.Context, kubectl client.Reader, cfg directorconfig.Config) (director.Client, error) {
+	opts := []director.Option{director.WithBatchSize(cfg.DirectorBatchSize)}
+	if cfg.DirectorAuthMode != directorconfig.AuthModeOAuth {
+		return directorconfig.NewClient(cfg, nil, nil, nil, opts...)
+	}
+
+	oauthOpts, err := directorconfig.NewOAuthOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DirectorOAuthSecretName == "" {
+		return directorconfig.NewClient(cfg, nil, nil, oauthOpts, opts...)
+	}
+
+	secret := corev1.Secret{}
+	if err := kubectl.Get(ctx, types.NamespacedName{Name: cfg.DirectorOAuthSecretName, Namespace: cfg.DirectorOAuthSecretNamespace}, &secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to get Secret %s/%s with OAuth credentials of Director", cfg.DirectorOAuthSecretNamespace, cfg.DirectorOAuthSecretName)
+	}
+	return directorconfig.NewOAuthClient(cfg, secret.Data[cfg.DirectorOAuthSecretKey], nil, nil, oauthOpts, opts...)
+}
@@ -0,0 +1,76 @@
+// Command fake-director serves the fake Director and OAuth2 tokens endpoint, for running compass-manager locally without a Compass landscape.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/compass-manager/internal/director/fake"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	readHeaderTimeout = 10 * time.Second
+	oauthFileMode     = 0o600
+)
+
+func main() {
+	var address, clientID, clientSecret, connectorURL, oauthPath string
+	var disableAuth bool
+	flag.StringVar(&address, "address", "localhost:8090", "The address the fake Director and tokens endpoint bind to.")
+	flag.StringVar(&clientID, "client-id", fake.DefaultClientID, "Client ID accepted by the tokens endpoint.")
+	flag.StringVar(&clientSecret, "client-secret", fake.DefaultClientSecret, "Client secret accepted by the tokens endpoint.")
+	flag.StringVar(&connectorURL, "connector-url", "", "Connector URL returned with one-time tokens. Defaults to the Connector path on the address.")
+	flag.StringVar(&oauthPath, "oauth-file", "", "If set, the OAuth credentials are written to the file in the director.yaml format.")
+	flag.BoolVar(&disableAuth, "disable-auth", false, "Accept Director requests without a token.")
+	flag.Parse()
+
+	log := logrus.New()
+
+	baseURL := "http://" + address
+	if connectorURL == "" {
+		connectorURL = baseURL + fake.ConnectorPath
+	}
+
+	director, tokens := fake.NewComponents(fake.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		ConnectorURL: connectorURL,
+		DisableAuth:  disableAuth,
+	})
+
+	if oauthPath != "" {
+		if err := writeOAuthFile(oauthPath, clientID, clientSecret, baseURL+fake.TokensPath); err != nil {
+			log.Fatalf("Failed to write OAuth file: %v", err)
+		}
+	}
+
+	log.Infof("Serving fake Director at %s", baseURL+fake.DirectorPath)
+	log.Infof("Run compass-manager with APP_DIRECTOR_URL=%s APP_CONNECTOR_URL_PATTERN=%s APP_DIRECTOR_OAUTH_PATH=%s",
+		baseURL+fake.DirectorPath, strings.TrimPrefix(connectorURL, "http://"), oauthPathOrPlaceholder(oauthPath))
+
+	server := &http.Server{
+		Addr:              address,
+		Handler:           fake.NewHandler(director, tokens),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	if err := server.ListenAndServe(); err != nil {
+		log.Fatalf("Fake Director stopped: %v", err)
+	}
+}
+
+func writeOAuthFile(path, clientID, clientSecret, tokensEndpoint string) error {
+	content := fmt.Sprintf("data:\n  client_id: %q\n  client_secret: %q\n  tokens_endpoint: %q\n", clientID, clientSecret, tokensEndpoint)
+	return os.WriteFile(path, []byte(content), oauthFileMode)
+}
+
+func oauthPathOrPlaceholder(path string) string {
+	if path == "" {
+		return "<director.yaml written with -oauth-file>"
+	}
+	return path
+}
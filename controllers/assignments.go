@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/internal/director"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AssignmentsReader periodically reads formations and applications Compass assigned to registered runtimes,
+// and summarises them in the status of their Compass Manager Mappings
+type AssignmentsReader struct {
+	Client    Client
+	Log       *log.Logger
+	directors *director.Registry
+	interval  time.Duration
+}
+
+func NewAssignmentsReader(c Client, log *log.Logger, directors *director.Registry, interval time.Duration) *AssignmentsReader {
+	return &AssignmentsReader{
+		Client:    c,
+		Log:       log,
+		directors: directors,
+		interval:  interval,
+	}
+}
+
+// Start reads the assignments every interval until the context is done, it implements manager.Runnable
+func (r *AssignmentsReader) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.ReadAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ReadAll updates the assignments of all mappings with a registered runtime, a failure for one mapping doesn't stop the others.
+// Assignments of all runtimes of a Director are read in batches.
+func (r *AssignmentsReader) ReadAll(ctx context.Context) {
+	mappings := v1beta1.CompassManagerMappingList{}
+	if err := r.Client.List(ctx, &mappings); err != nil {
+		r.Log.Warnf("Failed to list Compass Manager Mappings to read Compass assignments: %v", err)
+		return
+	}
+
+	operations := map[string][]director.BatchOperation{}
+	mappingsByDirector := map[string][]*v1beta1.CompassManagerMapping{}
+	for i := range mappings.Items {
+		mapping := &mappings.Items[i]
+		compassRuntimeID := mapping.Labels[LabelCompassID]
+		if compassRuntimeID == "" || !mapping.Status.Registered {
+			continue
+		}
+
+		directorName := mapping.Labels[LabelCompassDirector]
+		tenant := MappingTenant(*mapping)
+		operations[directorName] = append(operations[directorName],
+			director.GetRuntimeFormationsOperation(compassRuntimeID, tenant),
+			director.GetRuntimeApplicationsOperation(compassRuntimeID, tenant))
+		mappingsByDirector[directorName] = append(mappingsByDirector[directorName], mapping)
+	}
+
+	for directorName, directorMappings := range mappingsByDirector {
+		endpoint, err := r.directors.Get(directorName)
+		if err != nil {
+			r.Log.Warnf("Failed to read Compass assignments of %d Runtimes: %v", len(directorMappings), err)
+			continue
+		}
+
+		results := director.ExecuteBatch(endpoint.Client, operations[directorName])
+		for i, mapping := range directorMappings {
+			assignments, err := toAssignments(results[2*i], results[2*i+1])
+			if err != nil {
+				r.Log.Warnf("Failed to read Compass assignments of Runtime %s for Kyma resource %s: %v", mapping.Labels[LabelCompassID], mapping.Name, err)
+				continue
+			}
+
+			mapping.Status.Assignments = &assignments
+			if err := r.Client.Status().Update(ctx, mapping); err != nil {
+				r.Log.Warnf("Failed to update Compass assignments in Compass Mapping Status for %s: %v", mapping.Name, err)
+			}
+		}
+	}
+}
+
+// toAssignments summarises results of GetRuntimeFormationsOperation and GetRuntimeApplicationsOperation of a runtime
+func toAssignments(formations, applications director.BatchResult) (v1beta1.CompassAssignments, error) {
+	if formations.Err != nil {
+		return v1beta1.CompassAssignments{}, errors.Wrap(formations.Err, "failed to get formations")
+	}
+	if applications.Err != nil {
+		return v1beta1.CompassAssignments{}, errors.Wrap(applications.Err, "failed to get applications")
+	}
+
+	assignments := v1beta1.CompassAssignments{
+		FormationCount:   len(formations.Formations),
+		ApplicationCount: applications.Applications.TotalCount,
+		LastReadTime:     metav1.Now(),
+	}
+	for _, formation := range formations.Formations {
+		assignments.Formations = append(assignments.Formations, formation.Name)
+	}
+	for _, application := range applications.Applications.Data {
+		if application != nil {
+			assignments.Applications = append(assignments.Applications, application.Name)
+		}
+	}
+	return assignments, nil
+}
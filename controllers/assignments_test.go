@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/internal/director"
+	"github.com/kyma-project/compass-manager/internal/director/fake"
+	"github.com/kyma-project/compass-manager/internal/graphql"
+	"github.com/kyma-project/compass-manager/internal/oauth"
+	"github.com/kyma-project/compass-manager/pkg/gqlschema"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAssignmentsReader(t *testing.T) {
+	t.Run("should summarise formations and applications of registered runtimes in mapping statuses", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+
+		oauthClient := oauth.NewOauthClient(http.DefaultClient, fake.DefaultClientID, fake.DefaultClientSecret, server.TokensEndpoint())
+		directorClient := director.NewDirectorClient(graphql.NewGraphQLClient(server.DirectorURL(), false, false), oauthClient)
+
+		compassID, err := directorClient.CreateRuntime(&gqlschema.RuntimeInput{Name: "runtime"}, "subaccount")
+		require.NoError(t, err)
+		server.Director.AddFormation("subaccount", "formation")
+		require.NoError(t, directorClient.AssignFormation(compassID, "formation", "subaccount"))
+		server.Director.AddApplication("subaccount", "application", "formation")
+		unassignedID, err := directorClient.CreateRuntime(&gqlschema.RuntimeInput{Name: "unassigned"}, "subaccount")
+		require.NoError(t, err)
+
+		registered := newAssignmentsMapping("registered", compassID, true)
+		registered.Labels[LabelCompassTenant] = "subaccount"
+		unassigned := newAssignmentsMapping("unassigned", unassignedID, true)
+		unassigned.Labels[LabelCompassTenant] = "subaccount"
+		notRegistered := newAssignmentsMapping("not-registered", "", false)
+
+		scheme := runtime.NewScheme()
+		require.NoError(t, v1beta1.AddToScheme(scheme))
+		kubectl := ctrlfake.NewClientBuilder().WithScheme(scheme).WithObjects(registered, unassigned, notRegistered).WithStatusSubresource(registered, unassigned, notRegistered).Build()
+
+		reader := NewAssignmentsReader(kubectl, logrus.New(), director.NewSingleDirectorRegistry(directorClient, fake.ConnectorPath), 0)
+
+		// when
+		reader.ReadAll(context.Background())
+
+		// then
+		stored := v1beta1.CompassManagerMapping{}
+		require.NoError(t, kubectl.Get(context.Background(), types.NamespacedName{Name: "registered", Namespace: "kcp-system"}, &stored))
+		require.NotNil(t, stored.Status.Assignments)
+		assert.Equal(t, 1, stored.Status.Assignments.FormationCount)
+		assert.Equal(t, []string{"formation"}, stored.Status.Assignments.Formations)
+		assert.Equal(t, 1, stored.Status.Assignments.ApplicationCount)
+		assert.Equal(t, []string{"application"}, stored.Status.Assignments.Applications)
+		assert.False(t, stored.Status.Assignments.LastReadTime.IsZero())
+
+		storedUnassigned := v1beta1.CompassManagerMapping{}
+		require.NoError(t, kubectl.Get(context.Background(), types.NamespacedName{Name: "unassigned", Namespace: "kcp-system"}, &storedUnassigned))
+		require.NotNil(t, storedUnassigned.Status.Assignments)
+		assert.Zero(t, storedUnassigned.Status.Assignments.FormationCount)
+		assert.Zero(t, storedUnassigned.Status.Assignments.ApplicationCount)
+
+		require.NoError(t, kubectl.Get(context.Background(), types.NamespacedName{Name: "not-registered", Namespace: "kcp-system"}, &stored))
+		assert.Nil(t, stored.Status.Assignments)
+	})
+}
+
+func newAssignmentsMapping(name, compassID string, registered bool) *v1beta1.CompassManagerMapping {
+	return &v1beta1.CompassManagerMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kcp-system",
+			Labels: map[string]string{
+				LabelKymaName:        name,
+				LabelCompassID:       compassID,
+				LabelGlobalAccountID: "globalAccount",
+			},
+		},
+		Status: v1beta1.CompassManagerMappingStatus{Registered: registered},
+	}
+}
@@ -9,14 +9,17 @@ import (
 	"github.com/kyma-project/compass-manager/api/v1beta1"
 	"github.com/kyma-project/compass-manager/controllers/metrics"
 	s "github.com/kyma-project/compass-manager/controllers/status"
+	"github.com/kyma-project/compass-manager/internal/apperrors"
 	kyma "github.com/kyma-project/lifecycle-manager/api/v1beta2"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -38,6 +41,25 @@ const (
 	LabelShootName        = "kyma-project.io/shoot-name"
 	LabelSubaccountID     = "kyma-project.io/subaccount-id"
 	LabelDryRun           = "kyma-project.io/cm-dry-run"
+	LabelCompassDirector  = "kyma-project.io/compass-director"
+	// LabelCompassTenant is the tenant the Runtime is registered in, set on the mapping only when it isn't the Global Account
+	LabelCompassTenant = "kyma-project.io/compass-tenant"
+	// AnnotationCompassSubaccounts lists, separated with commas, additional subaccounts of the Kyma which get a context of its Runtime in Compass
+	AnnotationCompassSubaccounts = "kyma-project.io/compass-subaccounts"
+	// AnnotationCompassFormations lists, separated with commas, Compass formations the Runtime is assigned to in addition to the default ones
+	AnnotationCompassFormations = "kyma-project.io/compass-formations"
+	// AnnotationCompassOperation requests an operation on the Runtime from the controller: reregister, reconfigure or deregister.
+	// The controller removes the annotation before it performs the operation.
+	AnnotationCompassOperation = "kyma-project.io/compass-operation"
+
+	// Labels of runtimes registered in Compass, set from labels of the Kyma
+	RuntimeLabelManagedBy        = "director_connection_managed_by"
+	RuntimeLabelBrokerInstanceID = "broker_instance_id"
+	RuntimeLabelShootName        = "gardenerClusterName"
+	RuntimeLabelSubaccountID     = "global_subaccount_id"
+	RuntimeLabelGlobalAccountID  = "global_account_id"
+	RuntimeLabelBrokerPlanID     = "broker_plan_id"
+	RuntimeLabelBrokerPlanName   = "broker_plan_name"
 
 	ApplicationConnectorModuleName = "application-connector"
 	// KubeconfigKey is the name of the key in the secret storing cluster credentials.
@@ -48,14 +70,19 @@ const (
 var errNotFound = errors.New("resource not found")
 
 type DirectorError struct {
-	message error
+	message  error
+	director string
 }
 
 func (e *DirectorError) Error() string {
 	return fmt.Sprintf("error from director: %s", e.message)
 }
 
-//+kubebuilder:rbac:groups=operator.kyma-project.io,resources=kymas,verbs=get;list;watch,namespace=kcp-system
+func (e *DirectorError) Unwrap() error {
+	return e.message
+}
+
+//+kubebuilder:rbac:groups=operator.kyma-project.io,resources=kymas,verbs=get;list;watch;update,namespace=kcp-system
 //+kubebuilder:rbac:groups=operator.kyma-project.io,resources=compassmanagermappings,verbs=create;get;list;delete;watch;update,namespace=kcp-system
 //+kubebuilder:rbac:groups=operator.kyma-project.io,resources=compassmanagermappings/status,verbs=get;update;patch,namespace=kcp-system
 //+kubebuilder:rbac:groups=operator.kyma-project.io,resources=compassmanagermappings/finalizers,verbs=update;get,namespace=kcp-system
@@ -64,15 +91,30 @@ func (e *DirectorError) Error() string {
 //go:generate mockery --name=Configurator
 type Configurator interface {
 	// ConfigureCompassRuntimeAgent creates a secret in the Runtime that is used by the Compass Runtime Agent. It must be idempotent.
-	ConfigureCompassRuntimeAgent(kubeconfig []byte, compassRuntimeID, globalAccount string) error
+	ConfigureCompassRuntimeAgent(kymaName string, kubeconfig []byte, compassRuntimeID, globalAccount, director string) error
 }
 
 //go:generate mockery --name=Registrator
 type Registrator interface {
-	// RegisterInCompass creates Runtime in the Compass system. It must be idempotent.
-	RegisterInCompass(compassRuntimeLabels map[string]interface{}) (string, error)
+	// RegisterInCompass creates Runtime in the Compass system under the tenant. It must be idempotent.
+	RegisterInCompass(kymaName string, compassRuntimeLabels map[string]interface{}, tenant, director string) (string, error)
 	// DeregisterFromCompass deletes Runtime from Compass system
-	DeregisterFromCompass(compassID, globalAccount string) error
+	DeregisterFromCompass(kymaName, compassID, globalAccount, director string) error
+	// CreateRuntimeContext creates a context of the Runtime for the subaccount and returns its ID
+	CreateRuntimeContext(kymaName, compassID, subaccount, tenant, director string) (string, error)
+	// DeleteRuntimeContext deletes the context of the Runtime, a context which doesn't exist is not an error
+	DeleteRuntimeContext(kymaName, runtimeContextID, tenant, director string) error
+	// AssignFormation assigns the Runtime to the formation, which must exist in the tenant
+	AssignFormation(kymaName, compassID, formation, tenant, director string) error
+	// UnassignFormation unassigns the Runtime from the formation, a formation which doesn't exist is not an error
+	UnassignFormation(kymaName, compassID, formation, tenant, director string) error
+}
+
+// Directors selects the Director a Kyma runtime is registered in, and tells when calls to it, short-circuited after consecutive failures, are allowed again.
+// An empty Director name stands for the default Director.
+type Directors interface {
+	SelectDirector(kymaLabels map[string]string, globalAccount string) string
+	NextProbe(director string) time.Time
 }
 
 type Client interface {
@@ -94,8 +136,13 @@ type CompassManagerReconciler struct {
 	requeueTime              time.Duration
 	requeueTimeForKubeconfig time.Duration
 	enabledRegistration      bool
+	registerInSubaccount     bool
+	defaultFormations        []string
 	cluster                  *ControlPlaneInterface
 	metrics                  metrics.Metrics
+	directors                Directors
+	recorder                 events.EventRecorder
+	locks                    kymaLocks
 }
 
 func NewCompassManagerReconciler(
@@ -106,8 +153,11 @@ func NewCompassManagerReconciler(
 	requeueTime time.Duration,
 	requeueTimeForKubeconfig time.Duration,
 	enabledRegistration bool,
+	registerInSubaccount bool,
+	defaultFormations []string,
 	dryRun bool,
 	metrics metrics.Metrics,
+	directors Directors,
 ) *CompassManagerReconciler {
 	return &CompassManagerReconciler{
 		Client:                   mgr.GetClient(),
@@ -118,22 +168,41 @@ func NewCompassManagerReconciler(
 		requeueTime:              requeueTime,
 		requeueTimeForKubeconfig: requeueTimeForKubeconfig,
 		enabledRegistration:      enabledRegistration,
+		registerInSubaccount:     registerInSubaccount,
+		defaultFormations:        defaultFormations,
 		cluster:                  NewControlPlaneInterface(mgr.GetClient(), log, dryRun),
 		metrics:                  metrics,
+		directors:                directors,
+		recorder:                 mgr.GetEventRecorder("compass-manager"),
 	}
 }
 
-func (cm *CompassManagerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) { // nolint:revive
+// Reconcile waits for operations performed on the Kyma outside of the workqueue, see Operations, so that they don't register runtimes concurrently.
+// An operation requested with AnnotationCompassOperation is performed instead of the reconciliation.
+func (cm *CompassManagerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	defer cm.locks.lock(req.NamespacedName)()
+
+	operation, err := cm.claimRequestedOperation(req.NamespacedName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if operation != "" {
+		return cm.performRequestedOperation(req.NamespacedName, operation)
+	}
+	return cm.reconcile(ctx, req)
+}
+
+func (cm *CompassManagerReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) { // nolint:revive
 	cm.Log.Infof("Reconciliation triggered for Kyma Resource %s", req.Name)
 
 	kymaCR, err := cm.cluster.GetKyma(req.NamespacedName)
 
 	// KymaCR doesn't exist - reconcile was triggered by deletion
-	if isNotFound(err) {
+	if IsNotFound(err) {
 		delErr := cm.handleKymaDeletion(req.NamespacedName)
 		var directorError *DirectorError
 		if errors.As(delErr, &directorError) {
-			return ctrl.Result{RequeueAfter: cm.requeueTime}, nil
+			return ctrl.Result{RequeueAfter: cm.requeueTimeForDirectorError(delErr, directorError.director)}, nil
 		}
 
 		if delErr != nil {
@@ -150,7 +219,7 @@ func (cm *CompassManagerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	kubeconfig, err := cm.cluster.GetKubeconfig(req.NamespacedName)
 
 	// Kubeconfig doesn't exist / is empty
-	if isNotFound(err) || len(kubeconfig) == 0 {
+	if IsNotFound(err) || len(kubeconfig) == 0 {
 		cm.Log.Infof("Kubeconfig for Kyma resource %s not available. Next attempt in %s", req.Name, cm.requeueTimeForKubeconfig)
 		return ctrl.Result{RequeueAfter: cm.requeueTimeForKubeconfig}, nil
 	}
@@ -162,7 +231,7 @@ func (cm *CompassManagerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	// Kyma exists and has a kubeconfig, get the compass mapping
 	compassRuntimeID, runtimeIDErr := cm.cluster.GetCompassRuntimeID(req.NamespacedName)
 
-	if runtimeIDErr != nil && !isNotFound(runtimeIDErr) {
+	if runtimeIDErr != nil && !IsNotFound(runtimeIDErr) {
 		return ctrl.Result{}, errors.Wrapf(runtimeIDErr, "failed to obtain Compass Mapping for Kyma resource %s", req.Name)
 	}
 
@@ -172,7 +241,7 @@ func (cm *CompassManagerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 
 	/// Part 1 - If compass mapping doesn't exist let's create it and requeue
-	if isNotFound(runtimeIDErr) {
+	if IsNotFound(runtimeIDErr) {
 		return cm.makeNewCompassMappingAndRequeue(req.NamespacedName)
 	}
 
@@ -194,7 +263,7 @@ func (cm *CompassManagerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	// From this point we will always deal with Compass Manager Mapping for KymaCR
 	// Part 2 - If compass mapping doesn't contain valid runtime ID - register runtime and requeue
 	if len(compassRuntimeID) == 0 && cm.enabledRegistration {
-		return cm.registerRuntimeInCompassAndRequeue(req.NamespacedName, kymaCR.Labels)
+		return cm.registerRuntimeInCompassAndRequeue(req.NamespacedName, kymaCR.Labels, cm.registrationTenant(kymaCR.Labels, globalAccount))
 	}
 
 	if status&(s.Registered|s.Processing) != s.Registered|s.Processing {
@@ -203,14 +272,30 @@ func (cm *CompassManagerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 
 	// From that moment we will always deal with Compass Manager Mapping with ID of registered Runtime, or feature flag is disabled
-	return cm.configureRuntimeAndSetMappingStatus(req.NamespacedName, kubeconfig, compassRuntimeID, globalAccount)
+	tenant := MappingTenant(mapping)
+	director := mapping.Labels[LabelCompassDirector]
+
+	// Part 3 - Create and delete contexts of the Runtime for additional subaccounts of the Kyma
+	if len(compassRuntimeID) != 0 && cm.enabledRegistration {
+		if err := cm.updateRuntimeContexts(req.NamespacedName, kymaCR.Annotations, mapping, compassRuntimeID, tenant, director); err != nil {
+			return cm.failRuntimeContextsAndRequeue(req.NamespacedName, err, director)
+		}
+
+		// Part 4 - Assign the Runtime to formations of the policy and unassign it from formations removed from it
+		if err := cm.updateFormations(req.NamespacedName, kymaCR.Annotations, mapping, compassRuntimeID, tenant, director); err != nil {
+			return cm.failFormationsAndRequeue(req.NamespacedName, err, director)
+		}
+	}
+
+	return cm.configureRuntimeAndSetMappingStatus(req.NamespacedName, kubeconfig, compassRuntimeID, tenant, director)
 }
 
 func (cm *CompassManagerReconciler) handleKymaDeletion(name types.NamespacedName) error {
 	compass, err := cm.cluster.GetCompassMapping(name)
 
-	if isNotFound(err) {
+	if IsNotFound(err) {
 		cm.Log.Warnf("Runtime %s has no compass mapping, nothing to delete", name)
+		cm.metrics.DeleteKyma(name.Name)
 		return nil
 	}
 
@@ -222,17 +307,27 @@ func (cm *CompassManagerReconciler) handleKymaDeletion(name types.NamespacedName
 	runtimeIDFromMapping, ok := compass.Labels[LabelCompassID]
 
 	if ok && runtimeIDFromMapping != "" {
-		globalAccountFromMapping, ok := compass.Labels[LabelGlobalAccountID]
+		_, ok := compass.Labels[LabelGlobalAccountID]
 		if !ok {
 			cm.Log.Warnf("Compass Mapping for %s has no Global Account", name.Name)
 			return errors.Errorf("Compass Mapping for %s has no Global Account", name.Name)
 		}
 
+		directorFromMapping := compass.Labels[LabelCompassDirector]
+		err = cm.unassignAllFormations(name, compass, runtimeIDFromMapping, directorFromMapping)
+		if err != nil {
+			cm.Log.Warnf("Failed to unassign Runtime from formations for Kyma Resource %s: %v", name.Name, err)
+			cm.recordFailure(name, ReasonDeregistrationFailed, ActionDeregister, err)
+			return errors.Wrap(&DirectorError{message: err, director: directorFromMapping}, "failed to unassign Runtime from formations")
+		}
+
+		// contexts of the Runtime are deleted by Compass together with the Runtime
 		cm.Log.Infof("Runtime deregistration in Compass for Kyma Resource %s", name.Name)
-		err = cm.Registrator.DeregisterFromCompass(runtimeIDFromMapping, globalAccountFromMapping)
+		err = cm.Registrator.DeregisterFromCompass(name.Name, runtimeIDFromMapping, MappingTenant(compass), directorFromMapping)
 		if err != nil {
 			cm.Log.Warnf("Failed to deregister Runtime from Compass for Kyma Resource %s: %v", name.Name, err)
-			return errors.Wrap(&DirectorError{message: err}, "failed to deregister Runtime from Compass")
+			cm.recordFailure(name, ReasonDeregistrationFailed, ActionDeregister, err)
+			return errors.Wrap(&DirectorError{message: err, director: directorFromMapping}, "failed to deregister Runtime from Compass")
 		}
 		cm.metrics.IncUnregister(name.Name)
 		cm.metrics.UpdateState(name.Name, s.Empty)
@@ -246,6 +341,7 @@ func (cm *CompassManagerReconciler) handleKymaDeletion(name types.NamespacedName
 	if err != nil {
 		return errors.Wrap(err, "failed to delete Compass Mapping")
 	}
+	cm.metrics.DeleteKyma(name.Name)
 	return nil
 }
 
@@ -261,19 +357,25 @@ func (cm *CompassManagerReconciler) makeNewCompassMappingAndRequeue(kymaName typ
 	return ctrl.Result{RequeueAfter: cm.requeueTime}, nil
 }
 
-func (cm *CompassManagerReconciler) registerRuntimeInCompassAndRequeue(kymaName types.NamespacedName, kymaLabels map[string]string) (ctrl.Result, error) {
+func (cm *CompassManagerReconciler) registerRuntimeInCompassAndRequeue(kymaName types.NamespacedName, kymaLabels map[string]string, tenant string) (ctrl.Result, error) {
+	director := cm.selectDirector(kymaLabels, kymaLabels[LabelGlobalAccountID])
 	cm.Log.Infof("Attempting to register runtime in compass for Kyma resource %s.", kymaName.Name)
 
-	newCompassRuntimeID, regError := cm.Registrator.RegisterInCompass(createCompassRuntimeLabels(kymaLabels))
+	newCompassRuntimeID, regError := cm.Registrator.RegisterInCompass(kymaName.Name, CompassRuntimeLabels(kymaLabels), tenant, director)
 
 	if regError != nil {
 		cm.Log.Errorf("Failed attempt to register runtime for Kyma resource: %s: %v", kymaName.Name, regError)
+		cm.recordFailure(kymaName, ReasonRegistrationFailed, ActionRegister, regError)
 		statErr := cm.cluster.SetCompassMappingStatus(kymaName, s.Failed)
 
 		if statErr != nil {
 			return ctrl.Result{Requeue: true}, errors.Wrap(statErr, "failed to set Compass Manager Status after failed attempt to register runtime")
 		}
 
+		if isDirectorCircuitOpen(regError) {
+			return ctrl.Result{RequeueAfter: cm.requeueTimeForDirectorError(regError, director)}, nil
+		}
+
 		return ctrl.Result{Requeue: true}, errors.Wrapf(regError, "failed attempt to register runtime for Kyma resource: %s", kymaName.Name)
 	}
 
@@ -281,7 +383,7 @@ func (cm *CompassManagerReconciler) registerRuntimeInCompassAndRequeue(kymaName
 	cm.metrics.UpdateState(kymaName.Name, s.Registered|s.Processing)
 
 	cm.Log.Infof("Runtime %s registered in Compass", newCompassRuntimeID)
-	cmerr := cm.cluster.UpsertCompassMapping(kymaName, newCompassRuntimeID)
+	cmerr := cm.cluster.UpsertCompassMapping(kymaName, newCompassRuntimeID, director, tenant)
 	if cmerr != nil {
 		return ctrl.Result{Requeue: true}, errors.Wrap(cmerr, "failed to update Compass Manager Mapping with RuntimeID after registration of runtime")
 	}
@@ -289,18 +391,23 @@ func (cm *CompassManagerReconciler) registerRuntimeInCompassAndRequeue(kymaName
 	return ctrl.Result{RequeueAfter: cm.requeueTime}, nil
 }
 
-func (cm *CompassManagerReconciler) configureRuntimeAndSetMappingStatus(kymaName types.NamespacedName, kubeconfig []byte, compassRuntimeID, globalAccount string) (ctrl.Result, error) {
+func (cm *CompassManagerReconciler) configureRuntimeAndSetMappingStatus(kymaName types.NamespacedName, kubeconfig []byte, compassRuntimeID, globalAccount, director string) (ctrl.Result, error) {
 	cm.Log.Infof("Attempting to configure Compass Runtime Agent for Runtime %s", compassRuntimeID)
 
-	cfgError := cm.Configurator.ConfigureCompassRuntimeAgent(kubeconfig, compassRuntimeID, globalAccount)
+	cfgError := cm.Configurator.ConfigureCompassRuntimeAgent(kymaName.Name, kubeconfig, compassRuntimeID, globalAccount, director)
 	if cfgError != nil {
 		cm.Log.Errorf("Failed attempt to configure Compass Runtime Agent for Kyma resource %s", kymaName.Name)
+		cm.recordFailure(kymaName, ReasonConfigurationFailed, ActionConfigure, cfgError)
 
 		statErr := cm.cluster.SetCompassMappingStatus(kymaName, s.Registered|s.Failed)
 		if statErr != nil {
 			return ctrl.Result{Requeue: true}, errors.Wrap(statErr, "failed to set Compass Manager Status after failed attempt configuration Compass Runtime Agent ")
 		}
 
+		if isDirectorCircuitOpen(cfgError) {
+			return ctrl.Result{RequeueAfter: cm.requeueTimeForDirectorError(cfgError, director)}, nil
+		}
+
 		return ctrl.Result{Requeue: true}, errors.Wrapf(cfgError, "failed attempt to configure Compass Runtime Agent for Kyma resource %s", kymaName.Name)
 	}
 
@@ -308,11 +415,20 @@ func (cm *CompassManagerReconciler) configureRuntimeAndSetMappingStatus(kymaName
 	cm.metrics.UpdateState(kymaName.Name, s.Registered|s.Configured)
 	cm.Log.Infof("Compass Runtime Agent for Runtime %s configured.", compassRuntimeID)
 
+	mapping, err := cm.cluster.GetCompassMapping(kymaName)
+	if err != nil {
+		return ctrl.Result{Requeue: true}, errors.Wrap(err, "failed to obtain Compass Manager Mapping after successful configuration Compass Runtime Agent")
+	}
 	statErr := cm.cluster.SetCompassMappingStatus(kymaName, s.Registered|s.Configured)
 	if statErr != nil {
 		return ctrl.Result{Requeue: true}, errors.Wrap(statErr, "failed to set Compass Manager Status after successful configuration Compass Runtime Agent ")
 	}
 
+	// mappings configured before the time of the first configuration was stored aren't observed
+	if mapping.Status.ConfiguredTime == nil && !mapping.Status.Configured {
+		cm.metrics.ObserveTimeToReady(time.Since(mapping.CreationTimestamp.Time))
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -324,6 +440,32 @@ func (cm *CompassManagerReconciler) setStatusAndRequeue(kymaName types.Namespace
 	return ctrl.Result{RequeueAfter: cm.requeueTime}, nil
 }
 
+// selectDirector returns the Director the Kyma runtime should be registered in, an empty name stands for the default one
+func (cm *CompassManagerReconciler) selectDirector(kymaLabels map[string]string, globalAccount string) string {
+	if cm.directors == nil {
+		return ""
+	}
+	return cm.directors.SelectDirector(kymaLabels, globalAccount)
+}
+
+// requeueTimeForDirectorError delays the next attempt until Director is probed again when calls to it are short-circuited
+func (cm *CompassManagerReconciler) requeueTimeForDirectorError(err error, director string) time.Duration {
+	if !isDirectorCircuitOpen(err) || cm.directors == nil {
+		return cm.requeueTime
+	}
+
+	untilProbe := time.Until(cm.directors.NextProbe(director))
+	if untilProbe < cm.requeueTime {
+		return cm.requeueTime
+	}
+	return untilProbe
+}
+
+func isDirectorCircuitOpen(err error) bool {
+	var appErr apperrors.AppError
+	return errors.As(err, &appErr) && appErr.Reason() == apperrors.ErrDirectorCircuitOpen
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (cm *CompassManagerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	eventFilters := predicate.Funcs{
@@ -348,15 +490,12 @@ func (cm *CompassManagerReconciler) CreateFunc(obj runtime.Object) bool {
 		return false
 	}
 
-	kymaModules := kymaObj.Status.Modules
-
-	for _, v := range kymaModules {
-		if v.Name == ApplicationConnectorModuleName {
-			return true
-		}
-	}
+	return HasApplicationConnectorModule(*kymaObj)
+}
 
-	return false
+// HasApplicationConnectorModule tells if the application-connector module is enabled in the Kyma, so that it's registered in Compass
+func HasApplicationConnectorModule(kymaCR kyma.Kyma) bool {
+	return slices.Contains(getModuleNames(kymaCR.Status.Modules), ApplicationConnectorModuleName)
 }
 
 func (cm *CompassManagerReconciler) UpdateFunc(oldObj, newObj runtime.Object) bool {
@@ -373,8 +512,17 @@ func (cm *CompassManagerReconciler) UpdateFunc(oldObj, newObj runtime.Object) bo
 
 	oldModules := getModuleNames(oldKymaObj.Status.Modules)
 	newModules := getModuleNames(newKymaObj.Status.Modules)
+	if !slices.Contains(newModules, ApplicationConnectorModuleName) {
+		return false
+	}
 
-	return !slices.Contains(oldModules, ApplicationConnectorModuleName) && slices.Contains(newModules, ApplicationConnectorModuleName)
+	// Removal of the requested operation, when the controller claims it, doesn't trigger the reconciliation
+	operation := newKymaObj.Annotations[AnnotationCompassOperation]
+
+	return !slices.Contains(oldModules, ApplicationConnectorModuleName) ||
+		oldKymaObj.Annotations[AnnotationCompassSubaccounts] != newKymaObj.Annotations[AnnotationCompassSubaccounts] ||
+		oldKymaObj.Annotations[AnnotationCompassFormations] != newKymaObj.Annotations[AnnotationCompassFormations] ||
+		(operation != "" && operation != oldKymaObj.Annotations[AnnotationCompassOperation])
 }
 
 func getModuleNames(modules []kyma.ModuleStatus) []string {
@@ -395,19 +543,29 @@ func (cm *CompassManagerReconciler) DeleteFunc(obj runtime.Object) bool {
 	return true
 }
 
-func createCompassRuntimeLabels(kymaLabels map[string]string) map[string]interface{} {
+// CompassRuntimeLabels returns labels of the runtime registered in Compass for the Kyma with the labels
+func CompassRuntimeLabels(kymaLabels map[string]string) map[string]interface{} {
 	runtimeLabels := make(map[string]interface{})
-	runtimeLabels["director_connection_managed_by"] = ManagedBy
-	runtimeLabels["broker_instance_id"] = kymaLabels[LabelBrokerInstanceID]
-	runtimeLabels["gardenerClusterName"] = kymaLabels[LabelShootName]
-	runtimeLabels["global_subaccount_id"] = kymaLabels[LabelSubaccountID]
-	runtimeLabels["global_account_id"] = kymaLabels[LabelGlobalAccountID]
-	runtimeLabels["broker_plan_id"] = kymaLabels[LabelBrokerPlanID]
-	runtimeLabels["broker_plan_name"] = kymaLabels[LabelBrokerPlanName]
+	runtimeLabels[RuntimeLabelManagedBy] = ManagedBy
+	runtimeLabels[RuntimeLabelBrokerInstanceID] = kymaLabels[LabelBrokerInstanceID]
+	runtimeLabels[RuntimeLabelShootName] = kymaLabels[LabelShootName]
+	runtimeLabels[RuntimeLabelSubaccountID] = kymaLabels[LabelSubaccountID]
+	runtimeLabels[RuntimeLabelGlobalAccountID] = kymaLabels[LabelGlobalAccountID]
+	runtimeLabels[RuntimeLabelBrokerPlanID] = kymaLabels[LabelBrokerPlanID]
+	runtimeLabels[RuntimeLabelBrokerPlanName] = kymaLabels[LabelBrokerPlanName]
 
 	return runtimeLabels
 }
 
+// RuntimeMatchesKyma tells if the runtime with the labels was registered for the Kyma, by the broker instance ID or the shoot name set on both
+func RuntimeMatchesKyma(runtimeLabels map[string]interface{}, kymaLabels map[string]string) bool {
+	matches := func(runtimeLabel, kymaLabel string) bool {
+		value, ok := runtimeLabels[runtimeLabel].(string)
+		return ok && value != "" && value == kymaLabels[kymaLabel]
+	}
+	return matches(RuntimeLabelBrokerInstanceID, LabelBrokerInstanceID) || matches(RuntimeLabelShootName, LabelShootName)
+}
+
 type ControlPlaneInterface struct {
 	log     *log.Logger
 	kubectl Client
@@ -440,6 +598,12 @@ func (c *ControlPlaneInterface) GetKyma(name types.NamespacedName) (kyma.Kyma, e
 	return kymaCR, nil
 }
 
+// RemoveKymaAnnotation removes the annotation from the Kyma, the update fails with a conflict when the Kyma has changed since it was read
+func (c *ControlPlaneInterface) RemoveKymaAnnotation(kymaCR kyma.Kyma, annotation string) error {
+	delete(kymaCR.Annotations, annotation)
+	return c.kubectl.Update(context.TODO(), &kymaCR)
+}
+
 func (c *ControlPlaneInterface) GetCompassMapping(name types.NamespacedName) (v1beta1.CompassManagerMapping, error) {
 	mapping := v1beta1.CompassManagerMapping{}
 
@@ -533,25 +697,19 @@ func (c *ControlPlaneInterface) GetKubeconfig(name types.NamespacedName) ([]byte
 	return kubecfg.Data[KubeconfigKey], nil
 }
 
-func (c *ControlPlaneInterface) UpsertCompassMapping(name types.NamespacedName, compassRuntimeID string) error {
+// UpsertCompassMapping stores the ID of the registered Runtime and the Director it is registered in, unless it is the default one,
+// and the tenant it is registered in, unless it is the Global Account
+func (c *ControlPlaneInterface) UpsertCompassMapping(name types.NamespacedName, compassRuntimeID, director, tenant string) error {
 	kymaCR, err := c.GetKyma(name)
 	if err != nil {
 		return err
 	}
 
-	labels := make(map[string]string)
-	labels[LabelKymaName] = kymaCR.Labels[LabelKymaName]
-	labels[LabelCompassID] = compassRuntimeID
-	labels[LabelGlobalAccountID] = kymaCR.Labels[LabelGlobalAccountID]
-	labels[LabelSubaccountID] = kymaCR.Labels[LabelSubaccountID]
-	labels[LabelManagedBy] = ManagedBy
-	if c.dry {
-		labels[LabelDryRun] = "Yes"
-	}
+	labels := c.compassMappingLabels(kymaCR.Labels, compassRuntimeID, director, tenant)
 
 	existingMapping, err := c.GetCompassMapping(name)
 
-	if isNotFound(err) {
+	if IsNotFound(err) {
 		newMapping := &v1beta1.CompassManagerMapping{}
 		newMapping.Name = name.Name
 		newMapping.Namespace = name.Namespace
@@ -578,6 +736,42 @@ func (c *ControlPlaneInterface) UpsertCompassMapping(name types.NamespacedName,
 	return err
 }
 
+// RestoreCompassMapping creates the mapping of the Kyma registered as the Runtime, with labels as UpsertCompassMapping writes them and the status.
+// Labels of the Kyma are passed, as Kymas of a rebuilt control plane may be created after their mappings.
+func (c *ControlPlaneInterface) RestoreCompassMapping(name types.NamespacedName, kymaLabels map[string]string, compassRuntimeID, director, tenant string, status v1beta1.CompassManagerMappingStatus) error {
+	mapping := &v1beta1.CompassManagerMapping{}
+	mapping.Name = name.Name
+	mapping.Namespace = name.Namespace
+	mapping.Labels = c.compassMappingLabels(kymaLabels, compassRuntimeID, director, tenant)
+	mapping.Finalizers = []string{Finalizer}
+
+	if err := c.kubectl.Create(context.TODO(), mapping); err != nil {
+		return err
+	}
+
+	mapping.Status = status
+	return c.kubectl.Status().Update(context.TODO(), mapping)
+}
+
+func (c *ControlPlaneInterface) compassMappingLabels(kymaLabels map[string]string, compassRuntimeID, director, tenant string) map[string]string {
+	labels := make(map[string]string)
+	labels[LabelKymaName] = kymaLabels[LabelKymaName]
+	labels[LabelCompassID] = compassRuntimeID
+	labels[LabelGlobalAccountID] = kymaLabels[LabelGlobalAccountID]
+	labels[LabelSubaccountID] = kymaLabels[LabelSubaccountID]
+	labels[LabelManagedBy] = ManagedBy
+	if director != "" {
+		labels[LabelCompassDirector] = director
+	}
+	if tenant != "" && tenant != labels[LabelGlobalAccountID] {
+		labels[LabelCompassTenant] = tenant
+	}
+	if c.dry {
+		labels[LabelDryRun] = "Yes"
+	}
+	return labels
+}
+
 func (c *ControlPlaneInterface) CreateCompassMapping(name types.NamespacedName) error {
 	kymaCR, err := c.GetKyma(name)
 	if err != nil {
@@ -613,7 +807,7 @@ func (c *ControlPlaneInterface) GetCompassRuntimeID(name types.NamespacedName) (
 	return mapping.Labels[LabelCompassID], nil
 }
 
-// SetCompassMappingStatus sets the registered and configured on an existing CompassManagerMapping
+// SetCompassMappingStatus sets the registered and configured on an existing CompassManagerMapping, with the times the state changed from Ready and the runtime was first configured
 // If error occurs - logs it and returns
 func (c *ControlPlaneInterface) SetCompassMappingStatus(name types.NamespacedName, status s.Status) error {
 	mapping, err := c.GetCompassMapping(name)
@@ -625,10 +819,19 @@ func (c *ControlPlaneInterface) SetCompassMappingStatus(name types.NamespacedNam
 	configured := status&s.Configured != 0
 	state := s.StateText(status)
 
-	mapping.Status = v1beta1.CompassManagerMappingStatus{
-		Registered: registered,
-		Configured: configured,
-		State:      state,
+	mapping.Status.Registered = registered
+	mapping.Status.Configured = configured
+	mapping.Status.State = state
+
+	now := metav1.Now()
+	switch {
+	case state == s.ReadyState:
+		mapping.Status.NotReadySince = nil
+	case mapping.Status.NotReadySince == nil:
+		mapping.Status.NotReadySince = &now
+	}
+	if configured && mapping.Status.ConfiguredTime == nil {
+		mapping.Status.ConfiguredTime = &now
 	}
 
 	err = c.kubectl.Status().Update(context.TODO(), &mapping)
@@ -640,6 +843,81 @@ func (c *ControlPlaneInterface) SetCompassMappingStatus(name types.NamespacedNam
 	return err
 }
 
-func isNotFound(err error) bool {
+// SetCompassMappingRuntimeContexts stores the contexts of the Runtime created for subaccounts in the status of an existing CompassManagerMapping
+func (c *ControlPlaneInterface) SetCompassMappingRuntimeContexts(name types.NamespacedName, runtimeContexts []v1beta1.RuntimeContext) error {
+	mapping, err := c.GetCompassMapping(name)
+	if err != nil {
+		return err
+	}
+
+	mapping.Status.RuntimeContexts = runtimeContexts
+
+	err = c.kubectl.Status().Update(context.TODO(), &mapping)
+	if err != nil {
+		c.log.Warnf("Failed to update Runtime contexts in Compass Mapping Status for %s: %v", name.Name, err)
+	}
+	return err
+}
+
+// SetCompassMappingFormations stores the formations the Runtime is assigned to in the status of an existing CompassManagerMapping
+func (c *ControlPlaneInterface) SetCompassMappingFormations(name types.NamespacedName, formations []string) error {
+	mapping, err := c.GetCompassMapping(name)
+	if err != nil {
+		return err
+	}
+
+	mapping.Status.Formations = formations
+
+	err = c.kubectl.Status().Update(context.TODO(), &mapping)
+	if err != nil {
+		c.log.Warnf("Failed to update formations in Compass Mapping Status for %s: %v", name.Name, err)
+	}
+	return err
+}
+
+// RestoreCompassMappingStatus replaces the status of an existing CompassManagerMapping, e.g. with the status of an imported mapping
+func (c *ControlPlaneInterface) RestoreCompassMappingStatus(name types.NamespacedName, status v1beta1.CompassManagerMappingStatus) error {
+	mapping, err := c.GetCompassMapping(name)
+	if err != nil {
+		return err
+	}
+
+	mapping.Status = status
+
+	err = c.kubectl.Status().Update(context.TODO(), &mapping)
+	if err != nil {
+		c.log.Warnf("Failed to restore Compass Mapping Status for %s: %v", name.Name, err)
+	}
+	return err
+}
+
+// ResetCompassMapping removes the ID of the deregistered Runtime, and where it was registered, from an existing CompassManagerMapping and clears its status
+func (c *ControlPlaneInterface) ResetCompassMapping(name types.NamespacedName) error {
+	mapping, err := c.GetCompassMapping(name)
+	if err != nil {
+		return err
+	}
+
+	mapping.Labels[LabelCompassID] = ""
+	delete(mapping.Labels, LabelCompassDirector)
+	delete(mapping.Labels, LabelCompassTenant)
+
+	err = c.kubectl.Update(context.TODO(), &mapping)
+	if err != nil {
+		return err
+	}
+
+	// the first configuration is kept, so that registering the runtime again doesn't count as enabling the module
+	mapping.Status = v1beta1.CompassManagerMappingStatus{ConfiguredTime: mapping.Status.ConfiguredTime}
+
+	err = c.kubectl.Status().Update(context.TODO(), &mapping)
+	if err != nil {
+		c.log.Warnf("Failed to reset Compass Mapping Status for %s: %v", name.Name, err)
+	}
+	return err
+}
+
+// IsNotFound tells if the error is returned for a missing resource, including a missing Compass Manager Mapping
+func IsNotFound(err error) bool {
 	return k8serrors.IsNotFound(err) || errors.Is(err, errNotFound)
 }
@@ -2,9 +2,11 @@ package controllers
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/internal/director/fake"
 	"github.com/kyma-project/lifecycle-manager/api/shared"
 	kyma "github.com/kyma-project/lifecycle-manager/api/v1beta2"
 	. "github.com/onsi/ginkgo/v2" //nolint:revive
@@ -23,6 +25,7 @@ const (
 	kymaCustomResourceAPIVersion = "operator.kyma-project.io/v1beta2"
 	clientTimeout                = time.Second * 45
 	clientInterval               = time.Second * 3
+	globalAccount                = "globalAccount"
 )
 
 var _ = Describe("Compass Manager controller", func() {
@@ -31,7 +34,12 @@ var _ = Describe("Compass Manager controller", func() {
 	kymaCustomResourceLabels["operator.kyma-project.io/managed-by"] = "lifecycle-manager"
 
 	Context("Secret with Kubeconfig is correctly created, and assigned to Kyma resource", func() {
-		DescribeTable("Register Runtime in the Director, and configure Compass Runtime Agent", func(kymaName string) {
+		DescribeTable("Register Runtime in the Director, and configure Compass Runtime Agent", func(kymaName string, failingOperation string) {
+			if failingOperation != "" {
+				By("Fail the first " + failingOperation + " in the Director")
+				directorServer.Director.FailNext(failingOperation, fake.Failure{StatusCode: http.StatusServiceUnavailable})
+			}
+
 			By("Create secret with credentials")
 			secret := createCredentialsSecret(kymaName)
 			Expect(k8sClient.Create(context.Background(), &secret)).To(Succeed())
@@ -54,10 +62,20 @@ var _ = Describe("Compass Manager controller", func() {
 			Expect(mapping.Status.Registered).To(BeTrue())
 			Expect(mapping.Status.Configured).To(BeTrue())
 
+			By("Verify runtime in the Director")
+			runtime, registered := directorServer.Director.Runtime(globalAccount, mapping.Labels[LabelCompassID])
+			Expect(registered).To(BeTrue())
+			Expect(runtime.Labels).To(HaveKeyWithValue(RuntimeLabelGlobalAccountID, globalAccount))
+
+			By("Verify Compass Runtime Agent got an unused one-time token issued by the Director")
+			token, err := getAgentToken()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(directorServer.Director.OneTimeTokens(runtime.ID)).To(ContainElement(token))
+			Expect(directorServer.Director.UseOneTimeToken(token)).To(BeTrue())
 		},
-			Entry("Runtime successfully registered, and Compass Runtime Agent's configuration created", "all-good"),
-			Entry("The first attempt to register Runtime failed, and retry succeeded", "registration-fails"),
-			Entry("Runtime successfully registered, the first attempt to configure Compass Runtime Agent failed, and retry succeeded", "configure-fails"),
+			Entry("Runtime successfully registered, and Compass Runtime Agent's configuration created", "all-good", ""),
+			Entry("The first attempt to register Runtime failed, and retry succeeded", "registration-fails", "registerRuntime"),
+			Entry("Runtime successfully registered, the first attempt to configure Compass Runtime Agent failed, and retry succeeded", "configure-fails", "requestOneTimeTokenForRuntime"),
 		)
 	})
 
@@ -89,7 +107,7 @@ var _ = Describe("Compass Manager controller", func() {
 	})
 
 	Context("After successful runtime registration when user delete Kyma resource", func() {
-		DescribeTable("the runtime should be deregister from Compass System", func(kymaName string) {
+		DescribeTable("the runtime should be deregister from Compass System", func(kymaName string, failingOperation string) {
 			By("Create secret with credentials")
 			secret := createCredentialsSecret(kymaName)
 			Expect(k8sClient.Create(context.Background(), &secret)).To(Succeed())
@@ -98,12 +116,19 @@ var _ = Describe("Compass Manager controller", func() {
 			kymaCR := createKymaResource(kymaName)
 			Expect(k8sClient.Create(context.Background(), &kymaCR)).To(Succeed())
 
+			var compassID string
 			Eventually(func() bool {
-				label, _, err := getCompassMappingCompassIDAndState(kymaCR.Name)
+				var err error
+				compassID, _, err = getCompassMappingCompassIDAndState(kymaCR.Name)
 
-				return err == nil && label != ""
+				return err == nil && compassID != ""
 			}, clientTimeout, clientInterval).Should(BeTrue())
 
+			if failingOperation != "" {
+				By("Fail the first " + failingOperation + " in the Director")
+				directorServer.Director.FailNext(failingOperation, fake.Failure{StatusCode: http.StatusServiceUnavailable})
+			}
+
 			By("Delete Kyma resource")
 			Expect(k8sClient.Delete(context.Background(), &kymaCR)).To(Succeed())
 
@@ -112,9 +137,13 @@ var _ = Describe("Compass Manager controller", func() {
 
 				return errors.IsNotFound(err) && label == ""
 			}, clientTimeout, clientInterval).Should(BeTrue())
+
+			By("Verify runtime was removed from the Director")
+			_, registered := directorServer.Director.Runtime(globalAccount, compassID)
+			Expect(registered).To(BeFalse())
 		},
-			Entry("Runtime successfully unregistered", "unregister-runtime"),
-			Entry("The first attempt to unregister Runtime failed, and retry succeeded", "unregister-runtime-fails"),
+			Entry("Runtime successfully unregistered", "unregister-runtime", ""),
+			Entry("The first attempt to unregister Runtime failed, and retry succeeded", "unregister-runtime-fails", "unregisterRuntime"),
 		)
 	})
 
@@ -128,10 +157,13 @@ var _ = Describe("Compass Manager controller", func() {
 			kymaCR := createKymaResource(kymaName)
 			Expect(k8sClient.Create(context.Background(), &kymaCR)).To(Succeed())
 
+			var compassID string
 			Eventually(func() bool {
-				label, state, err := getCompassMappingCompassIDAndState(kymaCR.Name)
+				var state string
+				var err error
+				compassID, state, err = getCompassMappingCompassIDAndState(kymaCR.Name)
 
-				return err == nil && label != "" && state == mappingCRReadyState
+				return err == nil && compassID != "" && state == mappingCRReadyState
 			}, clientTimeout, clientInterval).Should(BeTrue())
 
 			By("Disable the Application Connector module")
@@ -153,6 +185,11 @@ var _ = Describe("Compass Manager controller", func() {
 				err = k8sClient.Update(context.Background(), modifiedKyma)
 				return err
 			}, clientTimeout, clientInterval).ShouldNot(HaveOccurred())
+
+			By("Verify a new one-time token was issued by the Director")
+			Eventually(func() int {
+				return len(directorServer.Director.OneTimeTokens(compassID))
+			}, clientTimeout, clientInterval).Should(BeNumerically(">", 1))
 		},
 			Entry("Token successfully refreshed", "refresh-token"),
 		)
@@ -170,7 +207,7 @@ func createNamespace(name string) error {
 
 func createKymaResource(name string) kyma.Kyma {
 	kymaCustomResourceLabels := make(map[string]string)
-	kymaCustomResourceLabels[LabelGlobalAccountID] = "globalAccount"
+	kymaCustomResourceLabels[LabelGlobalAccountID] = globalAccount
 	kymaCustomResourceLabels[LabelShootName] = name
 	kymaCustomResourceLabels[LabelKymaName] = name
 
@@ -205,7 +242,7 @@ func createCredentialsSecret(kymaName string) corev1.Secret {
 			Labels:    map[string]string{"operator.kyma-project.io/kyma-name": kymaName},
 		},
 		Immutable:  nil,
-		Data:       map[string][]byte{KubeconfigKey: []byte("kubeconfig-data-" + kymaName)},
+		Data:       map[string][]byte{KubeconfigKey: runtimeKubeconfig},
 		StringData: nil,
 		Type:       "Opaque",
 	}
@@ -221,6 +258,12 @@ func getCompassMappingCompassIDAndState(kymaName string) (string, string, error)
 	return labels[LabelCompassID], obj.Status.State, nil
 }
 
+func getAgentToken() (string, error) {
+	var secret corev1.Secret
+	err := k8sClient.Get(context.Background(), types.NamespacedName{Name: AgentConfigurationSecretName, Namespace: runtimeAgentComponentNameSpace}, &secret)
+	return string(secret.Data["TOKEN"]), err
+}
+
 func getCompassMapping(kymaName string) (v1beta1.CompassManagerMapping, error) {
 	var obj v1beta1.CompassManagerMapping
 	key := types.NamespacedName{Name: kymaName, Namespace: kymaCustomResourceNamespace}
@@ -3,10 +3,12 @@ package controllers
 import (
 	"context"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/kyma-incubator/compass/components/director/pkg/graphql"
 	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/kyma-project/compass-manager/internal/connector"
 	"github.com/kyma-project/compass-manager/internal/director"
 	"github.com/kyma-project/compass-manager/internal/util"
 	"github.com/pkg/errors"
@@ -22,29 +24,42 @@ const (
 	AgentConfigurationSecretName   = "compass-agent-configuration"
 	runtimeAgentComponentNameSpace = "kyma-system"
 	maxTokenLength                 = 100
+	// connectorCheckInterval is how long a successful check of the Connector of a Director is trusted
+	connectorCheckInterval = time.Hour
 )
 
 type RuntimeAgentConfigurator struct {
-	Client              director.Client
-	ConnectorURLPattern string
-	Log                 *logrus.Logger
+	Directors *director.Registry
+	Log       *logrus.Logger
+	// Connector checks that the Connector of every Director accepts its one-time tokens, the check is disabled when it's nil
+	Connector connector.Client
+
+	checkedMu sync.Mutex
+	// checked keeps when the Connector of the Director was last checked successfully
+	checked map[string]time.Time
 }
 
-func NewRuntimeAgentConfigurator(directorClient director.Client, connectorURLPattern string, log *logrus.Logger) *RuntimeAgentConfigurator {
+func NewRuntimeAgentConfigurator(directors *director.Registry, log *logrus.Logger, connectorClient connector.Client) *RuntimeAgentConfigurator {
 	return &RuntimeAgentConfigurator{
-		Client:              directorClient,
-		ConnectorURLPattern: connectorURLPattern,
-		Log:                 log,
+		Directors: directors,
+		Log:       log,
+		Connector: connectorClient,
+		checked:   map[string]time.Time{},
 	}
 }
 
-func (r *RuntimeAgentConfigurator) ConfigureCompassRuntimeAgent(kubeconfig []byte, compassRuntimeID, globalAccount string) error {
+func (r *RuntimeAgentConfigurator) ConfigureCompassRuntimeAgent(kymaName string, kubeconfig []byte, compassRuntimeID, globalAccount, directorName string) error {
 	kubeClient, err := r.prepareKubeClient(kubeconfig)
 	if err != nil {
 		return err
 	}
 
-	token, err := r.fetchCompassToken(compassRuntimeID, globalAccount)
+	err = r.checkConnector(kymaName, compassRuntimeID, globalAccount, directorName)
+	if err != nil {
+		return err
+	}
+
+	token, err := r.fetchCompassToken(kymaName, compassRuntimeID, globalAccount, directorName)
 	if err != nil {
 		return err
 	}
@@ -85,6 +100,38 @@ func (r *RuntimeAgentConfigurator) upsertCompassRuntimeAgentSecret(kubeClient ku
 	return err
 }
 
+// checkConnector confirms, over verified TLS, that the Connector returned by the Director accepts one-time tokens the Director issues.
+// The check uses up a one-time token of the Runtime, so the token written to the Runtime can't be checked itself.
+// A successful check is trusted for connectorCheckInterval, so one extra token is requested per Director, not per configuration.
+func (r *RuntimeAgentConfigurator) checkConnector(kymaName, compassRuntimeID, globalAccount, directorName string) error {
+	if r.Connector == nil {
+		return nil
+	}
+
+	// the lock isn't held while Director and the Connector are called, so concurrent configurations may both check the Connector
+	r.checkedMu.Lock()
+	checkedAt, ok := r.checked[directorName]
+	r.checkedMu.Unlock()
+	if ok && time.Since(checkedAt) < connectorCheckInterval {
+		return nil
+	}
+
+	token, err := r.fetchCompassToken(kymaName, compassRuntimeID, globalAccount, directorName)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.Connector.ValidateToken(token.ConnectorURL, token.Token)
+	if err != nil {
+		return err
+	}
+
+	r.checkedMu.Lock()
+	r.checked[directorName] = time.Now()
+	r.checkedMu.Unlock()
+	return nil
+}
+
 func (r *RuntimeAgentConfigurator) prepareKubeClient(kubeconfig []byte) (kubernetes.Interface, error) {
 	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
 	if err != nil {
@@ -93,10 +140,16 @@ func (r *RuntimeAgentConfigurator) prepareKubeClient(kubeconfig []byte) (kuberne
 	return kubernetes.NewForConfig(config)
 }
 
-func (r *RuntimeAgentConfigurator) fetchCompassToken(compassID, globalAccount string) (graphql.OneTimeTokenForRuntimeExt, error) {
+func (r *RuntimeAgentConfigurator) fetchCompassToken(kymaName, compassID, globalAccount, directorName string) (graphql.OneTimeTokenForRuntimeExt, error) {
+	endpoint, err := r.Directors.Get(directorName)
+	if err != nil {
+		return graphql.OneTimeTokenForRuntimeExt{}, err
+	}
+
+	directorClient := director.WithKymaName(endpoint.Client, kymaName)
 	var token graphql.OneTimeTokenForRuntimeExt
-	err := util.RetryOnError(retryTime*time.Second, attempts, "Error while refreshing OneTime token in Director: %s", func() (err apperrors.AppError) {
-		token, err = r.Client.GetConnectionToken(compassID, globalAccount)
+	err = util.RetryOnError(retryTime*time.Second, attempts, "Error while refreshing OneTime token in Director: %s", func() (err apperrors.AppError) {
+		token, err = directorClient.GetConnectionToken(compassID, globalAccount)
 		return
 	})
 
@@ -104,7 +157,7 @@ func (r *RuntimeAgentConfigurator) fetchCompassToken(compassID, globalAccount st
 		return graphql.OneTimeTokenForRuntimeExt{}, err
 	}
 
-	if !strings.HasSuffix(token.ConnectorURL, r.ConnectorURLPattern) {
+	if !strings.HasSuffix(token.ConnectorURL, endpoint.ConnectorURLPattern) {
 		return graphql.OneTimeTokenForRuntimeExt{}, errors.New("Connector URL does not match the expected pattern")
 	}
 
@@ -4,7 +4,15 @@ import (
 	"testing"
 
 	"github.com/kyma-incubator/compass/components/director/pkg/graphql"
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/kyma-project/compass-manager/internal/connector"
+	connectormocks "github.com/kyma-project/compass-manager/internal/connector/mocks"
+	"github.com/kyma-project/compass-manager/internal/director"
+	"github.com/kyma-project/compass-manager/internal/director/fake"
 	"github.com/kyma-project/compass-manager/internal/director/mocks"
+	gql "github.com/kyma-project/compass-manager/internal/graphql"
+	"github.com/kyma-project/compass-manager/internal/oauth"
+	"github.com/kyma-project/compass-manager/pkg/gqlschema"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -22,9 +30,9 @@ func TestAppError(t *testing.T) {
 			},
 		}, nil)
 
-		configurator := NewRuntimeAgentConfigurator(&mockDirectorClient, "kyma.cloud.sap/connector/graphql", logrus.New())
+		configurator := NewRuntimeAgentConfigurator(director.NewSingleDirectorRegistry(&mockDirectorClient, "kyma.cloud.sap/connector/graphql"), logrus.New(), nil)
 
-		token, err := configurator.fetchCompassToken("compassID", "globalAccount")
+		token, err := configurator.fetchCompassToken("kyma", "compassID", "globalAccount", "")
 		require.NoError(t, err)
 		assert.Equal(t, "kyma.cloud.sap/connector/graphql", token.ConnectorURL)
 		assert.Equal(t, "dGVzdFRva2VuQmFzZWQ2NA==", token.Token)
@@ -40,9 +48,9 @@ func TestAppError(t *testing.T) {
 			},
 		}, nil)
 
-		configurator := NewRuntimeAgentConfigurator(&mockDirectorClient, "kyma.cloud.sap/connector/graphql", logrus.New())
+		configurator := NewRuntimeAgentConfigurator(director.NewSingleDirectorRegistry(&mockDirectorClient, "kyma.cloud.sap/connector/graphql"), logrus.New(), nil)
 
-		token, err := configurator.fetchCompassToken("compassID", "globalAccount")
+		token, err := configurator.fetchCompassToken("kyma", "compassID", "globalAccount", "")
 		require.Error(t, err)
 		require.ErrorContains(t, err, "Connector URL does not match the expected pattern")
 		assert.Equal(t, token, graphql.OneTimeTokenForRuntimeExt{})
@@ -58,11 +66,121 @@ func TestAppError(t *testing.T) {
 			},
 		}, nil)
 
-		configurator := NewRuntimeAgentConfigurator(&mockDirectorClient, "kyma.cloud.sap/connector/graphql", logrus.New())
+		configurator := NewRuntimeAgentConfigurator(director.NewSingleDirectorRegistry(&mockDirectorClient, "kyma.cloud.sap/connector/graphql"), logrus.New(), nil)
 
-		token, err := configurator.fetchCompassToken("compassID", "globalAccount")
+		token, err := configurator.fetchCompassToken("kyma", "compassID", "globalAccount", "")
 		require.Error(t, err)
 		require.ErrorContains(t, err, "OneTimeToken is too long")
 		assert.Equal(t, token, graphql.OneTimeTokenForRuntimeExt{})
 	})
+	t.Run("should fetch Compass Token from the Director the Runtime is registered in", func(t *testing.T) {
+		euDirectorClient := mocks.Client{}
+		usDirectorClient := mocks.Client{}
+		usDirectorClient.On("GetConnectionToken", "compassID", "globalAccount").Return(graphql.OneTimeTokenForRuntimeExt{
+			OneTimeTokenForRuntime: graphql.OneTimeTokenForRuntime{
+				TokenWithURL: graphql.TokenWithURL{
+					Token:        "dGVzdFRva2VuQmFzZWQ2NA==",
+					ConnectorURL: "us.kyma.cloud.sap/connector/graphql",
+				},
+			},
+		}, nil)
+
+		directors, err := director.NewRegistry(
+			director.Endpoint{Name: "eu", Client: &euDirectorClient, ConnectorURLPattern: "eu.kyma.cloud.sap/connector/graphql", Default: true},
+			director.Endpoint{Name: "us", Client: &usDirectorClient, ConnectorURLPattern: "us.kyma.cloud.sap/connector/graphql"},
+		)
+		require.NoError(t, err)
+		configurator := NewRuntimeAgentConfigurator(directors, logrus.New(), nil)
+
+		token, err := configurator.fetchCompassToken("kyma", "compassID", "globalAccount", "us")
+		require.NoError(t, err)
+		assert.Equal(t, "us.kyma.cloud.sap/connector/graphql", token.ConnectorURL)
+		euDirectorClient.AssertNotCalled(t, "GetConnectionToken", "compassID", "globalAccount")
+
+		_, err = configurator.fetchCompassToken("kyma", "compassID", "globalAccount", "unknown")
+		require.ErrorContains(t, err, "Director \"unknown\" is not configured")
+	})
+}
+
+func TestCheckConnector(t *testing.T) {
+	t.Run("should check the Connector with a token other than the one written to the Runtime", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{TLS: true})
+		defer server.Close()
+		directorClient := director.NewDirectorClient(gql.NewGraphQLClient(server.DirectorURL(), false, true),
+			oauth.NewOauthClient(server.Client(), fake.DefaultClientID, fake.DefaultClientSecret, server.TokensEndpoint()))
+		compassID, err := directorClient.CreateRuntime(&gqlschema.RuntimeInput{Name: "runtime"}, "globalAccount")
+		require.NoError(t, err)
+
+		configurator := NewRuntimeAgentConfigurator(director.NewSingleDirectorRegistry(directorClient, fake.ConnectorPath), logrus.New(), connector.NewConnectorClient(server.Client()))
+
+		// when
+		checkErr := configurator.checkConnector("kyma", compassID, "globalAccount", "")
+		token, fetchErr := configurator.fetchCompassToken("kyma", compassID, "globalAccount", "")
+
+		// then
+		require.NoError(t, checkErr)
+		require.NoError(t, fetchErr)
+		issued := server.Director.OneTimeTokens(compassID)
+		require.Len(t, issued, 2)
+		assert.Equal(t, issued[1], token.Token)
+		assert.False(t, server.Director.UseOneTimeToken(issued[0]), "token sent to the Connector should be used up")
+		assert.True(t, server.Director.UseOneTimeToken(token.Token), "token written to the Runtime should be unused")
+	})
+	t.Run("should check the Connector of the Director once", func(t *testing.T) {
+		// given
+		directorClient := mocks.NewClient(t)
+		directorClient.On("GetConnectionToken", "compassID", "globalAccount").Return(graphql.OneTimeTokenForRuntimeExt{
+			OneTimeTokenForRuntime: graphql.OneTimeTokenForRuntime{
+				TokenWithURL: graphql.TokenWithURL{
+					Token:        "dGVzdFRva2VuQmFzZWQ2NA==",
+					ConnectorURL: "https://kyma.cloud.sap/connector/graphql",
+				},
+			},
+		}, nil).Once()
+		connectorClient := connectormocks.NewClient(t)
+		connectorClient.On("ValidateToken", "https://kyma.cloud.sap/connector/graphql", "dGVzdFRva2VuQmFzZWQ2NA==").Return("", nil).Once()
+		configurator := NewRuntimeAgentConfigurator(director.NewSingleDirectorRegistry(directorClient, "kyma.cloud.sap/connector/graphql"), logrus.New(), connectorClient)
+
+		// when
+		firstErr := configurator.checkConnector("kyma", "compassID", "globalAccount", "")
+		secondErr := configurator.checkConnector("other-kyma", "compassID", "globalAccount", "")
+
+		// then
+		require.NoError(t, firstErr)
+		require.NoError(t, secondErr)
+	})
+	t.Run("should return error when the Connector rejects the token", func(t *testing.T) {
+		// given
+		directorClient := mocks.NewClient(t)
+		directorClient.On("GetConnectionToken", "compassID", "globalAccount").Return(graphql.OneTimeTokenForRuntimeExt{
+			OneTimeTokenForRuntime: graphql.OneTimeTokenForRuntime{
+				TokenWithURL: graphql.TokenWithURL{
+					Token:        "dGVzdFRva2VuQmFzZWQ2NA==",
+					ConnectorURL: "https://kyma.cloud.sap/connector/graphql",
+				},
+			},
+		}, nil)
+		connectorClient := connectormocks.NewClient(t)
+		connectorClient.On("ValidateToken", "https://kyma.cloud.sap/connector/graphql", "dGVzdFRva2VuQmFzZWQ2NA==").Return("", apperrors.Forbidden("invalid token").SetReason(apperrors.ErrConnectorTokenValidation))
+		configurator := NewRuntimeAgentConfigurator(director.NewSingleDirectorRegistry(directorClient, "kyma.cloud.sap/connector/graphql"), logrus.New(), connectorClient)
+
+		// when
+		err := configurator.checkConnector("kyma", "compassID", "globalAccount", "")
+
+		// then
+		var appErr apperrors.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, apperrors.ErrConnectorTokenValidation, appErr.Reason())
+	})
+	t.Run("should skip the check without the Connector client", func(t *testing.T) {
+		// given
+		configurator := NewRuntimeAgentConfigurator(director.NewSingleDirectorRegistry(mocks.NewClient(t), "kyma.cloud.sap/connector/graphql"), logrus.New(), nil)
+
+		// when
+		err := configurator.checkConnector("kyma", "compassID", "globalAccount", "")
+
+		// then
+		require.NoError(t, err)
+	})
 }
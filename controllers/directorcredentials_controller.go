@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/kyma-project/compass-manager/internal/oauth"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// DirectorCredentialsReconciler keeps the credentials of the Director OAuth client in sync with the Secret storing them
+type DirectorCredentialsReconciler struct {
+	Client      Client
+	Log         *log.Logger
+	Name        string
+	secret      types.NamespacedName
+	key         string
+	authMethod  oauth.AuthMethod
+	oauthClient oauth.ReloadableClient
+}
+
+func NewDirectorCredentialsReconciler(c Client, log *log.Logger, secret types.NamespacedName, key string, authMethod oauth.AuthMethod, oauthClient oauth.ReloadableClient) *DirectorCredentialsReconciler {
+	return &DirectorCredentialsReconciler{
+		Client:      c,
+		Log:         log,
+		Name:        "director-credentials",
+		secret:      secret,
+		key:         key,
+		authMethod:  authMethod,
+		oauthClient: oauthClient,
+	}
+}
+
+func (r *DirectorCredentialsReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	secret := corev1.Secret{}
+	err := r.Client.Get(ctx, r.secret, &secret)
+	if IsNotFound(err) {
+		r.Log.Warnf("Secret %s with Director credentials not found", r.secret)
+		r.oauthClient.InvalidateCredentials(errors.Errorf("secret %s with Director credentials not found", r.secret))
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to get Secret %s with Director credentials", r.secret)
+	}
+
+	cfg, err := oauth.ParseDirectorOAuth(secret.Data[r.key], r.authMethod)
+	if err != nil {
+		r.Log.Warnf("Secret %s contains invalid Director credentials: %v", r.secret, err)
+		r.oauthClient.InvalidateCredentials(errors.Wrapf(err, "secret %s contains invalid Director credentials", r.secret))
+		return ctrl.Result{}, nil
+	}
+
+	r.oauthClient.UpdateCredentials(cfg.Data.ClientID, cfg.Data.ClientSecret, cfg.Data.TokensEndpoint)
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// The controller runs on every replica, as each of them calls Director.
+func (r *DirectorCredentialsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	isCredentialsSecret := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetName() == r.secret.Name && obj.GetNamespace() == r.secret.Namespace
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(r.Name).
+		For(&corev1.Secret{}).
+		WithEventFilter(isCredentialsSecret).
+		WithOptions(controller.Options{NeedLeaderElection: ptr.To(false)}).
+		Complete(r)
+}
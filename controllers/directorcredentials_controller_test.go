@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-project/compass-manager/internal/oauth"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const directorCredentials = `data:
+  client_id: "some-ID"
+  client_secret: "some-Secret"
+  tokens_endpoint: "https://example.com/oauth2/token"`
+
+func TestDirectorCredentialsReconciler(t *testing.T) {
+	secretName := types.NamespacedName{Name: "director-credentials", Namespace: "kcp-system"}
+
+	t.Run("should load credentials from Secret", func(t *testing.T) {
+		// given
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName.Name, Namespace: secretName.Namespace},
+			Data:       map[string][]byte{"director.yaml": []byte(directorCredentials)},
+		}
+		oauthClient := oauth.NewReloadableOauthClient(nil)
+		reconciler := NewDirectorCredentialsReconciler(fake.NewClientBuilder().WithObjects(secret).Build(), logrus.New(), secretName, "director.yaml", oauth.AuthMethodClientSecretBasic, oauthClient)
+
+		// when
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: secretName})
+
+		// then
+		require.NoError(t, err)
+		assert.NoError(t, oauthClient.CredentialsValid())
+	})
+
+	t.Run("should load credentials without client secret for private_key_jwt", func(t *testing.T) {
+		// given
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName.Name, Namespace: secretName.Namespace},
+			Data: map[string][]byte{"director.yaml": []byte(`data:
+  client_id: "some-ID"
+  tokens_endpoint: "https://example.com/oauth2/token"`)},
+		}
+		oauthClient := oauth.NewReloadableOauthClient(nil)
+		reconciler := NewDirectorCredentialsReconciler(fake.NewClientBuilder().WithObjects(secret).Build(), logrus.New(), secretName, "director.yaml", oauth.AuthMethodPrivateKeyJWT, oauthClient)
+
+		// when
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: secretName})
+
+		// then
+		require.NoError(t, err)
+		assert.NoError(t, oauthClient.CredentialsValid())
+	})
+
+	t.Run("should invalidate credentials when Secret content is invalid", func(t *testing.T) {
+		// given
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName.Name, Namespace: secretName.Namespace},
+			Data:       map[string][]byte{"director.yaml": []byte(`data: {}`)},
+		}
+		oauthClient := oauth.NewReloadableOauthClient(nil)
+		oauthClient.UpdateCredentials("id", "secret", "https://example.com/oauth2/token")
+		reconciler := NewDirectorCredentialsReconciler(fake.NewClientBuilder().WithObjects(secret).Build(), logrus.New(), secretName, "director.yaml", oauth.AuthMethodClientSecretBasic, oauthClient)
+
+		// when
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: secretName})
+
+		// then
+		require.NoError(t, err)
+		assert.ErrorContains(t, oauthClient.CredentialsValid(), "invalid Director credentials")
+	})
+
+	t.Run("should invalidate credentials when Secret is deleted", func(t *testing.T) {
+		// given
+		oauthClient := oauth.NewReloadableOauthClient(nil)
+		oauthClient.UpdateCredentials("id", "secret", "https://example.com/oauth2/token")
+		reconciler := NewDirectorCredentialsReconciler(fake.NewClientBuilder().Build(), logrus.New(), secretName, "director.yaml", oauth.AuthMethodClientSecretBasic, oauthClient)
+
+		// when
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: secretName})
+
+		// then
+		require.NoError(t, err)
+		assert.ErrorContains(t, oauthClient.CredentialsValid(), "not found")
+	})
+}
@@ -13,17 +13,38 @@ type DryRunner struct {
 	log *logrus.Logger
 }
 
-func (dr DryRunner) ConfigureCompassRuntimeAgent(_ []byte, compassRuntimeID, globalAccount string) error {
+func (dr DryRunner) ConfigureCompassRuntimeAgent(_ string, _ []byte, compassRuntimeID, globalAccount, _ string) error {
 	dr.log.Infof("[DRY] Configure runtime %s for GA %s", compassRuntimeID, globalAccount)
 	return nil
 }
 
-func (dr DryRunner) RegisterInCompass(compassRuntimeLabels map[string]interface{}) (string, error) {
+func (dr DryRunner) RegisterInCompass(_ string, _ map[string]interface{}, tenant, _ string) (string, error) {
 	compassID := uuid.New().String()
-	dr.log.Infof("[DRY] Register runtime %s: %s", compassRuntimeLabels["global_account_id"], compassID)
+	dr.log.Infof("[DRY] Register runtime %s: %s", tenant, compassID)
 	return compassID, nil
 }
-func (dr DryRunner) DeregisterFromCompass(compassID, globalAccount string) error {
+func (dr DryRunner) DeregisterFromCompass(_, compassID, globalAccount, _ string) error {
 	dr.log.Infof("[DRY] Register runtime, GA: %s Compass ID: %s", globalAccount, compassID)
 	return nil
 }
+
+func (dr DryRunner) CreateRuntimeContext(_, compassID, subaccount, _, _ string) (string, error) {
+	runtimeContextID := uuid.New().String()
+	dr.log.Infof("[DRY] Create context of runtime %s for subaccount %s: %s", compassID, subaccount, runtimeContextID)
+	return runtimeContextID, nil
+}
+
+func (dr DryRunner) AssignFormation(_, compassID, formation, tenant, _ string) error {
+	dr.log.Infof("[DRY] Assign runtime %s to formation %s, tenant: %s", compassID, formation, tenant)
+	return nil
+}
+
+func (dr DryRunner) UnassignFormation(_, compassID, formation, tenant, _ string) error {
+	dr.log.Infof("[DRY] Unassign runtime %s from formation %s, tenant: %s", compassID, formation, tenant)
+	return nil
+}
+
+func (dr DryRunner) DeleteRuntimeContext(_, runtimeContextID, tenant, _ string) error {
+	dr.log.Infof("[DRY] Delete runtime context, tenant: %s Runtime context ID: %s", tenant, runtimeContextID)
+	return nil
+}
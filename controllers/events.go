@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch,namespace=kcp-system
+
+// Reasons and actions of Warning events recorded for the mapping of a Kyma when one of the steps of its reconciliation fails
+const (
+	ReasonRegistrationFailed    = "RegistrationFailed"
+	ReasonDeregistrationFailed  = "DeregistrationFailed"
+	ReasonConfigurationFailed   = "ConfigurationFailed"
+	ReasonRuntimeContextsFailed = "RuntimeContextsFailed"
+	ReasonFormationsFailed      = "FormationsFailed"
+	ReasonOperationFailed       = "OperationFailed"
+	// ReasonOperationSucceeded is the reason of the Normal event of an operation requested with AnnotationCompassOperation
+	ReasonOperationSucceeded = "OperationSucceeded"
+
+	ActionRegister              = "Register"
+	ActionDeregister            = "Deregister"
+	ActionConfigure             = "Configure"
+	ActionUpdateRuntimeContexts = "UpdateRuntimeContexts"
+	ActionUpdateFormations      = "UpdateFormations"
+	ActionPerformOperation      = "PerformOperation"
+
+	// maxEventNoteLength is the limit of the API server for the note of an event
+	maxEventNoteLength = 1024
+)
+
+// recordFailure records the error as a Warning event of the mapping of the Kyma, so that last errors are shown together with the mapping
+func (cm *CompassManagerReconciler) recordFailure(kymaName types.NamespacedName, reason, action string, err error) {
+	cm.recordEvent(kymaName, corev1.EventTypeWarning, reason, action, err.Error())
+}
+
+// recordEvent records the event of the mapping of the Kyma, its note is cut to the length accepted by the API server
+func (cm *CompassManagerReconciler) recordEvent(kymaName types.NamespacedName, eventType, reason, action, note string) {
+	if cm.recorder == nil {
+		return
+	}
+
+	mapping, err := cm.cluster.GetCompassMapping(kymaName)
+	if err != nil {
+		cm.Log.Warnf("Failed to record %s event for Kyma resource %s: %v", reason, kymaName.Name, err)
+		return
+	}
+
+	if len(note) > maxEventNoteLength {
+		note = note[:maxEventNoteLength]
+	}
+	cm.recorder.Eventf(&mapping, nil, eventType, reason, action, "%s", note)
+}
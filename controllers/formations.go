@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	s "github.com/kyma-project/compass-manager/controllers/status"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// updateFormations assigns the Runtime to formations of the policy, the default formations and the ones listed on the Kyma, and unassigns it from formations removed from the policy.
+// Formations changed before a failure are stored in the mapping status too.
+func (cm *CompassManagerReconciler) updateFormations(kymaName types.NamespacedName, kymaAnnotations map[string]string, mapping v1beta1.CompassManagerMapping, compassRuntimeID, tenant, director string) error {
+	policy := formationPolicy(cm.defaultFormations, kymaAnnotations[AnnotationCompassFormations])
+	formations, err := cm.changeFormations(kymaName, mapping.Status.Formations, policy, compassRuntimeID, tenant, director)
+
+	if !slices.Equal(formations, mapping.Status.Formations) {
+		if statErr := cm.cluster.SetCompassMappingFormations(kymaName, formations); statErr != nil {
+			return errors.Wrap(statErr, "failed to store formations in Compass Manager Mapping status")
+		}
+	}
+	return err
+}
+
+func (cm *CompassManagerReconciler) changeFormations(kymaName types.NamespacedName, assigned, policy []string, compassRuntimeID, tenant, director string) ([]string, error) {
+	var formations []string
+	for i, formation := range assigned {
+		if slices.Contains(policy, formation) {
+			formations = append(formations, formation)
+			continue
+		}
+
+		cm.Log.Infof("Unassigning Runtime %s from formation %s", compassRuntimeID, formation)
+		if err := cm.Registrator.UnassignFormation(kymaName.Name, compassRuntimeID, formation, tenant, director); err != nil {
+			return append(formations, assigned[i:]...), errors.Wrapf(err, "failed to unassign Runtime from formation %s", formation)
+		}
+	}
+
+	for _, formation := range policy {
+		if slices.Contains(formations, formation) {
+			continue
+		}
+
+		cm.Log.Infof("Assigning Runtime %s to formation %s", compassRuntimeID, formation)
+		if err := cm.Registrator.AssignFormation(kymaName.Name, compassRuntimeID, formation, tenant, director); err != nil {
+			return formations, errors.Wrapf(err, "failed to assign Runtime to formation %s", formation)
+		}
+		formations = append(formations, formation)
+	}
+
+	return formations, nil
+}
+
+// unassignAllFormations unassigns the Runtime from formations stored in the mapping status before it's deregistered
+func (cm *CompassManagerReconciler) unassignAllFormations(kymaName types.NamespacedName, mapping v1beta1.CompassManagerMapping, compassRuntimeID, director string) error {
+	for _, formation := range mapping.Status.Formations {
+		cm.Log.Infof("Unassigning Runtime %s from formation %s", compassRuntimeID, formation)
+		if err := cm.Registrator.UnassignFormation(kymaName.Name, compassRuntimeID, formation, MappingTenant(mapping), director); err != nil {
+			return errors.Wrapf(err, "failed to unassign Runtime from formation %s", formation)
+		}
+	}
+	return nil
+}
+
+func (cm *CompassManagerReconciler) failFormationsAndRequeue(kymaName types.NamespacedName, err error, director string) (ctrl.Result, error) {
+	cm.Log.Errorf("Failed attempt to update formations for Kyma resource %s: %v", kymaName.Name, err)
+	cm.recordFailure(kymaName, ReasonFormationsFailed, ActionUpdateFormations, err)
+
+	statErr := cm.cluster.SetCompassMappingStatus(kymaName, s.Registered|s.Failed)
+	if statErr != nil {
+		return ctrl.Result{Requeue: true}, errors.Wrap(statErr, "failed to set Compass Manager Status after failed attempt to update formations")
+	}
+
+	if isDirectorCircuitOpen(err) {
+		return ctrl.Result{RequeueAfter: cm.requeueTimeForDirectorError(err, director)}, nil
+	}
+
+	return ctrl.Result{Requeue: true}, errors.Wrapf(err, "failed attempt to update formations for Kyma resource %s", kymaName.Name)
+}
+
+// formationPolicy returns the default formations followed by the formations listed on the Kyma, without duplicates
+func formationPolicy(defaultFormations []string, annotation string) []string {
+	var formations []string
+	for _, formation := range append(slices.Clone(defaultFormations), strings.Split(annotation, ",")...) {
+		formation = strings.TrimSpace(formation)
+		if formation == "" || slices.Contains(formations, formation) {
+			continue
+		}
+		formations = append(formations, formation)
+	}
+	return formations
+}
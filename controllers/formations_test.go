@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kyma-project/compass-manager/controllers/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestUpdateFormations(t *testing.T) {
+	kymaName := types.NamespacedName{Name: "kyma", Namespace: "kcp-system"}
+
+	t.Run("should assign Runtime to formations of the policy and unassign it from removed ones", func(t *testing.T) {
+		// given
+		mapping := newRuntimeContextsMapping(kymaName)
+		mapping.Status.Formations = []string{"removed", "kept"}
+		registrator := mocks.NewRegistrator(t)
+		registrator.On("UnassignFormation", "kyma", "runtime-id", "removed", "globalAccount", "").Return(nil)
+		registrator.On("AssignFormation", "kyma", "runtime-id", "default", "globalAccount", "").Return(nil)
+		registrator.On("AssignFormation", "kyma", "runtime-id", "added", "globalAccount", "").Return(nil)
+		reconciler := newRuntimeContextsReconciler(t, registrator, mapping)
+		reconciler.defaultFormations = []string{"default"}
+
+		// when
+		err := reconciler.updateFormations(kymaName, map[string]string{AnnotationCompassFormations: "kept, added,default"}, *mapping, "runtime-id", "globalAccount", "")
+
+		// then
+		require.NoError(t, err)
+		stored, err := reconciler.cluster.GetCompassMapping(kymaName)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"kept", "default", "added"}, stored.Status.Formations)
+	})
+
+	t.Run("should store formations assigned before a failure", func(t *testing.T) {
+		// given
+		mapping := newRuntimeContextsMapping(kymaName)
+		registrator := mocks.NewRegistrator(t)
+		registrator.On("AssignFormation", "kyma", "runtime-id", "first", "globalAccount", "").Return(nil)
+		registrator.On("AssignFormation", "kyma", "runtime-id", "second", "globalAccount", "").Return(errors.New("formation not found"))
+		reconciler := newRuntimeContextsReconciler(t, registrator, mapping)
+
+		// when
+		err := reconciler.updateFormations(kymaName, map[string]string{AnnotationCompassFormations: "first,second"}, *mapping, "runtime-id", "globalAccount", "")
+
+		// then
+		require.ErrorContains(t, err, "formation not found")
+		stored, err := reconciler.cluster.GetCompassMapping(kymaName)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"first"}, stored.Status.Formations)
+	})
+
+	t.Run("should unassign Runtime from all formations before deregistration", func(t *testing.T) {
+		// given
+		mapping := newRuntimeContextsMapping(kymaName)
+		mapping.Labels[LabelGlobalAccountID] = "globalAccount"
+		mapping.Labels[LabelCompassTenant] = "subaccount"
+		mapping.Status.Formations = []string{"first", "second"}
+		registrator := mocks.NewRegistrator(t)
+		registrator.On("UnassignFormation", "kyma", "runtime-id", "first", "subaccount", "").Return(nil)
+		registrator.On("UnassignFormation", "kyma", "runtime-id", "second", "subaccount", "").Return(nil)
+		reconciler := newRuntimeContextsReconciler(t, registrator, mapping)
+
+		// when
+		err := reconciler.unassignAllFormations(kymaName, *mapping, "runtime-id", "")
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func TestFormationPolicy(t *testing.T) {
+	assert.Empty(t, formationPolicy(nil, ""))
+	assert.Equal(t, []string{"default", "annotated"}, formationPolicy([]string{"default"}, " annotated,,default"))
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// kymaLocks serialise reconciliations of a Kyma with operations performed on it outside of the workqueue, e.g. requested through the API.
+// The zero value is ready to use, locks are removed once nobody holds or waits for them.
+type kymaLocks struct {
+	mu    sync.Mutex
+	locks map[types.NamespacedName]*kymaLock
+}
+
+type kymaLock struct {
+	sync.Mutex
+	holders int
+}
+
+// lock waits until nobody else holds the lock of the Kyma, and returns the function releasing it
+func (l *kymaLocks) lock(kymaName types.NamespacedName) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[types.NamespacedName]*kymaLock{}
+	}
+	kl := l.locks[kymaName]
+	if kl == nil {
+		kl = &kymaLock{}
+		l.locks[kymaName] = kl
+	}
+	kl.holders++
+	l.mu.Unlock()
+
+	kl.Lock()
+	return func() {
+		kl.Unlock()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		kl.holders--
+		if kl.holders == 0 {
+			delete(l.locks, kymaName)
+		}
+	}
+}
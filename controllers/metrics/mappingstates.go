@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// mappingStates counts mappings by their state and the global account of their Kyma, computed from the mappings on every scrape.
+// Mappings without a state, which weren't reconciled yet, aren't counted.
+type mappingStates struct {
+	reader             client.Reader
+	namespace          string
+	globalAccountLabel string
+	desc               *prometheus.Desc
+}
+
+// RegisterMappingStates registers, next to the metrics, the number of mappings by state and global account read from the label of mappings,
+// which replaces cm_states when the metrics are created WithoutKymaNames
+func (m Metrics) RegisterMappingStates(reader client.Reader, namespace, globalAccountLabel string) {
+	metrics.Registry.MustRegister(newMappingStates(reader, namespace, globalAccountLabel))
+}
+
+func newMappingStates(reader client.Reader, namespace, globalAccountLabel string) *mappingStates {
+	return &mappingStates{
+		reader:             reader,
+		namespace:          namespace,
+		globalAccountLabel: globalAccountLabel,
+		desc: prometheus.NewDesc(
+			MetricMappingStates,
+			"Number of Compass Mappings in the <state>, by the global account of their Kymas",
+			[]string{LabelState, LabelAccount}, nil,
+		),
+	}
+}
+
+func (c *mappingStates) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *mappingStates) Collect(ch chan<- prometheus.Metric) {
+	mappings := v1beta1.CompassManagerMappingList{}
+	if err := c.reader.List(context.Background(), &mappings, client.InNamespace(c.namespace)); err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+
+	type key struct {
+		state         string
+		globalAccount string
+	}
+	counts := map[key]int{}
+	for _, mapping := range mappings.Items {
+		if mapping.Status.State == "" {
+			continue
+		}
+		counts[key{mapping.Status.State, mapping.Labels[c.globalAccountLabel]}]++
+	}
+
+	for k, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), k.state, k.globalAccount)
+	}
+}
@@ -1,18 +1,40 @@
 package metrics
 
 import (
+	"strconv"
+	"time"
+
 	s "github.com/kyma-project/compass-manager/controllers/status"
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/kyma-project/compass-manager/internal/director"
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 const (
-	MetricState   = "cm_states"
-	MetricActions = "cm_actions"
+	MetricState                 = "cm_states"
+	MetricActions               = "cm_actions"
+	MetricDirectorCircuitStates = "cm_director_circuit_states"
+	MetricDirectorThrottleWait  = "cm_director_throttle_wait_seconds"
+	MetricDirectorRequests      = "cm_director_request_duration_seconds"
+	MetricDirectorErrors        = "cm_director_errors"
+	MetricTokenFetches          = "cm_director_token_fetch_duration_seconds"
+	MetricTokenFetchFailures    = "cm_director_token_fetch_failures"
+	MetricOneTimeTokens         = "cm_one_time_tokens"
+	MetricTimeToReady           = "cm_time_to_ready_seconds"
+	MetricStuckKymas            = "cm_stuck_kymas"
+	MetricMappingStates         = "cm_mapping_states"
 
-	LabelState  = "state"
-	LabelName   = "kyma_name"
-	LabelAction = "action"
+	LabelState     = "state"
+	LabelName      = "kyma_name"
+	LabelAction    = "action"
+	LabelLimit     = "limit"
+	LabelDirector  = "director"
+	LabelOperation = "operation"
+	LabelCode      = "code"
+	LabelReason    = "reason"
+	LabelComponent = "component"
+	LabelAccount   = "global_account"
 
 	ActionRegister   = "register"
 	ActionConfigure  = "configure"
@@ -20,56 +42,206 @@ const (
 )
 
 type Metrics struct {
-	states  *prometheus.GaugeVec
-	actions *prometheus.CounterVec
+	kymaNames     bool
+	states        *prometheus.GaugeVec
+	actions       *prometheus.CounterVec
+	circuitStates *prometheus.GaugeVec
+	throttleWait  *prometheus.HistogramVec
+	requests      *prometheus.HistogramVec
+	errors        *prometheus.CounterVec
+	tokenFetches  *prometheus.HistogramVec
+	tokenFailures *prometheus.CounterVec
+	oneTimeTokens *prometheus.CounterVec
+	timeToReady   prometheus.Histogram
+}
+
+// Option configures Metrics
+type Option func(*Metrics)
+
+// WithoutKymaNames drops the kyma_name label, so that the number of series doesn't grow with the number of Kymas.
+// cm_states isn't exported, cm_actions counts actions on all Kymas, and cm_mapping_states can be registered with RegisterMappingStates instead.
+func WithoutKymaNames() Option {
+	return func(m *Metrics) {
+		m.kymaNames = false
+	}
+}
+
+// NewMetrics creates the metrics and registers them in the controller-runtime registry
+func NewMetrics(opts ...Option) Metrics {
+	m := newMetrics(opts...)
+	metrics.Registry.MustRegister(m.collectors()...)
+	return m
 }
 
-func NewMetrics() Metrics {
+func newMetrics(opts ...Option) Metrics {
 	m := Metrics{
-		states: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		kymaNames: true,
+
+		circuitStates: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: MetricDirectorCircuitStates,
+			Help: "Indicates the state of the circuit breaker guarding calls to Director",
+		}, []string{LabelDirector, LabelState}),
+
+		throttleWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    MetricDirectorThrottleWait,
+			Help:    "Time Director requests spent waiting for the <limit> of the client-side request budget",
+			Buckets: []float64{0.001, 0.01, 0.1, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{LabelDirector, LabelLimit}),
+
+		requests: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    MetricDirectorRequests,
+			Help:    "Duration of requests sent to Director, by the <operation> they call",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{LabelDirector, LabelOperation}),
+
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: MetricDirectorErrors,
+			Help: "Number of failed Director operations, by the <code>, <reason> and <component> of the error",
+		}, []string{LabelDirector, LabelOperation, LabelCode, LabelReason, LabelComponent}),
+
+		tokenFetches: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    MetricTokenFetches,
+			Help:    "Duration of requests for OAuth tokens to access Director",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{LabelDirector}),
+
+		tokenFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: MetricTokenFetchFailures,
+			Help: "Number of failed requests for OAuth tokens to access Director",
+		}, []string{LabelDirector}),
+
+		oneTimeTokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: MetricOneTimeTokens,
+			Help: "Number of one-time tokens issued by Director for the Compass Runtime Agent",
+		}, []string{LabelDirector}),
+
+		timeToReady: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    MetricTimeToReady,
+			Help:    "Time from the creation of the Compass Mapping, when application-connector is enabled, to the first configuration of the Compass Runtime Agent",
+			Buckets: []float64{30, 60, 120, 300, 600, 900, 1800, 3600, 10800},
+		}),
+	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+
+	actionLabels := []string{LabelAction}
+	if m.kymaNames {
+		actionLabels = []string{LabelName, LabelAction}
+		m.states = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: MetricState,
 			Help: "Indicates the Status.state for Compass Mappings",
-		}, []string{LabelName, LabelState}),
-
-		actions: prometheus.NewCounterVec(prometheus.CounterOpts{
-			Name: MetricActions,
-			Help: "Number of <action> performed on Kymas",
-		}, []string{LabelName, LabelAction}),
+		}, []string{LabelName, LabelState})
 	}
-	metrics.Registry.MustRegister(m.states, m.actions)
+	m.actions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: MetricActions,
+		Help: "Number of <action> performed on Kymas",
+	}, actionLabels)
 	return m
 }
 
+func (m Metrics) collectors() []prometheus.Collector {
+	collectors := []prometheus.Collector{m.actions, m.circuitStates, m.throttleWait, m.requests, m.errors, m.tokenFetches, m.tokenFailures, m.oneTimeTokens, m.timeToReady}
+	if m.kymaNames {
+		collectors = append(collectors, m.states)
+	}
+	return collectors
+}
+
 func (m Metrics) IncConfigure(kymaName string) {
-	m.actions.With(prometheus.Labels{
-		LabelName:   kymaName,
-		LabelAction: ActionConfigure,
-	}).Inc()
+	m.incAction(kymaName, ActionConfigure)
 }
 
 func (m Metrics) IncRegister(kymaName string) {
-	m.actions.With(prometheus.Labels{
-		LabelName:   kymaName,
-		LabelAction: ActionRegister,
-	}).Inc()
+	m.incAction(kymaName, ActionRegister)
 }
 
 func (m Metrics) IncUnregister(kymaName string) {
-	m.actions.With(prometheus.Labels{
-		LabelName:   kymaName,
-		LabelAction: ActionUnregister,
-	}).Inc()
+	m.incAction(kymaName, ActionUnregister)
 }
 
+// UpdateState sets the state of the Kyma, series of the Kyma are deleted when the status is Empty
 func (m Metrics) UpdateState(kymaName string, status s.Status) {
+	if !m.kymaNames {
+		return
+	}
 	if status == s.Empty {
-		m.setModuleStateGauge(kymaName, "")
+		m.states.DeletePartialMatch(prometheus.Labels{LabelName: kymaName})
 		return
 	}
 	state := s.StateText(status)
 	m.setModuleStateGauge(kymaName, state)
 }
 
+// DeleteKyma deletes all series of the deleted Kyma
+func (m Metrics) DeleteKyma(kymaName string) {
+	if !m.kymaNames {
+		return
+	}
+	m.states.DeletePartialMatch(prometheus.Labels{LabelName: kymaName})
+	m.actions.DeletePartialMatch(prometheus.Labels{LabelName: kymaName})
+}
+
+func (m Metrics) UpdateDirectorCircuitState(directorName string, state director.BreakerState) {
+	for _, s := range []director.BreakerState{director.BreakerClosed, director.BreakerOpen, director.BreakerHalfOpen} {
+		val := 0.0
+		if s == state {
+			val = 1
+		}
+		m.circuitStates.With(prometheus.Labels{
+			LabelDirector: directorName,
+			LabelState:    string(s),
+		}).Set(val)
+	}
+}
+
+func (m Metrics) ObserveDirectorThrottleWait(directorName, limit string, waited time.Duration) {
+	m.throttleWait.With(prometheus.Labels{
+		LabelDirector: directorName,
+		LabelLimit:    limit,
+	}).Observe(waited.Seconds())
+}
+
+func (m Metrics) ObserveDirectorRequest(directorName, operation string, duration time.Duration) {
+	m.requests.With(prometheus.Labels{
+		LabelDirector:  directorName,
+		LabelOperation: operation,
+	}).Observe(duration.Seconds())
+}
+
+func (m Metrics) IncDirectorError(directorName, operation string, err apperrors.AppError) {
+	m.errors.With(prometheus.Labels{
+		LabelDirector:  directorName,
+		LabelOperation: operation,
+		LabelCode:      strconv.Itoa(int(err.Code())),
+		LabelReason:    string(err.Reason()),
+		LabelComponent: string(err.Component()),
+	}).Inc()
+}
+
+func (m Metrics) ObserveTokenFetch(directorName string, duration time.Duration, err apperrors.AppError) {
+	m.tokenFetches.With(prometheus.Labels{LabelDirector: directorName}).Observe(duration.Seconds())
+	if err != nil {
+		m.tokenFailures.With(prometheus.Labels{LabelDirector: directorName}).Inc()
+	}
+}
+
+func (m Metrics) IncOneTimeTokens(directorName string) {
+	m.oneTimeTokens.With(prometheus.Labels{LabelDirector: directorName}).Inc()
+}
+
+func (m Metrics) ObserveTimeToReady(duration time.Duration) {
+	m.timeToReady.Observe(duration.Seconds())
+}
+
+func (m Metrics) incAction(kymaName, action string) {
+	labels := prometheus.Labels{LabelAction: action}
+	if m.kymaNames {
+		labels[LabelName] = kymaName
+	}
+	m.actions.With(labels).Inc()
+}
+
 func (m Metrics) setModuleStateGauge(kymaName, state string) {
 	for _, s := range []string{s.ReadyState, s.FailedState, s.ProcessingState} {
 		val := 0.0
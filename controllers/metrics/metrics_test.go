@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	s "github.com/kyma-project/compass-manager/controllers/status"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMetrics_KymaSeries(t *testing.T) {
+	t.Run("should delete series of the deleted Kyma", func(t *testing.T) {
+		// given
+		m := newMetrics()
+		m.IncRegister("deleted")
+		m.UpdateState("deleted", s.Registered|s.Processing)
+		m.IncRegister("kept")
+		m.UpdateState("kept", s.Registered|s.Configured)
+
+		// when
+		m.DeleteKyma("deleted")
+
+		// then
+		series := gather(t, m.collectors()...)
+		assert.Equal(t, []map[string]string{{LabelName: "kept", LabelAction: ActionRegister}}, series[MetricActions])
+		assert.Len(t, series[MetricState], 3)
+		for _, labels := range series[MetricState] {
+			assert.Equal(t, "kept", labels[LabelName])
+		}
+	})
+
+	t.Run("should delete state series of Kyma with empty status", func(t *testing.T) {
+		// given
+		m := newMetrics()
+		m.IncUnregister("kyma")
+		m.UpdateState("kyma", s.Registered|s.Configured)
+
+		// when
+		m.UpdateState("kyma", s.Empty)
+
+		// then
+		series := gather(t, m.collectors()...)
+		assert.Empty(t, series[MetricState])
+		assert.Len(t, series[MetricActions], 1)
+	})
+
+	t.Run("should count actions of all Kymas without Kyma names", func(t *testing.T) {
+		// given
+		m := newMetrics(WithoutKymaNames())
+
+		// when
+		m.IncRegister("first")
+		m.IncRegister("second")
+		m.UpdateState("first", s.Registered|s.Processing)
+		m.DeleteKyma("first")
+
+		// then
+		series := gather(t, m.collectors()...)
+		assert.Equal(t, []map[string]string{{LabelAction: ActionRegister}}, series[MetricActions])
+		assert.NotContains(t, series, MetricState)
+	})
+}
+
+func TestMappingStates(t *testing.T) {
+	// given
+	const globalAccountLabel = "kyma-project.io/global-account-id"
+	newMapping := func(name, globalAccount, state string) client.Object {
+		return &v1beta1.CompassManagerMapping{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kcp-system", Labels: map[string]string{globalAccountLabel: globalAccount}},
+			Status:     v1beta1.CompassManagerMappingStatus{State: state},
+		}
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newMapping("first", "globalAccount", s.ReadyState),
+		newMapping("second", "globalAccount", s.ReadyState),
+		newMapping("third", "globalAccount", s.FailedState),
+		newMapping("other", "otherAccount", s.ReadyState),
+		newMapping("new", "otherAccount", ""),
+	).Build()
+
+	// when
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(newMappingStates(reader, "kcp-system", globalAccountLabel))
+	families, err := registry.Gather()
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	counts := map[string]float64{}
+	for _, metric := range families[0].GetMetric() {
+		labels := map[string]string{}
+		for _, label := range metric.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		counts[labels[LabelAccount]+"/"+labels[LabelState]] = metric.GetGauge().GetValue()
+	}
+	assert.Equal(t, map[string]float64{
+		"globalAccount/Ready":  2,
+		"globalAccount/Failed": 1,
+		"otherAccount/Ready":   1,
+	}, counts)
+}
+
+// gather returns labels of series of the collectors by the metric name
+func gather(t *testing.T, collectors ...prometheus.Collector) map[string][]map[string]string {
+	t.Helper()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors...)
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	series := map[string][]map[string]string{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			series[family.GetName()] = append(series[family.GetName()], labels)
+		}
+	}
+	return series
+}
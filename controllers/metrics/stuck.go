@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	s "github.com/kyma-project/compass-manager/controllers/status"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// stuckKymas counts Kymas whose mappings are Processing or Failed for longer than the threshold.
+// It's computed from the mappings on every scrape, so that Kymas stuck before a restart of the controller are counted too.
+type stuckKymas struct {
+	reader    client.Reader
+	namespace string
+	threshold time.Duration
+	now       func() time.Time
+	desc      *prometheus.Desc
+}
+
+// RegisterStuckKymas registers, next to the metrics, the gauge of Kymas stuck in the Processing or Failed state for longer than the threshold
+func (m Metrics) RegisterStuckKymas(reader client.Reader, namespace string, threshold time.Duration) {
+	metrics.Registry.MustRegister(newStuckKymas(reader, namespace, threshold, time.Now))
+}
+
+func newStuckKymas(reader client.Reader, namespace string, threshold time.Duration, now func() time.Time) *stuckKymas {
+	return &stuckKymas{
+		reader:    reader,
+		namespace: namespace,
+		threshold: threshold,
+		now:       now,
+		desc: prometheus.NewDesc(
+			MetricStuckKymas,
+			"Number of Kymas whose Compass Mappings are in the <state> for longer than the threshold",
+			[]string{LabelState}, nil,
+		),
+	}
+}
+
+func (c *stuckKymas) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *stuckKymas) Collect(ch chan<- prometheus.Metric) {
+	mappings := v1beta1.CompassManagerMappingList{}
+	if err := c.reader.List(context.Background(), &mappings, client.InNamespace(c.namespace)); err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+
+	counts := map[string]int{s.ProcessingState: 0, s.FailedState: 0}
+	for _, mapping := range mappings.Items {
+		if _, counted := counts[mapping.Status.State]; !counted {
+			continue
+		}
+		// mappings written before NotReadySince was stored are not ready since their creation
+		since := mapping.CreationTimestamp.Time
+		if mapping.Status.NotReadySince != nil {
+			since = mapping.Status.NotReadySince.Time
+		}
+		if c.now().Sub(since) > c.threshold {
+			counts[mapping.Status.State]++
+		}
+	}
+
+	for state, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), state)
+	}
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	s "github.com/kyma-project/compass-manager/controllers/status"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestStuckKymas(t *testing.T) {
+	// given
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	newMapping := func(name, state string, created time.Time, notReadySince *time.Time) client.Object {
+		mapping := &v1beta1.CompassManagerMapping{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kcp-system", CreationTimestamp: metav1.NewTime(created)},
+			Status:     v1beta1.CompassManagerMappingStatus{State: state},
+		}
+		if notReadySince != nil {
+			since := metav1.NewTime(*notReadySince)
+			mapping.Status.NotReadySince = &since
+		}
+		return mapping
+	}
+	longAgo := now.Add(-time.Hour)
+	recently := now.Add(-time.Minute)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newMapping("stuck-processing", s.ProcessingState, longAgo, &longAgo),
+		newMapping("stuck-failed", s.FailedState, longAgo, &longAgo),
+		newMapping("failed-before-upgrade", s.FailedState, longAgo, nil),
+		newMapping("failed-recently", s.FailedState, longAgo, &recently),
+		newMapping("created-recently", s.ProcessingState, recently, nil),
+		newMapping("ready", s.ReadyState, longAgo, nil),
+	).Build()
+
+	collector := newStuckKymas(reader, "kcp-system", 15*time.Minute, func() time.Time { return now })
+
+	// when
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+	families, err := registry.Gather()
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	stuck := map[string]float64{}
+	for _, metric := range families[0].GetMetric() {
+		stuck[metric.GetLabel()[0].GetValue()] = metric.GetGauge().GetValue()
+	}
+	assert.Equal(t, map[string]float64{s.FailedState: 2, s.ProcessingState: 1}, stuck)
+}
@@ -9,13 +9,13 @@ type Configurator struct {
 	mock.Mock
 }
 
-// ConfigureCompassRuntimeAgent provides a mock function with given fields: kubeconfig, compassRuntimeID, globalAccount
-func (_m *Configurator) ConfigureCompassRuntimeAgent(kubeconfig []byte, compassRuntimeID string, globalAccount string) error {
-	ret := _m.Called(kubeconfig, compassRuntimeID, globalAccount)
+// ConfigureCompassRuntimeAgent provides a mock function with given fields: kymaName, kubeconfig, compassRuntimeID, globalAccount, director
+func (_m *Configurator) ConfigureCompassRuntimeAgent(kymaName string, kubeconfig []byte, compassRuntimeID string, globalAccount string, director string) error {
+	ret := _m.Called(kymaName, kubeconfig, compassRuntimeID, globalAccount, director)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func([]byte, string, string) error); ok {
-		r0 = rf(kubeconfig, compassRuntimeID, globalAccount)
+	if rf, ok := ret.Get(0).(func(string, []byte, string, string, string) error); ok {
+		r0 = rf(kymaName, kubeconfig, compassRuntimeID, globalAccount, director)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -9,13 +9,13 @@ type Registrator struct {
 	mock.Mock
 }
 
-// DeregisterFromCompass provides a mock function with given fields: compassID, globalAccount
-func (_m *Registrator) DeregisterFromCompass(compassID string, globalAccount string) error {
-	ret := _m.Called(compassID, globalAccount)
+// AssignFormation provides a mock function with given fields: kymaName, compassID, formation, tenant, director
+func (_m *Registrator) AssignFormation(kymaName string, compassID string, formation string, tenant string, director string) error {
+	ret := _m.Called(kymaName, compassID, formation, tenant, director)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(compassID, globalAccount)
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string) error); ok {
+		r0 = rf(kymaName, compassID, formation, tenant, director)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -23,23 +23,23 @@ func (_m *Registrator) DeregisterFromCompass(compassID string, globalAccount str
 	return r0
 }
 
-// RegisterInCompass provides a mock function with given fields: compassRuntimeLabels
-func (_m *Registrator) RegisterInCompass(compassRuntimeLabels map[string]interface{}) (string, error) {
-	ret := _m.Called(compassRuntimeLabels)
+// CreateRuntimeContext provides a mock function with given fields: kymaName, compassID, subaccount, tenant, director
+func (_m *Registrator) CreateRuntimeContext(kymaName string, compassID string, subaccount string, tenant string, director string) (string, error) {
+	ret := _m.Called(kymaName, compassID, subaccount, tenant, director)
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(map[string]interface{}) (string, error)); ok {
-		return rf(compassRuntimeLabels)
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string) (string, error)); ok {
+		return rf(kymaName, compassID, subaccount, tenant, director)
 	}
-	if rf, ok := ret.Get(0).(func(map[string]interface{}) string); ok {
-		r0 = rf(compassRuntimeLabels)
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string) string); ok {
+		r0 = rf(kymaName, compassID, subaccount, tenant, director)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(map[string]interface{}) error); ok {
-		r1 = rf(compassRuntimeLabels)
+	if rf, ok := ret.Get(1).(func(string, string, string, string, string) error); ok {
+		r1 = rf(kymaName, compassID, subaccount, tenant, director)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -47,6 +47,72 @@ func (_m *Registrator) RegisterInCompass(compassRuntimeLabels map[string]interfa
 	return r0, r1
 }
 
+// DeleteRuntimeContext provides a mock function with given fields: kymaName, runtimeContextID, tenant, director
+func (_m *Registrator) DeleteRuntimeContext(kymaName string, runtimeContextID string, tenant string, director string) error {
+	ret := _m.Called(kymaName, runtimeContextID, tenant, director)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string) error); ok {
+		r0 = rf(kymaName, runtimeContextID, tenant, director)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeregisterFromCompass provides a mock function with given fields: kymaName, compassID, globalAccount, director
+func (_m *Registrator) DeregisterFromCompass(kymaName string, compassID string, globalAccount string, director string) error {
+	ret := _m.Called(kymaName, compassID, globalAccount, director)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string) error); ok {
+		r0 = rf(kymaName, compassID, globalAccount, director)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RegisterInCompass provides a mock function with given fields: kymaName, compassRuntimeLabels, tenant, director
+func (_m *Registrator) RegisterInCompass(kymaName string, compassRuntimeLabels map[string]interface{}, tenant string, director string) (string, error) {
+	ret := _m.Called(kymaName, compassRuntimeLabels, tenant, director)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, map[string]interface{}, string, string) (string, error)); ok {
+		return rf(kymaName, compassRuntimeLabels, tenant, director)
+	}
+	if rf, ok := ret.Get(0).(func(string, map[string]interface{}, string, string) string); ok {
+		r0 = rf(kymaName, compassRuntimeLabels, tenant, director)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, map[string]interface{}, string, string) error); ok {
+		r1 = rf(kymaName, compassRuntimeLabels, tenant, director)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnassignFormation provides a mock function with given fields: kymaName, compassID, formation, tenant, director
+func (_m *Registrator) UnassignFormation(kymaName string, compassID string, formation string, tenant string, director string) error {
+	ret := _m.Called(kymaName, compassID, formation, tenant, director)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string) error); ok {
+		r0 = rf(kymaName, compassID, formation, tenant, director)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewRegistrator creates a new instance of Registrator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRegistrator(t interface {
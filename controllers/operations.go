@@ -0,0 +1,252 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	s "github.com/kyma-project/compass-manager/controllers/status"
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	kyma "github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// Operations below are requested through the API for a single Kyma, outside of the reconciliation triggered by changes of the Kyma.
+// They hold the lock of the Kyma, which Reconcile takes too, so the controller doesn't change the mapping or the Runtime while they run.
+// Errors of Director calls are returned as they are, so that the API presents their code and reason.
+// Clients without access to the API, e.g. cmctl, request the operations with AnnotationCompassOperation, and Reconcile performs them.
+
+// Operations requested with AnnotationCompassOperation
+const (
+	OperationReregister  = "reregister"
+	OperationReconfigure = "reconfigure"
+	OperationDeregister  = "deregister"
+)
+
+// Reregister deregisters the Runtime of the Kyma, when it's registered, registers a new one and reconciles the Kyma,
+// which creates contexts of the Runtime, assigns it to formations and configures the Compass Runtime Agent
+func (cm *CompassManagerReconciler) Reregister(kymaName types.NamespacedName) error {
+	defer cm.locks.lock(kymaName)()
+	_, err := cm.reregister(kymaName)
+	return err
+}
+
+func (cm *CompassManagerReconciler) reregister(kymaName types.NamespacedName) (ctrl.Result, error) {
+	if !cm.enabledRegistration {
+		return ctrl.Result{}, apperrors.BadRequest("registration of runtimes in Compass is disabled")
+	}
+
+	kymaCR, mapping, err := cm.getKymaAndMapping(kymaName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if compassRuntimeID := mapping.Labels[LabelCompassID]; compassRuntimeID != "" {
+		if err := cm.deregisterRuntime(kymaName, mapping, compassRuntimeID); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	globalAccount := kymaCR.Labels[LabelGlobalAccountID]
+	tenant := cm.registrationTenant(kymaCR.Labels, globalAccount)
+	director := cm.selectDirector(kymaCR.Labels, globalAccount)
+
+	cm.Log.Infof("Registering new Runtime in Compass for Kyma resource %s", kymaName.Name)
+	compassRuntimeID, err := cm.Registrator.RegisterInCompass(kymaName.Name, CompassRuntimeLabels(kymaCR.Labels), tenant, director)
+	if err != nil {
+		cm.recordFailure(kymaName, ReasonRegistrationFailed, ActionRegister, err)
+		if statErr := cm.cluster.SetCompassMappingStatus(kymaName, s.Failed); statErr != nil {
+			cm.Log.Warnf("Failed to set Compass Manager Status after failed attempt to register runtime for Kyma resource %s: %v", kymaName.Name, statErr)
+		}
+		return ctrl.Result{}, errors.Wrapf(err, "failed attempt to register runtime for Kyma resource %s", kymaName.Name)
+	}
+	cm.metrics.IncRegister(kymaName.Name)
+
+	if err := cm.cluster.UpsertCompassMapping(kymaName, compassRuntimeID, director, tenant); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to update Compass Manager Mapping with RuntimeID after registration of runtime")
+	}
+	if err := cm.cluster.SetCompassMappingStatus(kymaName, s.Registered|s.Processing); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to update Compass Manager Mapping status after registration of runtime")
+	}
+	cm.metrics.UpdateState(kymaName.Name, s.Registered|s.Processing)
+
+	return cm.reconcile(context.TODO(), ctrl.Request{NamespacedName: kymaName})
+}
+
+// Reconfigure writes the configuration of the Compass Runtime Agent with a new one-time token again, and updates the mapping status
+func (cm *CompassManagerReconciler) Reconfigure(kymaName types.NamespacedName) error {
+	defer cm.locks.lock(kymaName)()
+	_, err := cm.reconfigure(kymaName)
+	return err
+}
+
+func (cm *CompassManagerReconciler) reconfigure(kymaName types.NamespacedName) (ctrl.Result, error) {
+	_, mapping, err := cm.getKymaAndMapping(kymaName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	compassRuntimeID := mapping.Labels[LabelCompassID]
+	if compassRuntimeID == "" {
+		return ctrl.Result{}, apperrors.BadRequest(fmt.Sprintf("Runtime of Kyma %s is not registered in Compass", kymaName.Name))
+	}
+
+	kubeconfig, err := cm.getKubeconfig(kymaName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return cm.configureRuntimeAndSetMappingStatus(kymaName, kubeconfig, compassRuntimeID, MappingTenant(mapping), mapping.Labels[LabelCompassDirector])
+}
+
+// RotateToken replaces the one-time token in the configuration of the Compass Runtime Agent, the Runtime must be configured already.
+// The mapping status is kept when the rotation fails, as the previous configuration is still in place.
+func (cm *CompassManagerReconciler) RotateToken(kymaName types.NamespacedName) error {
+	defer cm.locks.lock(kymaName)()
+	_, mapping, err := cm.getKymaAndMapping(kymaName)
+	if err != nil {
+		return err
+	}
+
+	compassRuntimeID := mapping.Labels[LabelCompassID]
+	if compassRuntimeID == "" || !mapping.Status.Configured {
+		return apperrors.BadRequest(fmt.Sprintf("Compass Runtime Agent of Kyma %s is not configured", kymaName.Name))
+	}
+
+	kubeconfig, err := cm.getKubeconfig(kymaName)
+	if err != nil {
+		return err
+	}
+
+	cm.Log.Infof("Rotating one-time token of Compass Runtime Agent for Runtime %s", compassRuntimeID)
+	err = cm.Configurator.ConfigureCompassRuntimeAgent(kymaName.Name, kubeconfig, compassRuntimeID, MappingTenant(mapping), mapping.Labels[LabelCompassDirector])
+	if err != nil {
+		return errors.Wrapf(err, "failed attempt to rotate one-time token of Compass Runtime Agent for Kyma resource %s", kymaName.Name)
+	}
+	return nil
+}
+
+// Deregister deletes the Runtime of the Kyma from Compass and resets the mapping.
+// The Kyma is registered again on its next reconciliation when registration is enabled.
+func (cm *CompassManagerReconciler) Deregister(kymaName types.NamespacedName) error {
+	defer cm.locks.lock(kymaName)()
+	return cm.deregister(kymaName)
+}
+
+func (cm *CompassManagerReconciler) deregister(kymaName types.NamespacedName) error {
+	mapping, err := cm.cluster.GetCompassMapping(kymaName)
+	if IsNotFound(err) {
+		return apperrors.NotFound(fmt.Sprintf("Compass Manager Mapping for Kyma %s not found", kymaName.Name))
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to obtain Compass Manager Mapping")
+	}
+
+	compassRuntimeID := mapping.Labels[LabelCompassID]
+	if compassRuntimeID == "" {
+		return apperrors.BadRequest(fmt.Sprintf("Runtime of Kyma %s is not registered in Compass", kymaName.Name))
+	}
+
+	return cm.deregisterRuntime(kymaName, mapping, compassRuntimeID)
+}
+
+// claimRequestedOperation removes the operation requested with AnnotationCompassOperation from the Kyma, and returns it.
+// The Kyma is updated in the version it was read in, so that a request is claimed at most once.
+func (cm *CompassManagerReconciler) claimRequestedOperation(kymaName types.NamespacedName) (string, error) {
+	kymaCR, err := cm.cluster.GetKyma(kymaName)
+	if IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to obtain Kyma resource %s", kymaName.Name)
+	}
+
+	operation := kymaCR.Annotations[AnnotationCompassOperation]
+	if operation == "" {
+		return "", nil
+	}
+	if err := cm.cluster.RemoveKymaAnnotation(kymaCR, AnnotationCompassOperation); err != nil {
+		return "", errors.Wrapf(err, "failed to claim %s operation requested for Kyma resource %s", operation, kymaName.Name)
+	}
+	return operation, nil
+}
+
+// performRequestedOperation performs the operation and records its outcome as an event of the mapping.
+// A failed operation is followed by the reconciliation of the Kyma, while a deregistered Kyma is registered again on its next reconciliation only, as with Deregister.
+func (cm *CompassManagerReconciler) performRequestedOperation(kymaName types.NamespacedName, operation string) (ctrl.Result, error) {
+	cm.Log.Infof("Performing %s operation requested for Kyma resource %s", operation, kymaName.Name)
+
+	var result ctrl.Result
+	var err error
+	switch operation {
+	case OperationReregister:
+		result, err = cm.reregister(kymaName)
+	case OperationReconfigure:
+		result, err = cm.reconfigure(kymaName)
+	case OperationDeregister:
+		err = cm.deregister(kymaName)
+	default:
+		err = apperrors.BadRequest(fmt.Sprintf("unknown operation %q, expected %s, %s or %s", operation, OperationReregister, OperationReconfigure, OperationDeregister))
+	}
+
+	if err != nil {
+		cm.recordEvent(kymaName, corev1.EventTypeWarning, ReasonOperationFailed, ActionPerformOperation, fmt.Sprintf("%s: %v", operation, err))
+		return ctrl.Result{}, errors.Wrapf(err, "failed to perform %s operation requested for Kyma resource %s", operation, kymaName.Name)
+	}
+	cm.recordEvent(kymaName, corev1.EventTypeNormal, ReasonOperationSucceeded, ActionPerformOperation, fmt.Sprintf("%s: done", operation))
+	return result, nil
+}
+
+// deregisterRuntime unassigns the Runtime from formations, deletes it from Compass, together with its contexts, and resets the mapping
+func (cm *CompassManagerReconciler) deregisterRuntime(kymaName types.NamespacedName, mapping v1beta1.CompassManagerMapping, compassRuntimeID string) error {
+	director := mapping.Labels[LabelCompassDirector]
+	if err := cm.unassignAllFormations(kymaName, mapping, compassRuntimeID, director); err != nil {
+		return errors.Wrap(err, "failed to unassign Runtime from formations")
+	}
+
+	cm.Log.Infof("Runtime deregistration in Compass for Kyma Resource %s", kymaName.Name)
+	if err := cm.Registrator.DeregisterFromCompass(kymaName.Name, compassRuntimeID, MappingTenant(mapping), director); err != nil {
+		return errors.Wrap(err, "failed to deregister Runtime from Compass")
+	}
+	cm.metrics.IncUnregister(kymaName.Name)
+	cm.metrics.UpdateState(kymaName.Name, s.Empty)
+	cm.Log.Infof("Runtime %s deregistered from Compass", compassRuntimeID)
+
+	if err := cm.cluster.ResetCompassMapping(kymaName); err != nil {
+		return errors.Wrap(err, "failed to reset Compass Manager Mapping after deregistration of runtime")
+	}
+	return nil
+}
+
+func (cm *CompassManagerReconciler) getKymaAndMapping(kymaName types.NamespacedName) (kymaCR kyma.Kyma, mapping v1beta1.CompassManagerMapping, err error) {
+	kymaCR, err = cm.cluster.GetKyma(kymaName)
+	if IsNotFound(err) {
+		return kymaCR, mapping, apperrors.NotFound(fmt.Sprintf("Kyma %s not found", kymaName.Name))
+	}
+	if err != nil {
+		return kymaCR, mapping, errors.Wrapf(err, "failed to obtain Kyma resource %s", kymaName.Name)
+	}
+
+	mapping, err = cm.cluster.GetCompassMapping(kymaName)
+	if IsNotFound(err) {
+		return kymaCR, mapping, apperrors.NotFound(fmt.Sprintf("Compass Manager Mapping for Kyma %s not found", kymaName.Name))
+	}
+	if err != nil {
+		return kymaCR, mapping, errors.Wrap(err, "failed to obtain Compass Manager Mapping")
+	}
+	return kymaCR, mapping, nil
+}
+
+func (cm *CompassManagerReconciler) getKubeconfig(kymaName types.NamespacedName) ([]byte, error) {
+	kubeconfig, err := cm.cluster.GetKubeconfig(kymaName)
+	if IsNotFound(err) || (err == nil && len(kubeconfig) == 0) {
+		return nil, apperrors.NotFound(fmt.Sprintf("Kubeconfig for Kyma %s not available", kymaName.Name))
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get Kubeconfig object for Kyma: %s", kymaName.Name)
+	}
+	return kubeconfig, nil
+}
@@ -0,0 +1,319 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/controllers/mocks"
+	s "github.com/kyma-project/compass-manager/controllers/status"
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	kyma "github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRotateToken(t *testing.T) {
+	kymaName := types.NamespacedName{Name: "kyma", Namespace: "kcp-system"}
+
+	t.Run("should configure Compass Runtime Agent of configured Runtime with new token", func(t *testing.T) {
+		// given
+		mapping := newOperationsMapping(kymaName, "runtime-id")
+		mapping.Labels[LabelCompassTenant] = "subaccount"
+		mapping.Status = v1beta1.CompassManagerMappingStatus{Registered: true, Configured: true, State: "Ready"}
+		configurator := mocks.NewConfigurator(t)
+		configurator.On("ConfigureCompassRuntimeAgent", "kyma", []byte("kubeconfig"), "runtime-id", "subaccount", "").Return(nil)
+		reconciler := newOperationsReconciler(t, configurator, mocks.NewRegistrator(t), mapping)
+
+		// when
+		err := reconciler.RotateToken(kymaName)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should refuse to rotate token of Runtime which isn't configured", func(t *testing.T) {
+		// given
+		mapping := newOperationsMapping(kymaName, "runtime-id")
+		mapping.Status = v1beta1.CompassManagerMappingStatus{Registered: true, State: "Failed"}
+		reconciler := newOperationsReconciler(t, mocks.NewConfigurator(t), mocks.NewRegistrator(t), mapping)
+
+		// when
+		err := reconciler.RotateToken(kymaName)
+
+		// then
+		var appErr apperrors.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, apperrors.CodeBadRequest, appErr.Code())
+	})
+}
+
+func TestDeregister(t *testing.T) {
+	kymaName := types.NamespacedName{Name: "kyma", Namespace: "kcp-system"}
+
+	t.Run("should refuse to deregister Runtime which isn't registered", func(t *testing.T) {
+		// given
+		reconciler := newOperationsReconciler(t, mocks.NewConfigurator(t), mocks.NewRegistrator(t), newOperationsMapping(kymaName, ""))
+
+		// when
+		err := reconciler.Deregister(kymaName)
+
+		// then
+		var appErr apperrors.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, apperrors.CodeBadRequest, appErr.Code())
+	})
+
+	t.Run("should return not found for Kyma without mapping", func(t *testing.T) {
+		// given
+		reconciler := newOperationsReconciler(t, mocks.NewConfigurator(t), mocks.NewRegistrator(t))
+
+		// when
+		err := reconciler.Deregister(kymaName)
+
+		// then
+		var appErr apperrors.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, apperrors.CodeNotFound, appErr.Code())
+	})
+}
+
+func TestReregister(t *testing.T) {
+	kymaName := types.NamespacedName{Name: "kyma", Namespace: "kcp-system"}
+
+	t.Run("should record Warning event of the mapping when registration fails", func(t *testing.T) {
+		// given
+		mapping := newOperationsMapping(kymaName, "")
+		registrator := mocks.NewRegistrator(t)
+		registrator.On("RegisterInCompass", "kyma", mock.Anything, "globalAccount", "").Return("", errors.New("director unavailable"))
+		reconciler := newOperationsReconciler(t, mocks.NewConfigurator(t), registrator, mapping)
+		reconciler.enabledRegistration = true
+		recorder := events.NewFakeRecorder(1)
+		reconciler.recorder = recorder
+
+		// when
+		err := reconciler.Reregister(kymaName)
+
+		// then
+		require.Error(t, err)
+		require.Len(t, recorder.Events, 1)
+		event := <-recorder.Events
+		assert.Contains(t, event, corev1.EventTypeWarning)
+		assert.Contains(t, event, ReasonRegistrationFailed)
+		assert.Contains(t, event, "director unavailable")
+	})
+}
+
+func TestReconcile_RequestedOperation(t *testing.T) {
+	kymaName := types.NamespacedName{Name: "kyma", Namespace: "kcp-system"}
+
+	t.Run("should claim the requested operation and record its failure", func(t *testing.T) {
+		// given
+		reconciler := newOperationsReconciler(t, mocks.NewConfigurator(t), mocks.NewRegistrator(t), newOperationsMapping(kymaName, ""))
+		recorder := events.NewFakeRecorder(1)
+		reconciler.recorder = recorder
+		requestOperation(t, reconciler, kymaName, OperationReconfigure)
+
+		// when
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: kymaName})
+
+		// then
+		var appErr apperrors.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, apperrors.CodeBadRequest, appErr.Code())
+		kymaCR, err := reconciler.cluster.GetKyma(kymaName)
+		require.NoError(t, err)
+		assert.NotContains(t, kymaCR.Annotations, AnnotationCompassOperation)
+		require.Len(t, recorder.Events, 1)
+		event := <-recorder.Events
+		assert.Contains(t, event, corev1.EventTypeWarning)
+		assert.Contains(t, event, ReasonOperationFailed)
+		assert.Contains(t, event, "reconfigure: Runtime of Kyma kyma is not registered in Compass")
+	})
+
+	t.Run("should reject unknown operation", func(t *testing.T) {
+		// given
+		reconciler := newOperationsReconciler(t, mocks.NewConfigurator(t), mocks.NewRegistrator(t), newOperationsMapping(kymaName, "runtime-id"))
+		requestOperation(t, reconciler, kymaName, "unknown")
+
+		// when
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: kymaName})
+
+		// then
+		require.ErrorContains(t, err, `unknown operation "unknown"`)
+		kymaCR, err := reconciler.cluster.GetKyma(kymaName)
+		require.NoError(t, err)
+		assert.NotContains(t, kymaCR.Annotations, AnnotationCompassOperation)
+	})
+}
+
+func TestUpdateFunc_RequestedOperation(t *testing.T) {
+	// given
+	reconciler := &CompassManagerReconciler{Log: logrus.New()}
+	newKyma := func(operation string) *kyma.Kyma {
+		kymaCR := &kyma.Kyma{Status: kyma.KymaStatus{Modules: []kyma.ModuleStatus{{Name: ApplicationConnectorModuleName}}}}
+		if operation != "" {
+			kymaCR.Annotations = map[string]string{AnnotationCompassOperation: operation}
+		}
+		return kymaCR
+	}
+
+	// then
+	assert.True(t, reconciler.UpdateFunc(newKyma(""), newKyma(OperationReregister)))
+	assert.False(t, reconciler.UpdateFunc(newKyma(OperationReregister), newKyma("")), "claiming the operation shouldn't trigger the reconciliation")
+	assert.False(t, reconciler.UpdateFunc(newKyma(OperationReregister), newKyma(OperationReregister)))
+}
+
+func TestOperations_KymaLock(t *testing.T) {
+	// given
+	kymaName := types.NamespacedName{Name: "kyma", Namespace: "kcp-system"}
+	mapping := newOperationsMapping(kymaName, "runtime-id")
+	mapping.Status = v1beta1.CompassManagerMappingStatus{Registered: true, Configured: true, State: "Ready"}
+	configured := make(chan struct{})
+	configurator := mocks.NewConfigurator(t)
+	configurator.On("ConfigureCompassRuntimeAgent", "kyma", []byte("kubeconfig"), "runtime-id", "globalAccount", "").Run(func(mock.Arguments) {
+		close(configured)
+	}).Return(nil)
+	reconciler := newOperationsReconciler(t, configurator, mocks.NewRegistrator(t), mapping)
+
+	// when
+	unlock := reconciler.locks.lock(kymaName)
+	done := make(chan error)
+	go func() {
+		done <- reconciler.RotateToken(kymaName)
+	}()
+
+	// then
+	assert.Never(t, func() bool {
+		select {
+		case <-configured:
+			return true
+		default:
+			return false
+		}
+	}, 100*time.Millisecond, 10*time.Millisecond, "operation should wait while the Kyma is reconciled")
+
+	unlock()
+	require.NoError(t, <-done)
+	assert.Empty(t, reconciler.locks.locks)
+}
+
+func TestResetCompassMapping(t *testing.T) {
+	// given
+	kymaName := types.NamespacedName{Name: "kyma", Namespace: "kcp-system"}
+	mapping := newOperationsMapping(kymaName, "runtime-id")
+	mapping.Labels[LabelCompassTenant] = "subaccount"
+	mapping.Labels[LabelCompassDirector] = "director"
+	configuredTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	mapping.Status = v1beta1.CompassManagerMappingStatus{Registered: true, Configured: true, State: "Ready", Formations: []string{"formation"}, ConfiguredTime: &configuredTime}
+	reconciler := newOperationsReconciler(t, mocks.NewConfigurator(t), mocks.NewRegistrator(t), mapping)
+
+	// when
+	err := reconciler.cluster.ResetCompassMapping(kymaName)
+
+	// then
+	require.NoError(t, err)
+	stored, err := reconciler.cluster.GetCompassMapping(kymaName)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{LabelKymaName: "kyma", LabelCompassID: "", LabelGlobalAccountID: "globalAccount"}, stored.Labels)
+	require.NotNil(t, stored.Status.ConfiguredTime)
+	assert.True(t, configuredTime.Equal(stored.Status.ConfiguredTime))
+	stored.Status.ConfiguredTime = nil
+	assert.Equal(t, v1beta1.CompassManagerMappingStatus{}, stored.Status)
+}
+
+func TestSetCompassMappingStatus(t *testing.T) {
+	// given
+	kymaName := types.NamespacedName{Name: "kyma", Namespace: "kcp-system"}
+	reconciler := newOperationsReconciler(t, mocks.NewConfigurator(t), mocks.NewRegistrator(t), newOperationsMapping(kymaName, "runtime-id"))
+	statusAfter := func(status s.Status) v1beta1.CompassManagerMappingStatus {
+		require.NoError(t, reconciler.cluster.SetCompassMappingStatus(kymaName, status))
+		mapping, err := reconciler.cluster.GetCompassMapping(kymaName)
+		require.NoError(t, err)
+		return mapping.Status
+	}
+
+	// when
+	processing := statusAfter(s.Processing)
+	failed := statusAfter(s.Registered | s.Failed)
+	ready := statusAfter(s.Registered | s.Configured)
+	failedAgain := statusAfter(s.Registered | s.Failed)
+
+	// then
+	require.NotNil(t, processing.NotReadySince)
+	assert.True(t, processing.NotReadySince.Equal(failed.NotReadySince), "the time the mapping isn't ready since is kept while it's not ready")
+	assert.Nil(t, processing.ConfiguredTime)
+
+	assert.Nil(t, ready.NotReadySince)
+	require.NotNil(t, ready.ConfiguredTime)
+
+	assert.NotNil(t, failedAgain.NotReadySince)
+	assert.True(t, ready.ConfiguredTime.Equal(failedAgain.ConfiguredTime), "the time of the first configuration is kept")
+}
+
+// requestOperation annotates the Kyma with the operation requested from the controller
+func requestOperation(t *testing.T, reconciler *CompassManagerReconciler, kymaName types.NamespacedName, operation string) {
+	t.Helper()
+	kymaCR, err := reconciler.cluster.GetKyma(kymaName)
+	require.NoError(t, err)
+	kymaCR.Annotations[AnnotationCompassOperation] = operation
+	require.NoError(t, reconciler.cluster.kubectl.Update(context.Background(), &kymaCR))
+}
+
+func newOperationsMapping(kymaName types.NamespacedName, compassID string) *v1beta1.CompassManagerMapping {
+	return &v1beta1.CompassManagerMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kymaName.Name,
+			Namespace: kymaName.Namespace,
+			Labels: map[string]string{
+				LabelKymaName:        kymaName.Name,
+				LabelCompassID:       compassID,
+				LabelGlobalAccountID: "globalAccount",
+			},
+		},
+	}
+}
+
+// newOperationsReconciler creates a reconciler of the Kyma with a kubeconfig, and the mapping when it's given
+func newOperationsReconciler(t *testing.T, configurator Configurator, registrator Registrator, mappings ...*v1beta1.CompassManagerMapping) *CompassManagerReconciler {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	require.NoError(t, kyma.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	objects := []client.Object{
+		&kyma.Kyma{ObjectMeta: metav1.ObjectMeta{
+			Name:      "kyma",
+			Namespace: "kcp-system",
+			Labels:    map[string]string{LabelKymaName: "kyma", LabelGlobalAccountID: "globalAccount"},
+		}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig-kyma", Namespace: "kcp-system", Labels: map[string]string{LabelKymaName: "kyma"}},
+			Data:       map[string][]byte{KubeconfigKey: []byte("kubeconfig")},
+		},
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...)
+	for _, mapping := range mappings {
+		builder = builder.WithObjects(mapping).WithStatusSubresource(mapping)
+	}
+
+	log := logrus.New()
+	return &CompassManagerReconciler{
+		Log:          log,
+		Configurator: configurator,
+		Registrator:  registrator,
+		cluster:      NewControlPlaneInterface(builder.Build(), log, false),
+	}
+}
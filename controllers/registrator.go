@@ -20,18 +20,23 @@ const (
 )
 
 type CompassRegistrator struct {
-	Client director.Client
-	Log    *logrus.Logger
+	Directors *director.Registry
+	Log       *logrus.Logger
 }
 
-func NewCompassRegistrator(directorClient director.Client, log *logrus.Logger) *CompassRegistrator {
+func NewCompassRegistrator(directors *director.Registry, log *logrus.Logger) *CompassRegistrator {
 	return &CompassRegistrator{
-		Client: directorClient,
-		Log:    log,
+		Directors: directors,
+		Log:       log,
 	}
 }
 
-func (r *CompassRegistrator) RegisterInCompass(compassRuntimeLabels map[string]interface{}) (string, error) {
+func (r *CompassRegistrator) RegisterInCompass(kymaName string, compassRuntimeLabels map[string]interface{}, tenant, directorName string) (string, error) {
+	directorClient, err := r.directorClient(kymaName, directorName)
+	if err != nil {
+		return "", err
+	}
+
 	var runtimeID string
 	runtimeInput, err := createRuntimeInput(compassRuntimeLabels)
 	if err != nil {
@@ -39,7 +44,7 @@ func (r *CompassRegistrator) RegisterInCompass(compassRuntimeLabels map[string]i
 	}
 
 	err = util.RetryOnError(retryTime*time.Second, attempts, "Error while registering runtime in Director: %s", func() (err apperrors.AppError) {
-		runtimeID, err = r.Client.CreateRuntime(runtimeInput, compassRuntimeLabels["global_account_id"].(string))
+		runtimeID, err = directorClient.CreateRuntime(runtimeInput, tenant)
 		return
 	})
 
@@ -50,9 +55,14 @@ func (r *CompassRegistrator) RegisterInCompass(compassRuntimeLabels map[string]i
 	return runtimeID, nil
 }
 
-func (r *CompassRegistrator) DeregisterFromCompass(compassID, globalAccount string) error {
-	err := util.RetryOnError(extendedRetryTime*time.Second, attempts, "Error while unregistering runtime in Director: %s", func() (err apperrors.AppError) {
-		err = r.Client.DeleteRuntime(compassID, globalAccount)
+func (r *CompassRegistrator) DeregisterFromCompass(kymaName, compassID, globalAccount, directorName string) error {
+	directorClient, err := r.directorClient(kymaName, directorName)
+	if err != nil {
+		return err
+	}
+
+	err = util.RetryOnError(extendedRetryTime*time.Second, attempts, "Error while unregistering runtime in Director: %s", func() (err apperrors.AppError) {
+		err = directorClient.DeleteRuntime(compassID, globalAccount)
 		return
 	})
 	if err != nil {
@@ -61,10 +71,73 @@ func (r *CompassRegistrator) DeregisterFromCompass(compassID, globalAccount stri
 	return nil
 }
 
-func (r *CompassRegistrator) RefreshCompassToken(compassID, globalAccount string) (graphql.OneTimeTokenForRuntimeExt, error) {
+func (r *CompassRegistrator) CreateRuntimeContext(kymaName, compassID, subaccount, tenant, directorName string) (string, error) {
+	directorClient, err := r.directorClient(kymaName, directorName)
+	if err != nil {
+		return "", err
+	}
+
+	var runtimeContextID string
+	err = util.RetryOnError(retryTime*time.Second, attempts, "Error while creating runtime context in Director: %s", func() (err apperrors.AppError) {
+		runtimeContextID, err = directorClient.CreateRuntimeContext(compassID, subaccount, tenant)
+		return
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return runtimeContextID, nil
+}
+
+func (r *CompassRegistrator) DeleteRuntimeContext(kymaName, runtimeContextID, tenant, directorName string) error {
+	directorClient, err := r.directorClient(kymaName, directorName)
+	if err != nil {
+		return err
+	}
+
+	err = util.RetryOnError(retryTime*time.Second, attempts, "Error while deleting runtime context in Director: %s", func() (err apperrors.AppError) {
+		err = directorClient.DeleteRuntimeContext(runtimeContextID, tenant)
+		return
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *CompassRegistrator) AssignFormation(kymaName, compassID, formation, tenant, directorName string) error {
+	directorClient, err := r.directorClient(kymaName, directorName)
+	if err != nil {
+		return err
+	}
+
+	return util.RetryOnError(retryTime*time.Second, attempts, "Error while assigning runtime to formation in Director: %s", func() (err apperrors.AppError) {
+		err = directorClient.AssignFormation(compassID, formation, tenant)
+		return
+	})
+}
+
+func (r *CompassRegistrator) UnassignFormation(kymaName, compassID, formation, tenant, directorName string) error {
+	directorClient, err := r.directorClient(kymaName, directorName)
+	if err != nil {
+		return err
+	}
+
+	return util.RetryOnError(retryTime*time.Second, attempts, "Error while unassigning runtime from formation in Director: %s", func() (err apperrors.AppError) {
+		err = directorClient.UnassignFormation(compassID, formation, tenant)
+		return
+	})
+}
+
+func (r *CompassRegistrator) RefreshCompassToken(kymaName, compassID, globalAccount, directorName string) (graphql.OneTimeTokenForRuntimeExt, error) {
+	directorClient, err := r.directorClient(kymaName, directorName)
+	if err != nil {
+		return graphql.OneTimeTokenForRuntimeExt{}, err
+	}
+
 	var token graphql.OneTimeTokenForRuntimeExt
-	err := util.RetryOnError(retryTime*time.Second, attempts, "Error while refreshing OneTime token in Director: %s", func() (err apperrors.AppError) {
-		token, err = r.Client.GetConnectionToken(compassID, globalAccount)
+	err = util.RetryOnError(retryTime*time.Second, attempts, "Error while refreshing OneTime token in Director: %s", func() (err apperrors.AppError) {
+		token, err = directorClient.GetConnectionToken(compassID, globalAccount)
 		return
 	})
 
@@ -75,9 +148,17 @@ func (r *CompassRegistrator) RefreshCompassToken(compassID, globalAccount string
 	return token, nil
 }
 
+func (r *CompassRegistrator) directorClient(kymaName, directorName string) (director.Client, error) {
+	endpoint, err := r.Directors.Get(directorName)
+	if err != nil {
+		return nil, err
+	}
+	return director.WithKymaName(endpoint.Client, kymaName), nil
+}
+
 func createRuntimeInput(compassRuntimeLabels map[string]interface{}) (*gqlschema.RuntimeInput, error) {
 	runtimeInput := &gqlschema.RuntimeInput{}
-	runtimeInput.Name = compassRuntimeLabels["gardenerClusterName"].(string) + "-" + generateRandomText(nameIDLen)
+	runtimeInput.Name = compassRuntimeLabels[RuntimeLabelShootName].(string) + "-" + generateRandomText(nameIDLen)
 
 	err := runtimeInput.Labels.UnmarshalGQL(compassRuntimeLabels)
 	if err != nil {
@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/kyma-project/compass-manager/internal/director"
+	"github.com/kyma-project/compass-manager/internal/director/fake"
+	"github.com/kyma-project/compass-manager/internal/graphql"
+	"github.com/kyma-project/compass-manager/internal/oauth"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompassRegistrator(t *testing.T) {
+	t.Run("should register runtime, fetch Compass Token and deregister runtime in the Director", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+
+		oauthClient := oauth.NewOauthClient(http.DefaultClient, fake.DefaultClientID, fake.DefaultClientSecret, server.TokensEndpoint())
+		directorClient := director.NewDirectorClient(graphql.NewGraphQLClient(server.DirectorURL(), false, false), oauthClient)
+		directors := director.NewSingleDirectorRegistry(directorClient, fake.ConnectorPath)
+
+		registrator := NewCompassRegistrator(directors, logrus.New())
+		configurator := NewRuntimeAgentConfigurator(directors, logrus.New(), nil)
+
+		// when
+		compassID, err := registrator.RegisterInCompass("kyma", map[string]interface{}{
+			"global_account_id":   "globalAccount",
+			"gardenerClusterName": "shoot",
+		}, "globalAccount", "")
+		require.NoError(t, err)
+
+		token, tokenErr := configurator.fetchCompassToken("kyma", compassID, "globalAccount", "")
+		deregisterErr := registrator.DeregisterFromCompass("kyma", compassID, "globalAccount", "")
+
+		// then
+		require.NoError(t, tokenErr)
+		assert.Equal(t, []string{token.Token}, server.Director.OneTimeTokens(compassID))
+
+		require.NoError(t, deregisterErr)
+		assert.Empty(t, server.Director.Runtimes("globalAccount"))
+	})
+
+	t.Run("should register runtime under subaccount and manage its contexts in the Director", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+
+		oauthClient := oauth.NewOauthClient(http.DefaultClient, fake.DefaultClientID, fake.DefaultClientSecret, server.TokensEndpoint())
+		directorClient := director.NewDirectorClient(graphql.NewGraphQLClient(server.DirectorURL(), false, false), oauthClient)
+		registrator := NewCompassRegistrator(director.NewSingleDirectorRegistry(directorClient, fake.ConnectorPath), logrus.New())
+
+		// when
+		compassID, err := registrator.RegisterInCompass("kyma", map[string]interface{}{
+			"global_account_id":   "globalAccount",
+			"gardenerClusterName": "shoot",
+		}, "subaccount", "")
+		require.NoError(t, err)
+
+		contextID, createErr := registrator.CreateRuntimeContext("kyma", compassID, "other-subaccount", "subaccount", "")
+		contexts := server.Director.RuntimeContexts("subaccount")
+		deleteErr := registrator.DeleteRuntimeContext("kyma", contextID, "subaccount", "")
+
+		// then
+		assert.Empty(t, server.Director.Runtimes("globalAccount"))
+		assert.Len(t, server.Director.Runtimes("subaccount"), 1)
+
+		require.NoError(t, createErr)
+		require.Len(t, contexts, 1)
+		assert.Equal(t, "other-subaccount", contexts[0].Value)
+
+		require.NoError(t, deleteErr)
+		assert.Empty(t, server.Director.RuntimeContexts("subaccount"))
+	})
+
+	t.Run("should assign runtime to formation and unassign it in the Director", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+
+		oauthClient := oauth.NewOauthClient(http.DefaultClient, fake.DefaultClientID, fake.DefaultClientSecret, server.TokensEndpoint())
+		directorClient := director.NewDirectorClient(graphql.NewGraphQLClient(server.DirectorURL(), false, false), oauthClient)
+		registrator := NewCompassRegistrator(director.NewSingleDirectorRegistry(directorClient, fake.ConnectorPath), logrus.New())
+
+		server.Director.AddFormation("globalAccount", "formation")
+		compassID, err := registrator.RegisterInCompass("kyma", map[string]interface{}{
+			"global_account_id":   "globalAccount",
+			"gardenerClusterName": "shoot",
+		}, "globalAccount", "")
+		require.NoError(t, err)
+
+		// when
+		assignErr := registrator.AssignFormation("kyma", compassID, "formation", "globalAccount", "")
+		assigned, _ := server.Director.Formation("globalAccount", "formation")
+		unassignErr := registrator.UnassignFormation("kyma", compassID, "formation", "globalAccount", "")
+
+		// then
+		require.NoError(t, assignErr)
+		assert.Equal(t, []string{compassID}, assigned.RuntimeIDs)
+
+		require.NoError(t, unassignErr)
+		unassigned, _ := server.Director.Formation("globalAccount", "formation")
+		assert.Empty(t, unassigned.RuntimeIDs)
+	})
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	s "github.com/kyma-project/compass-manager/controllers/status"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// registrationTenant returns the tenant the Runtime of a new Kyma is registered in
+func (cm *CompassManagerReconciler) registrationTenant(kymaLabels map[string]string, globalAccount string) string {
+	if cm.registerInSubaccount && kymaLabels[LabelSubaccountID] != "" {
+		return kymaLabels[LabelSubaccountID]
+	}
+	return globalAccount
+}
+
+// MappingTenant returns the tenant the Runtime of the mapping is registered in
+func MappingTenant(mapping v1beta1.CompassManagerMapping) string {
+	if tenant := mapping.Labels[LabelCompassTenant]; tenant != "" {
+		return tenant
+	}
+	return mapping.Labels[LabelGlobalAccountID]
+}
+
+// updateRuntimeContexts creates contexts of the Runtime for subaccounts listed on the Kyma and deletes contexts of subaccounts removed from it.
+// Contexts changed before a failure are stored in the mapping status too, so that they're not created twice.
+func (cm *CompassManagerReconciler) updateRuntimeContexts(kymaName types.NamespacedName, kymaAnnotations map[string]string, mapping v1beta1.CompassManagerMapping, compassRuntimeID, tenant, director string) error {
+	subaccounts := additionalSubaccounts(kymaAnnotations[AnnotationCompassSubaccounts], tenant)
+	runtimeContexts, err := cm.changeRuntimeContexts(kymaName, mapping.Status.RuntimeContexts, subaccounts, compassRuntimeID, tenant, director)
+
+	if !slices.Equal(runtimeContexts, mapping.Status.RuntimeContexts) {
+		if statErr := cm.cluster.SetCompassMappingRuntimeContexts(kymaName, runtimeContexts); statErr != nil {
+			return errors.Wrap(statErr, "failed to store Runtime contexts in Compass Manager Mapping status")
+		}
+	}
+	return err
+}
+
+func (cm *CompassManagerReconciler) changeRuntimeContexts(kymaName types.NamespacedName, existing []v1beta1.RuntimeContext, subaccounts []string, compassRuntimeID, tenant, director string) ([]v1beta1.RuntimeContext, error) {
+	var runtimeContexts []v1beta1.RuntimeContext
+	for i, runtimeContext := range existing {
+		if slices.Contains(subaccounts, runtimeContext.Subaccount) {
+			runtimeContexts = append(runtimeContexts, runtimeContext)
+			continue
+		}
+
+		cm.Log.Infof("Deleting context %s of Runtime %s for subaccount %s", runtimeContext.ID, compassRuntimeID, runtimeContext.Subaccount)
+		if err := cm.Registrator.DeleteRuntimeContext(kymaName.Name, runtimeContext.ID, tenant, director); err != nil {
+			return append(runtimeContexts, existing[i:]...), errors.Wrapf(err, "failed to delete context of Runtime for subaccount %s", runtimeContext.Subaccount)
+		}
+	}
+
+	for _, subaccount := range subaccounts {
+		if slices.ContainsFunc(runtimeContexts, func(runtimeContext v1beta1.RuntimeContext) bool { return runtimeContext.Subaccount == subaccount }) {
+			continue
+		}
+
+		cm.Log.Infof("Creating context of Runtime %s for subaccount %s", compassRuntimeID, subaccount)
+		id, err := cm.Registrator.CreateRuntimeContext(kymaName.Name, compassRuntimeID, subaccount, tenant, director)
+		if err != nil {
+			return runtimeContexts, errors.Wrapf(err, "failed to create context of Runtime for subaccount %s", subaccount)
+		}
+		runtimeContexts = append(runtimeContexts, v1beta1.RuntimeContext{ID: id, Subaccount: subaccount})
+	}
+
+	return runtimeContexts, nil
+}
+
+func (cm *CompassManagerReconciler) failRuntimeContextsAndRequeue(kymaName types.NamespacedName, err error, director string) (ctrl.Result, error) {
+	cm.Log.Errorf("Failed attempt to update Runtime contexts for Kyma resource %s: %v", kymaName.Name, err)
+	cm.recordFailure(kymaName, ReasonRuntimeContextsFailed, ActionUpdateRuntimeContexts, err)
+
+	statErr := cm.cluster.SetCompassMappingStatus(kymaName, s.Registered|s.Failed)
+	if statErr != nil {
+		return ctrl.Result{Requeue: true}, errors.Wrap(statErr, "failed to set Compass Manager Status after failed attempt to update Runtime contexts")
+	}
+
+	if isDirectorCircuitOpen(err) {
+		return ctrl.Result{RequeueAfter: cm.requeueTimeForDirectorError(err, director)}, nil
+	}
+
+	return ctrl.Result{Requeue: true}, errors.Wrapf(err, "failed attempt to update Runtime contexts for Kyma resource %s", kymaName.Name)
+}
+
+// additionalSubaccounts parses the subaccounts listed on the Kyma, leaving out the tenant the Runtime is registered in
+func additionalSubaccounts(annotation, tenant string) []string {
+	var subaccounts []string
+	for _, subaccount := range strings.Split(annotation, ",") {
+		subaccount = strings.TrimSpace(subaccount)
+		if subaccount == "" || subaccount == tenant || slices.Contains(subaccounts, subaccount) {
+			continue
+		}
+		subaccounts = append(subaccounts, subaccount)
+	}
+	return subaccounts
+}
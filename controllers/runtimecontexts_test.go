@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/controllers/mocks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestUpdateRuntimeContexts(t *testing.T) {
+	kymaName := types.NamespacedName{Name: "kyma", Namespace: "kcp-system"}
+
+	t.Run("should create contexts for added subaccounts and delete contexts of removed ones", func(t *testing.T) {
+		// given
+		mapping := newRuntimeContextsMapping(kymaName, v1beta1.RuntimeContext{ID: "context-removed", Subaccount: "removed"}, v1beta1.RuntimeContext{ID: "context-kept", Subaccount: "kept"})
+		registrator := mocks.NewRegistrator(t)
+		registrator.On("DeleteRuntimeContext", "kyma", "context-removed", "subaccount", "").Return(nil)
+		registrator.On("CreateRuntimeContext", "kyma", "runtime-id", "added", "subaccount", "").Return("context-added", nil)
+		reconciler := newRuntimeContextsReconciler(t, registrator, mapping)
+
+		// when
+		err := reconciler.updateRuntimeContexts(kymaName, map[string]string{AnnotationCompassSubaccounts: "kept, added,subaccount,added"}, *mapping, "runtime-id", "subaccount", "")
+
+		// then
+		require.NoError(t, err)
+		stored, err := reconciler.cluster.GetCompassMapping(kymaName)
+		require.NoError(t, err)
+		assert.Equal(t, []v1beta1.RuntimeContext{
+			{ID: "context-kept", Subaccount: "kept"},
+			{ID: "context-added", Subaccount: "added"},
+		}, stored.Status.RuntimeContexts)
+	})
+
+	t.Run("should store contexts created before a failure", func(t *testing.T) {
+		// given
+		mapping := newRuntimeContextsMapping(kymaName)
+		registrator := mocks.NewRegistrator(t)
+		registrator.On("CreateRuntimeContext", "kyma", "runtime-id", "first", "globalAccount", "").Return("context-first", nil)
+		registrator.On("CreateRuntimeContext", "kyma", "runtime-id", "second", "globalAccount", "").Return("", errors.New("director unavailable"))
+		reconciler := newRuntimeContextsReconciler(t, registrator, mapping)
+
+		// when
+		err := reconciler.updateRuntimeContexts(kymaName, map[string]string{AnnotationCompassSubaccounts: "first,second"}, *mapping, "runtime-id", "globalAccount", "")
+
+		// then
+		require.ErrorContains(t, err, "director unavailable")
+		stored, err := reconciler.cluster.GetCompassMapping(kymaName)
+		require.NoError(t, err)
+		assert.Equal(t, []v1beta1.RuntimeContext{{ID: "context-first", Subaccount: "first"}}, stored.Status.RuntimeContexts)
+	})
+
+	t.Run("should not call Director when contexts are up to date", func(t *testing.T) {
+		// given
+		mapping := newRuntimeContextsMapping(kymaName, v1beta1.RuntimeContext{ID: "context", Subaccount: "subaccount"})
+		reconciler := newRuntimeContextsReconciler(t, mocks.NewRegistrator(t), mapping)
+
+		// when
+		err := reconciler.updateRuntimeContexts(kymaName, map[string]string{AnnotationCompassSubaccounts: "subaccount"}, *mapping, "runtime-id", "globalAccount", "")
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func TestRegistrationTenant(t *testing.T) {
+	kymaLabels := map[string]string{LabelSubaccountID: "subaccount"}
+
+	assert.Equal(t, "globalAccount", (&CompassManagerReconciler{}).registrationTenant(kymaLabels, "globalAccount"))
+	assert.Equal(t, "subaccount", (&CompassManagerReconciler{registerInSubaccount: true}).registrationTenant(kymaLabels, "globalAccount"))
+	assert.Equal(t, "globalAccount", (&CompassManagerReconciler{registerInSubaccount: true}).registrationTenant(map[string]string{}, "globalAccount"))
+
+	mapping := v1beta1.CompassManagerMapping{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{LabelGlobalAccountID: "globalAccount"}}}
+	assert.Equal(t, "globalAccount", MappingTenant(mapping))
+	mapping.Labels[LabelCompassTenant] = "subaccount"
+	assert.Equal(t, "subaccount", MappingTenant(mapping))
+}
+
+func newRuntimeContextsMapping(kymaName types.NamespacedName, runtimeContexts ...v1beta1.RuntimeContext) *v1beta1.CompassManagerMapping {
+	return &v1beta1.CompassManagerMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kymaName.Name,
+			Namespace: kymaName.Namespace,
+			Labels:    map[string]string{LabelKymaName: kymaName.Name},
+		},
+		Status: v1beta1.CompassManagerMappingStatus{RuntimeContexts: runtimeContexts},
+	}
+}
+
+func newRuntimeContextsReconciler(t *testing.T, registrator Registrator, mapping *v1beta1.CompassManagerMapping) *CompassManagerReconciler {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	kubectl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mapping).WithStatusSubresource(mapping).Build()
+
+	log := logrus.New()
+	return &CompassManagerReconciler{
+		Log:         log,
+		Registrator: registrator,
+		cluster:     NewControlPlaneInterface(kubectl, log, false),
+	}
+}
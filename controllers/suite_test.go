@@ -2,14 +2,17 @@ package controllers
 
 import (
 	"context"
-	"errors"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/kyma-project/compass-manager/api/v1beta1"
 	"github.com/kyma-project/compass-manager/controllers/metrics"
-	"github.com/kyma-project/compass-manager/controllers/mocks"
+	"github.com/kyma-project/compass-manager/internal/connector"
+	"github.com/kyma-project/compass-manager/internal/director"
+	"github.com/kyma-project/compass-manager/internal/director/fake"
+	"github.com/kyma-project/compass-manager/internal/graphql"
+	"github.com/kyma-project/compass-manager/internal/oauth"
 	kyma "github.com/kyma-project/lifecycle-manager/api/v1beta2"
 	. "github.com/onsi/ginkgo/v2" //nolint:revive
 	. "github.com/onsi/gomega"    //nolint:revive
@@ -27,14 +30,14 @@ import (
 // http://onsi.github.io/ginkgo/ to learn more about Ginkgo.
 
 var (
-	cfg              *rest.Config              //nolint:gochecknoglobals
-	k8sClient        client.Client             //nolint:gochecknoglobals
-	testEnv          *envtest.Environment      //nolint:gochecknoglobals
-	cm               *CompassManagerReconciler //nolint:gochecknoglobals
-	mockConfigurator *mocks.Configurator       //nolint:gochecknoglobals
-	mockRegistrator  *mocks.Registrator        //nolint:gochecknoglobals
-	suiteCtx         context.Context           //nolint:gochecknoglobals
-	cancelSuiteCtx   context.CancelFunc        //nolint:gochecknoglobals
+	cfg               *rest.Config              //nolint:gochecknoglobals
+	k8sClient         client.Client             //nolint:gochecknoglobals
+	testEnv           *envtest.Environment      //nolint:gochecknoglobals
+	cm                *CompassManagerReconciler //nolint:gochecknoglobals
+	directorServer    *fake.Server              //nolint:gochecknoglobals
+	runtimeKubeconfig []byte                    //nolint:gochecknoglobals
+	suiteCtx          context.Context           //nolint:gochecknoglobals
+	cancelSuiteCtx    context.CancelFunc        //nolint:gochecknoglobals
 )
 
 func TestAPIs(t *testing.T) {
@@ -71,9 +74,21 @@ var _ = BeforeSuite(func() {
 	log := logrus.New()
 	log.SetLevel(logrus.InfoLevel)
 
-	mockConfigurator = &mocks.Configurator{}
-	mockRegistrator = &mocks.Registrator{}
-	prepareMockFunctions(mockConfigurator, mockRegistrator)
+	By("starting the fake Director, tokens endpoint and Connector")
+	directorServer = fake.NewServer(fake.Config{TLS: true})
+	httpClient := directorServer.Client()
+	httpClient.Timeout = 5 * time.Second
+	directorClient := director.NewDirectorClient(
+		graphql.NewGraphQLClient(directorServer.DirectorURL(), true, true),
+		oauth.NewOauthClient(httpClient, fake.DefaultClientID, fake.DefaultClientSecret, directorServer.TokensEndpoint()),
+	)
+	directors := director.NewSingleDirectorRegistry(directorClient, fake.ConnectorPath)
+
+	// Compass Runtime Agent of every Kyma is configured in the envtest cluster, in the namespace it runs in on runtimes
+	runtimeUser, err := testEnv.AddUser(envtest.User{Name: "runtime-admin", Groups: []string{"system:masters"}}, nil)
+	Expect(err).NotTo(HaveOccurred())
+	runtimeKubeconfig, err = runtimeUser.KubeConfig()
+	Expect(err).NotTo(HaveOccurred())
 
 	requeueTime := time.Second * 5
 	requeueTimeForKubeconfig := time.Second * 5
@@ -82,19 +97,23 @@ var _ = BeforeSuite(func() {
 	cm = NewCompassManagerReconciler(
 		k8sManager,
 		log,
-		mockConfigurator,
-		mockRegistrator,
+		NewRuntimeAgentConfigurator(directors, log, connector.NewConnectorClient(httpClient)),
+		NewCompassRegistrator(directors, log),
 		requeueTime,
 		requeueTimeForKubeconfig,
 		true,
 		false,
+		nil,
+		false,
 		metrics,
+		directors,
 	)
 	k8sClient = k8sManager.GetClient()
 	err = cm.SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
 	Expect(createNamespace(kymaCustomResourceNamespace)).To(Succeed())
+	Expect(createNamespace(runtimeAgentComponentNameSpace)).To(Succeed())
 
 	go func() {
 		defer GinkgoRecover()
@@ -109,6 +128,7 @@ var _ = BeforeSuite(func() {
 
 var _ = AfterSuite(func() {
 	cancelSuiteCtx()
+	directorServer.Close()
 
 	By("tearing down the test environment")
 	err := (func() (err error) {
@@ -126,48 +146,3 @@ var _ = AfterSuite(func() {
 	})()
 	Expect(err).NotTo(HaveOccurred())
 })
-
-func prepareMockFunctions(c *mocks.Configurator, r *mocks.Registrator) {
-	// It handles `compass-runtime-id-for-migration`
-	compassLabelsRegistered := createCompassRuntimeLabels(map[string]string{LabelShootName: "preregistered", LabelGlobalAccountID: "globalAccount"})
-	r.On("RegisterInCompass", compassLabelsRegistered).Return("id-preregistered-incorrect", nil)
-	// succeeding test case
-	c.On("ConfigureCompassRuntimeAgent", []byte("kubeconfig-data-preregistered"), "preregistered-id", "globalAccount").Return(nil)
-	// failing test case
-	c.On("ConfigureCompassRuntimeAgent", []byte("kubeconfig-data-preregistered"), "preregistered-id", "globalAccount").Return(errors.New("this shouldn't be called"))
-
-	compassLabelsAllGood := createCompassRuntimeLabels(map[string]string{LabelShootName: "all-good", LabelGlobalAccountID: "globalAccount"})
-	r.On("RegisterInCompass", compassLabelsAllGood).Return("id-all-good", nil)
-	c.On("ConfigureCompassRuntimeAgent", []byte("kubeconfig-data-all-good"), "id-all-good", "globalAccount").Return(nil)
-
-	compassLabelsConfigureFails := createCompassRuntimeLabels(map[string]string{LabelShootName: "configure-fails", LabelGlobalAccountID: "globalAccount"})
-	// The first call to ConfigureRuntimeAgent fails, but the second is successful
-	r.On("RegisterInCompass", compassLabelsConfigureFails).Return("id-configure-fails", nil)
-	c.On("ConfigureCompassRuntimeAgent", []byte("kubeconfig-data-configure-fails"), "id-configure-fails", "globalAccount").Return(errors.New("error during configuration of Compass Runtime Agent CR")).Once()
-	c.On("ConfigureCompassRuntimeAgent", []byte("kubeconfig-data-configure-fails"), "id-configure-fails", "globalAccount").Return(nil).Once()
-
-	compassLabelsRegistrationFails := createCompassRuntimeLabels(map[string]string{LabelShootName: "registration-fails", LabelGlobalAccountID: "globalAccount"})
-	// The first call to RegisterInCompass fails, but the second is successful.
-	r.On("RegisterInCompass", compassLabelsRegistrationFails).Return("", errors.New("error during registration")).Once()
-	r.On("RegisterInCompass", compassLabelsRegistrationFails).Return("registration-fails", nil).Once()
-	c.On("ConfigureCompassRuntimeAgent", []byte("kubeconfig-data-registration-fails"), "registration-fails", "globalAccount").Return(nil)
-
-	compassLabelsEmptyKubeconfig := createCompassRuntimeLabels(map[string]string{LabelShootName: "empty-kubeconfig", LabelGlobalAccountID: "globalAccount"})
-	r.On("RegisterInCompass", compassLabelsEmptyKubeconfig).Return("id-empty-kubeconfig", nil)
-	c.On("ConfigureCompassRuntimeAgent", []byte("kubeconfig-data-empty-kubeconfig"), "id-empty-kubeconfig", "globalAccount").Return(nil)
-
-	compassLabelsDeregistration := createCompassRuntimeLabels(map[string]string{LabelShootName: "unregister-runtime", LabelGlobalAccountID: "globalAccount"})
-	r.On("RegisterInCompass", compassLabelsDeregistration).Return("id-unregister-runtime", nil)
-	c.On("ConfigureCompassRuntimeAgent", []byte("kubeconfig-data-unregister-runtime"), "id-unregister-runtime", "globalAccount").Return(nil)
-	r.On("DeregisterFromCompass", "id-unregister-runtime", "globalAccount").Return(nil)
-
-	compassLabelsDeregistrationFails := createCompassRuntimeLabels(map[string]string{LabelShootName: "unregister-runtime-fails", LabelGlobalAccountID: "globalAccount"})
-	r.On("RegisterInCompass", compassLabelsDeregistrationFails).Return("id-unregister-runtime-fails", nil)
-	c.On("ConfigureCompassRuntimeAgent", []byte("kubeconfig-data-unregister-runtime-fails"), "id-unregister-runtime-fails", "globalAccount").Return(nil)
-	r.On("DeregisterFromCompass", "id-unregister-runtime-fails", "globalAccount").Return(errors.New("error during unregistration of the runtime")).Once()
-	r.On("DeregisterFromCompass", "id-unregister-runtime-fails", "globalAccount").Return(nil).Once()
-
-	compassLabelsRefreshToken := createCompassRuntimeLabels(map[string]string{LabelShootName: "refresh-token", LabelGlobalAccountID: "globalAccount"})
-	r.On("RegisterInCompass", compassLabelsRefreshToken).Return("id-refresh-token", nil).Once()
-	c.On("ConfigureCompassRuntimeAgent", []byte("kubeconfig-data-refresh-token"), "id-refresh-token", "globalAccount").Return(nil).Twice()
-}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vektah/gqlparser/v2/ast"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+const (
+	// MappingsGroup and MappingsResource are the resource callers of the API are authorized for
+	MappingsGroup    = "operator.kyma-project.io"
+	MappingsResource = "compassmanagermappings"
+
+	// VerbRead is checked for queries, VerbWrite for mutations, and VerbWatch for subscriptions
+	VerbRead  = "list"
+	VerbWrite = "update"
+	VerbWatch = "watch"
+)
+
+// User is the caller of the API authenticated by its bearer token
+type User struct {
+	Name   string
+	UID    string
+	Groups []string
+}
+
+//go:generate mockery --name=Auth
+type Auth interface {
+	// Authenticate returns the user of the bearer token, false when the token isn't valid
+	Authenticate(ctx context.Context, token string) (User, bool, error)
+	// Authorize tells if the user is allowed the verb on Compass Manager Mappings, with the reason of the decision
+	Authorize(ctx context.Context, user User, verb string) (bool, string, error)
+}
+
+// KubernetesAuth authenticates callers with TokenReviews, and authorizes them with SubjectAccessReviews against Compass Manager Mappings in the namespace
+type KubernetesAuth struct {
+	client    client.Client
+	namespace string
+}
+
+func NewKubernetesAuth(c client.Client, namespace string) *KubernetesAuth {
+	return &KubernetesAuth{
+		client:    c,
+		namespace: namespace,
+	}
+}
+
+func (a *KubernetesAuth) Authenticate(ctx context.Context, token string) (User, bool, error) {
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}
+	if err := a.client.Create(ctx, review); err != nil {
+		return User{}, false, errors.Wrap(err, "failed to review token")
+	}
+
+	if !review.Status.Authenticated {
+		return User{}, false, nil
+	}
+	return User{
+		Name:   review.Status.User.Username,
+		UID:    review.Status.User.UID,
+		Groups: review.Status.User.Groups,
+	}, true, nil
+}
+
+func (a *KubernetesAuth) Authorize(ctx context.Context, user User, verb string) (bool, string, error) {
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Name,
+			UID:    user.UID,
+			Groups: user.Groups,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: a.namespace,
+				Verb:      verb,
+				Group:     MappingsGroup,
+				Resource:  MappingsResource,
+			},
+		},
+	}
+	if err := a.client.Create(ctx, review); err != nil {
+		return false, "", errors.Wrap(err, "failed to review access")
+	}
+
+	return review.Status.Allowed, review.Status.Reason, nil
+}
+
+// verb returns the verb the caller must be allowed to perform the operation
+func verb(operation ast.Operation) string {
+	switch operation {
+	case ast.Mutation:
+		return VerbWrite
+	case ast.Subscription:
+		return VerbWatch
+	default:
+		return VerbRead
+	}
+}
+
+// bearerToken returns the token of the Authorization header value
+func bearerToken(header string) (string, bool) {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+type userKey struct{}
+
+func withUser(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFrom returns the authenticated caller of the request
+func UserFrom(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(userKey{}).(User)
+	return user, ok
+}
@@ -0,0 +1,160 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/kyma-project/compass-manager/internal/api"
+	"github.com/kyma-project/compass-manager/internal/api/mocks"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestServer_Auth(t *testing.T) {
+	kymaName := types.NamespacedName{Name: "kyma", Namespace: namespace}
+
+	t.Run("should reject request without bearer token", func(t *testing.T) {
+		// given
+		server := newAuthTestServer(newFakeClient(t), nil, nil, mocks.NewAuth(t), logrus.New())
+		defer server.Close()
+
+		// when
+		resp, status := postWithToken(t, server, `query { mappings { kymaName } }`, "")
+
+		// then
+		assert.Equal(t, http.StatusUnauthorized, status)
+		require.Len(t, resp.Errors, 1)
+	})
+
+	t.Run("should reject request with invalid bearer token", func(t *testing.T) {
+		// given
+		auth := mocks.NewAuth(t)
+		auth.On("Authenticate", mock.Anything, "invalid").Return(api.User{}, false, nil)
+
+		server := newAuthTestServer(newFakeClient(t), nil, nil, auth, logrus.New())
+		defer server.Close()
+
+		// when
+		_, status := postWithToken(t, server, `query { mappings { kymaName } }`, "invalid")
+
+		// then
+		assert.Equal(t, http.StatusUnauthorized, status)
+	})
+
+	t.Run("should forbid mutation to caller allowed only to read", func(t *testing.T) {
+		// given
+		auth := mocks.NewAuth(t)
+		auth.On("Authenticate", mock.Anything, token).Return(caller, true, nil)
+		auth.On("Authorize", mock.Anything, caller, api.VerbWrite).Return(false, "no RBAC policy matched", nil)
+
+		server := newAuthTestServer(newFakeClient(t), mocks.NewOperations(t), nil, auth, logrus.New())
+		defer server.Close()
+
+		// when
+		resp, status := postWithToken(t, server, `mutation { deregister(kymaName: "kyma") { kymaName } }`, token)
+
+		// then
+		assert.Equal(t, http.StatusForbidden, status)
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, api.VerbWrite)
+	})
+
+	t.Run("should audit mutation with its caller", func(t *testing.T) {
+		// given
+		log, hook := test.NewNullLogger()
+		operations := mocks.NewOperations(t)
+		operations.On("Reconfigure", kymaName).Return(nil)
+		auth := mocks.NewAuth(t)
+		auth.On("Authenticate", mock.Anything, token).Return(caller, true, nil)
+		auth.On("Authorize", mock.Anything, caller, api.VerbWrite).Return(true, "", nil)
+
+		server := newAuthTestServer(newFakeClient(t, newMapping("kyma", "globalAccount", "runtime-id")), operations, nil, auth, log)
+		defer server.Close()
+
+		// when
+		resp, status := postWithToken(t, server, `mutation { reconfigure(kymaName: "kyma") { kymaName } }`, token)
+
+		// then
+		assert.Equal(t, http.StatusOK, status)
+		require.Empty(t, resp.Errors)
+		require.NotNil(t, hook.LastEntry())
+		assert.Equal(t, logrus.InfoLevel, hook.LastEntry().Level)
+		assert.Equal(t, true, hook.LastEntry().Data["audit"])
+		assert.Equal(t, caller.Name, hook.LastEntry().Data["user"])
+		assert.Equal(t, caller.Groups, hook.LastEntry().Data["groups"])
+		assert.Equal(t, "reconfigure", hook.LastEntry().Data["mutation"])
+		assert.Equal(t, "kyma", hook.LastEntry().Data["kymaName"])
+	})
+}
+
+func TestKubernetesAuth(t *testing.T) {
+	t.Run("should return user of authenticated token", func(t *testing.T) {
+		// given
+		auth := api.NewKubernetesAuth(newReviewClient(t, func(obj client.Object) {
+			review := obj.(*authenticationv1.TokenReview)
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: review.Spec.Token == token,
+				User:          authenticationv1.UserInfo{Username: caller.Name, UID: caller.UID, Groups: caller.Groups},
+			}
+		}), namespace)
+
+		// when
+		user, authenticated, err := auth.Authenticate(context.Background(), token)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, authenticated)
+		assert.Equal(t, caller, user)
+	})
+
+	t.Run("should review access of the user to Compass Manager Mappings in the namespace", func(t *testing.T) {
+		// given
+		var spec authorizationv1.SubjectAccessReviewSpec
+		auth := api.NewKubernetesAuth(newReviewClient(t, func(obj client.Object) {
+			review := obj.(*authorizationv1.SubjectAccessReview)
+			spec = review.Spec
+			review.Status = authorizationv1.SubjectAccessReviewStatus{Allowed: false, Reason: "denied"}
+		}), namespace)
+
+		// when
+		allowed, reason, err := auth.Authorize(context.Background(), caller, api.VerbRead)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, allowed)
+		assert.Equal(t, "denied", reason)
+		assert.Equal(t, caller.Name, spec.User)
+		assert.Equal(t, caller.Groups, spec.Groups)
+		assert.Equal(t, &authorizationv1.ResourceAttributes{
+			Namespace: namespace,
+			Verb:      api.VerbRead,
+			Group:     api.MappingsGroup,
+			Resource:  api.MappingsResource,
+		}, spec.ResourceAttributes)
+	})
+}
+
+// newReviewClient creates a client which answers created reviews with the status set by review, as the API server would
+func newReviewClient(t *testing.T, review func(obj client.Object)) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, authenticationv1.AddToScheme(scheme))
+	require.NoError(t, authorizationv1.AddToScheme(scheme))
+	return ctrlfake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			review(obj)
+			return nil
+		},
+	}).Build()
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	log "github.com/sirupsen/logrus"
+	toolscache "k8s.io/client-go/tools/cache"
+)
+
+// subscriberBuffer is the number of state changes queued for a subscriber, further changes are dropped until it catches up
+const subscriberBuffer = 32
+
+// MappingStateChange is a transition of a Compass Manager Mapping to another state
+type MappingStateChange struct {
+	PreviousState string
+	Mapping       v1beta1.CompassManagerMapping
+}
+
+// MappingEvents fans out state changes of Compass Manager Mappings to subscribers of the API.
+// It's an event handler of the informer of the controller, so mappings are observed without polling the API server.
+type MappingEvents struct {
+	mutex       sync.Mutex
+	subscribers map[chan MappingStateChange]func(v1beta1.CompassManagerMapping) bool
+	log         *log.Logger
+}
+
+var _ toolscache.ResourceEventHandler = &MappingEvents{}
+
+func NewMappingEvents(log *log.Logger) *MappingEvents {
+	return &MappingEvents{
+		subscribers: map[chan MappingStateChange]func(v1beta1.CompassManagerMapping) bool{},
+		log:         log,
+	}
+}
+
+// Subscribe returns state changes of mappings matching the filter, until the context is done and the channel is closed
+func (e *MappingEvents) Subscribe(ctx context.Context, filter func(v1beta1.CompassManagerMapping) bool) <-chan MappingStateChange {
+	changes := make(chan MappingStateChange, subscriberBuffer)
+
+	e.mutex.Lock()
+	e.subscribers[changes] = filter
+	e.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		e.mutex.Lock()
+		delete(e.subscribers, changes)
+		close(changes)
+		e.mutex.Unlock()
+	}()
+
+	return changes
+}
+
+// OnAdd publishes mappings created with a state, mappings of the initial list aren't changes
+func (e *MappingEvents) OnAdd(obj interface{}, isInInitialList bool) {
+	mapping, ok := obj.(*v1beta1.CompassManagerMapping)
+	if !ok || isInInitialList || mapping.Status.State == "" {
+		return
+	}
+	e.publish(MappingStateChange{Mapping: *mapping})
+}
+
+func (e *MappingEvents) OnUpdate(oldObj, newObj interface{}) {
+	oldMapping, ok := oldObj.(*v1beta1.CompassManagerMapping)
+	if !ok {
+		return
+	}
+	newMapping, ok := newObj.(*v1beta1.CompassManagerMapping)
+	if !ok || oldMapping.Status.State == newMapping.Status.State {
+		return
+	}
+	e.publish(MappingStateChange{PreviousState: oldMapping.Status.State, Mapping: *newMapping})
+}
+
+func (e *MappingEvents) OnDelete(interface{}) {}
+
+func (e *MappingEvents) publish(change MappingStateChange) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for changes, filter := range e.subscribers {
+		if !filter(change.Mapping) {
+			continue
+		}
+		select {
+		case changes <- change:
+		default:
+			e.log.Warnf("Dropped state change of Compass Manager Mapping %s for a slow API subscriber", change.Mapping.Name)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package api_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/internal/api"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMappingEvents(t *testing.T) {
+	t.Run("should publish state changes of mappings matching the filter", func(t *testing.T) {
+		// given
+		events := api.NewMappingEvents(logrus.New())
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		changes := events.Subscribe(ctx, func(mapping v1beta1.CompassManagerMapping) bool {
+			return mapping.Name == "kyma"
+		})
+
+		processing := newMapping("kyma", "globalAccount", "runtime-id")
+		processing.Status = v1beta1.CompassManagerMappingStatus{State: "Processing"}
+		ready := processing.DeepCopy()
+		ready.Status.State = "Ready"
+		other := newMapping("other", "globalAccount", "")
+		other.Status = v1beta1.CompassManagerMappingStatus{State: "Processing"}
+
+		// when
+		events.OnAdd(processing, true)
+		events.OnAdd(other, false)
+		events.OnUpdate(processing, processing)
+		events.OnUpdate(processing, ready)
+
+		// then
+		require.Len(t, changes, 1)
+		change := <-changes
+		assert.Equal(t, "Processing", change.PreviousState)
+		assert.Equal(t, "Ready", change.Mapping.Status.State)
+	})
+
+	t.Run("should close changes when the context is done", func(t *testing.T) {
+		// given
+		events := api.NewMappingEvents(logrus.New())
+		ctx, cancel := context.WithCancel(context.Background())
+		changes := events.Subscribe(ctx, func(v1beta1.CompassManagerMapping) bool { return true })
+
+		// when
+		cancel()
+
+		// then
+		_, open := <-changes
+		assert.False(t, open)
+	})
+}
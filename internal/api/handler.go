@@ -0,0 +1,199 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// maxRequestSize limits the size of request bodies, queries and variables of the API are small
+const maxRequestSize = 1 << 20
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// handler executes GraphQL requests sent with POST against the executable schema.
+// Callers are authenticated by their bearer token, and authorized for the verb of the operation before it's executed.
+// Subscriptions are served over websocket connections, see serveWebsocket.
+// The handler package of gqlgen isn't used, as it doesn't build with the version of gqlparser the schema is served with.
+type handler struct {
+	schema         graphql.ExecutableSchema
+	errorPresenter graphql.ErrorPresenterFunc
+	auth           Auth
+	log            *log.Logger
+
+	stopOnce sync.Once
+	stopped  chan struct{}
+}
+
+func newHandler(schema graphql.ExecutableSchema, errorPresenter graphql.ErrorPresenterFunc, auth Auth, log *log.Logger) *handler {
+	return &handler{
+		schema:         schema,
+		errorPresenter: errorPresenter,
+		auth:           auth,
+		log:            log,
+		stopped:        make(chan struct{}),
+	}
+}
+
+// shutdown closes websocket connections
+func (h *handler) shutdown() {
+	h.stopOnce.Do(func() {
+		close(h.stopped)
+	})
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		h.serveWebsocket(w, r)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "only POST is supported")
+		return
+	}
+
+	user, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request is larger than %d bytes", tooLarge.Limit))
+			return
+		}
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to decode request: %s", err.Error()))
+		return
+	}
+
+	operationContext, errs := h.operationContext(req)
+	if len(errs) != 0 {
+		writeResponse(w, http.StatusUnprocessableEntity, &graphql.Response{Errors: errs})
+		return
+	}
+	if operationContext.Operation.Operation == ast.Subscription {
+		writeError(w, http.StatusBadRequest, "subscriptions are only supported over websocket")
+		return
+	}
+
+	allowed, err := h.authorize(r.Context(), user, operationContext.Operation.Operation)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to authorize")
+		return
+	}
+	if !allowed {
+		writeError(w, http.StatusForbidden, forbidden(user, operationContext.Operation.Operation))
+		return
+	}
+
+	ctx := h.executionContext(r.Context(), user, operationContext)
+	response := h.schema.Exec(ctx)(ctx)
+	response.Errors = append(response.Errors, graphql.GetErrors(ctx)...)
+	writeResponse(w, http.StatusOK, response)
+}
+
+func (h *handler) authenticate(w http.ResponseWriter, r *http.Request) (User, bool) {
+	token, ok := bearerToken(r.Header.Get("Authorization"))
+	if !ok {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, "bearer token is required")
+		return User{}, false
+	}
+
+	user, authenticated, err := h.auth.Authenticate(r.Context(), token)
+	if err != nil {
+		h.log.Errorf("Failed to authenticate API caller: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to authenticate")
+		return User{}, false
+	}
+	if !authenticated {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, "bearer token is not valid")
+		return User{}, false
+	}
+	return user, true
+}
+
+// authorize tells if the user is allowed to perform the operation, failed and denied authorizations are logged
+func (h *handler) authorize(ctx context.Context, user User, operation ast.Operation) (bool, error) {
+	verb := verb(operation)
+	allowed, reason, err := h.auth.Authorize(ctx, user, verb)
+	if err != nil {
+		h.log.Errorf("Failed to authorize API caller %s: %v", user.Name, err)
+		return false, err
+	}
+	if !allowed {
+		h.log.Warnf("API caller %s is not allowed to %s %s: %s", user.Name, verb, MappingsResource, reason)
+	}
+	return allowed, nil
+}
+
+func forbidden(user User, operation ast.Operation) string {
+	return fmt.Sprintf("user %s is not allowed to %s %s", user.Name, verb(operation), MappingsResource)
+}
+
+// executionContext returns the context the operation of the user is executed in
+func (h *handler) executionContext(ctx context.Context, user User, operationContext *graphql.OperationContext) context.Context {
+	ctx = graphql.WithOperationContext(withUser(ctx, user), operationContext)
+	return graphql.WithResponseContext(ctx, h.errorPresenter, graphql.DefaultRecover)
+}
+
+func (h *handler) operationContext(req request) (*graphql.OperationContext, gqlerror.List) {
+	doc, errs := gqlparser.LoadQuery(h.schema.Schema(), req.Query)
+	if len(errs) != 0 {
+		return nil, errs
+	}
+
+	operation := doc.Operations.ForName(req.OperationName)
+	if operation == nil {
+		return nil, gqlerror.List{gqlerror.Errorf("operation %s not found", req.OperationName)}
+	}
+
+	variables, err := validator.VariableValues(h.schema.Schema(), operation, req.Variables)
+	if err != nil {
+		return nil, gqlerror.List{gqlerror.Errorf("%s", err.Error())}
+	}
+
+	return &graphql.OperationContext{
+		RawQuery:      req.Query,
+		Variables:     variables,
+		OperationName: req.OperationName,
+		Doc:           doc,
+		Operation:     operation,
+		Recover:       graphql.DefaultRecover,
+		ResolverMiddleware: func(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+			return next(ctx)
+		},
+	}, nil
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeResponse(w, status, &graphql.Response{Errors: gqlerror.List{gqlerror.Errorf("%s", message)}})
+}
+
+func writeResponse(w http.ResponseWriter, status int, response *graphql.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode response: %s", err), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,284 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/controllers"
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/kyma-project/compass-manager/internal/director"
+	"github.com/kyma-project/compass-manager/pkg/gqlschema"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Operations change the Runtime of a single Kyma, they're implemented by the CompassManagerReconciler
+//
+//go:generate mockery --name=Operations
+type Operations interface {
+	Reregister(kymaName types.NamespacedName) error
+	Reconfigure(kymaName types.NamespacedName) error
+	RotateToken(kymaName types.NamespacedName) error
+	Deregister(kymaName types.NamespacedName) error
+}
+
+// Resolver resolves queries of Compass Manager Mappings in the namespace, and of their Runtimes in Compass, mutations performing operations on them,
+// and subscriptions to their state changes
+type Resolver struct {
+	client     client.Reader
+	operations Operations
+	directors  *director.Registry
+	events     *MappingEvents
+	namespace  string
+	log        *log.Logger
+}
+
+// NewResolver creates a Resolver, directors are nil when Director isn't called, e.g. in dry run, so runtime details are unavailable.
+// The reader should read from the API server rather than a cache, so that mutations return mappings as updated by the operations.
+func NewResolver(reader client.Reader, operations Operations, directors *director.Registry, events *MappingEvents, namespace string, log *log.Logger) *Resolver {
+	return &Resolver{
+		client:     reader,
+		operations: operations,
+		directors:  directors,
+		events:     events,
+		namespace:  namespace,
+		log:        log,
+	}
+}
+
+func (r *Resolver) Query() gqlschema.QueryResolver {
+	return &queryResolver{r}
+}
+
+func (r *Resolver) Mutation() gqlschema.MutationResolver {
+	return &mutationResolver{r}
+}
+
+func (r *Resolver) Subscription() gqlschema.SubscriptionResolver {
+	return &subscriptionResolver{r}
+}
+
+type queryResolver struct {
+	*Resolver
+}
+
+func (r *queryResolver) Mappings(ctx context.Context, kymaName *string, globalAccount *string, state *gqlschema.MappingState) ([]*gqlschema.Mapping, error) {
+	selector := map[string]string{}
+	if kymaName != nil {
+		selector[controllers.LabelKymaName] = *kymaName
+	}
+	if globalAccount != nil {
+		selector[controllers.LabelGlobalAccountID] = *globalAccount
+	}
+
+	mappings := v1beta1.CompassManagerMappingList{}
+	err := r.client.List(ctx, &mappings, &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(selector),
+		Namespace:     r.namespace,
+	})
+	if err != nil {
+		return nil, apperrors.Internalf("failed to list Compass Manager Mappings: %s", err.Error())
+	}
+
+	result := make([]*gqlschema.Mapping, 0, len(mappings.Items))
+	for _, mapping := range mappings.Items {
+		if state != nil && mapping.Status.State != state.String() {
+			continue
+		}
+		result = append(result, toMapping(mapping))
+	}
+	return result, nil
+}
+
+func (r *queryResolver) Runtime(ctx context.Context, kymaName string) (*gqlschema.Runtime, error) {
+	mapping, err := r.getMapping(ctx, kymaName)
+	if err != nil {
+		return nil, err
+	}
+
+	compassRuntimeID := mapping.Labels[controllers.LabelCompassID]
+	if compassRuntimeID == "" {
+		return nil, nil
+	}
+
+	if r.directors == nil {
+		return nil, apperrors.Unavailable("details of Runtime %s are not available, as Director isn't called", compassRuntimeID)
+	}
+
+	endpoint, err := r.directors.Get(mapping.Labels[controllers.LabelCompassDirector])
+	if err != nil {
+		return nil, apperrors.Internal(err.Error())
+	}
+	tenant := controllers.MappingTenant(mapping)
+
+	results := director.ExecuteBatch(director.WithKymaName(endpoint.Client, kymaName), []director.BatchOperation{
+		director.GetRuntimeOperation(compassRuntimeID, tenant),
+		director.GetRuntimeFormationsOperation(compassRuntimeID, tenant),
+		director.GetRuntimeApplicationsOperation(compassRuntimeID, tenant),
+	})
+	for _, result := range results {
+		if result.Err != nil {
+			return nil, result.Err
+		}
+	}
+	runtime, formations, applications := results[0].Runtime, results[1].Formations, results[2].Applications
+
+	result := &gqlschema.Runtime{
+		ID:               runtime.ID,
+		Name:             runtime.Name,
+		Description:      runtime.Description,
+		Labels:           gqlschema.Labels(runtime.Labels),
+		Formations:       []string{},
+		ApplicationCount: applications.TotalCount,
+		Applications:     []string{},
+	}
+	for _, formation := range formations {
+		result.Formations = append(result.Formations, formation.Name)
+	}
+	for _, application := range applications.Data {
+		if application != nil {
+			result.Applications = append(result.Applications, application.Name)
+		}
+	}
+	return result, nil
+}
+
+type mutationResolver struct {
+	*Resolver
+}
+
+func (r *mutationResolver) Reregister(ctx context.Context, kymaName string) (*gqlschema.Mapping, error) {
+	return r.operate(ctx, "reregister", kymaName, r.operations.Reregister)
+}
+
+func (r *mutationResolver) Reconfigure(ctx context.Context, kymaName string) (*gqlschema.Mapping, error) {
+	return r.operate(ctx, "reconfigure", kymaName, r.operations.Reconfigure)
+}
+
+func (r *mutationResolver) RotateToken(ctx context.Context, kymaName string) (*gqlschema.Mapping, error) {
+	return r.operate(ctx, "rotateToken", kymaName, r.operations.RotateToken)
+}
+
+func (r *mutationResolver) Deregister(ctx context.Context, kymaName string) (*gqlschema.Mapping, error) {
+	return r.operate(ctx, "deregister", kymaName, r.operations.Deregister)
+}
+
+// operate performs the operation on the Kyma and returns its mapping as updated by the operation, the caller and the outcome are audited
+func (r *mutationResolver) operate(ctx context.Context, mutation, kymaName string, operation func(types.NamespacedName) error) (*gqlschema.Mapping, error) {
+	err := operation(types.NamespacedName{Name: kymaName, Namespace: r.namespace})
+	r.audit(ctx, mutation, kymaName, err)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := r.getMapping(ctx, kymaName)
+	if err != nil {
+		return nil, err
+	}
+	return toMapping(mapping), nil
+}
+
+func (r *mutationResolver) audit(ctx context.Context, mutation, kymaName string, err error) {
+	user, _ := UserFrom(ctx)
+	entry := r.log.WithFields(log.Fields{
+		"audit":    true,
+		"user":     user.Name,
+		"uid":      user.UID,
+		"groups":   user.Groups,
+		"mutation": mutation,
+		"kymaName": kymaName,
+	})
+	if err != nil {
+		entry.Warnf("API mutation %s of Kyma %s by %s failed: %v", mutation, kymaName, user.Name, err)
+		return
+	}
+	entry.Infof("API mutation %s of Kyma %s by %s succeeded", mutation, kymaName, user.Name)
+}
+
+type subscriptionResolver struct {
+	*Resolver
+}
+
+// MappingStateChanged streams state changes of mappings in the namespace, until the subscription is completed or the connection is closed
+func (r *subscriptionResolver) MappingStateChanged(ctx context.Context, kymaName *string, globalAccount *string) (<-chan *gqlschema.MappingStateChange, error) {
+	if r.events == nil {
+		return nil, apperrors.Unavailable("state changes of Compass Manager Mappings aren't available")
+	}
+
+	changes := r.events.Subscribe(ctx, func(mapping v1beta1.CompassManagerMapping) bool {
+		return mapping.Namespace == r.namespace &&
+			(kymaName == nil || mapping.Labels[controllers.LabelKymaName] == *kymaName) &&
+			(globalAccount == nil || mapping.Labels[controllers.LabelGlobalAccountID] == *globalAccount)
+	})
+
+	result := make(chan *gqlschema.MappingStateChange)
+	go func() {
+		defer close(result)
+		for change := range changes {
+			select {
+			case result <- toMappingStateChange(change):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return result, nil
+}
+
+func (r *Resolver) getMapping(ctx context.Context, kymaName string) (v1beta1.CompassManagerMapping, error) {
+	mappings := v1beta1.CompassManagerMappingList{}
+	err := r.client.List(ctx, &mappings, &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{controllers.LabelKymaName: kymaName}),
+		Namespace:     r.namespace,
+	})
+	if err != nil {
+		return v1beta1.CompassManagerMapping{}, apperrors.Internalf("failed to list Compass Manager Mappings: %s", err.Error())
+	}
+	if len(mappings.Items) == 0 {
+		return v1beta1.CompassManagerMapping{}, apperrors.NotFound(fmt.Sprintf("Compass Manager Mapping for Kyma %s not found", kymaName))
+	}
+	return mappings.Items[0], nil
+}
+
+func toMapping(mapping v1beta1.CompassManagerMapping) *gqlschema.Mapping {
+	result := &gqlschema.Mapping{
+		KymaName:        mapping.Labels[controllers.LabelKymaName],
+		GlobalAccount:   mapping.Labels[controllers.LabelGlobalAccountID],
+		Subaccount:      optional(mapping.Labels[controllers.LabelSubaccountID]),
+		RuntimeID:       optional(mapping.Labels[controllers.LabelCompassID]),
+		Director:        optional(mapping.Labels[controllers.LabelCompassDirector]),
+		Registered:      mapping.Status.Registered,
+		Configured:      mapping.Status.Configured,
+		RuntimeContexts: []*gqlschema.RuntimeContext{},
+		Formations:      []string{},
+	}
+	if result.RuntimeID != nil {
+		result.Tenant = optional(controllers.MappingTenant(mapping))
+	}
+	if state := gqlschema.MappingState(mapping.Status.State); state.IsValid() {
+		result.State = &state
+	}
+	for _, runtimeContext := range mapping.Status.RuntimeContexts {
+		result.RuntimeContexts = append(result.RuntimeContexts, &gqlschema.RuntimeContext{ID: runtimeContext.ID, Subaccount: runtimeContext.Subaccount})
+	}
+	result.Formations = append(result.Formations, mapping.Status.Formations...)
+	return result
+}
+
+func toMappingStateChange(change MappingStateChange) *gqlschema.MappingStateChange {
+	result := &gqlschema.MappingStateChange{Mapping: toMapping(change.Mapping)}
+	if state := gqlschema.MappingState(change.PreviousState); state.IsValid() {
+		result.PreviousState = &state
+	}
+	return result
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/kyma-project/compass-manager/pkg/gqlschema"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 10 * time.Second
+)
+
+// Server serves the GraphQL API at the endpoint to callers authenticated and authorized by auth,
+// errors are presented with their code, reason and component in extensions
+type Server struct {
+	server *http.Server
+	log    *log.Logger
+}
+
+func NewServer(address, endpoint string, resolver *Resolver, auth Auth, log *log.Logger) *Server {
+	schema := gqlschema.NewExecutableSchema(gqlschema.Config{Resolvers: resolver})
+
+	handler := newHandler(schema, apperrors.NewPresenter(log).Do, auth, log)
+	mux := http.NewServeMux()
+	mux.Handle(endpoint, handler)
+
+	server := &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	// websocket connections are hijacked, so they aren't closed by the server on shutdown
+	server.RegisterOnShutdown(handler.shutdown)
+
+	return &Server{
+		server: server,
+		log:    log,
+	}
+}
+
+// Handler returns the handler of the API, e.g. to serve it in tests
+func (s *Server) Handler() http.Handler {
+	return s.server.Handler
+}
+
+// Start serves the API until the context is done, it implements manager.Runnable
+func (s *Server) Start(ctx context.Context) error {
+	errs := make(chan error, 1)
+	go func() {
+		s.log.Infof("Serving GraphQL API on %s", s.server.Addr)
+		errs <- s.server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := s.server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
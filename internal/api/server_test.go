@@ -0,0 +1,258 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	directorgraphql "github.com/kyma-incubator/compass/components/director/pkg/graphql"
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/controllers"
+	"github.com/kyma-project/compass-manager/internal/api"
+	"github.com/kyma-project/compass-manager/internal/api/mocks"
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/kyma-project/compass-manager/internal/director"
+	"github.com/kyma-project/compass-manager/internal/director/fake"
+	directormocks "github.com/kyma-project/compass-manager/internal/director/mocks"
+	"github.com/kyma-project/compass-manager/internal/graphql"
+	"github.com/kyma-project/compass-manager/internal/oauth"
+	"github.com/kyma-project/compass-manager/pkg/gqlschema"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	namespace = "kcp-system"
+	endpoint  = "/graphql"
+	token     = "token"
+)
+
+var caller = api.User{Name: "operator", UID: "uid", Groups: []string{"operators"}}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func TestServer_Queries(t *testing.T) {
+	t.Run("should return mappings of the global account in the state", func(t *testing.T) {
+		// given
+		ready := newMapping("ready", "globalAccount", "runtime-ready")
+		ready.Status = v1beta1.CompassManagerMappingStatus{Registered: true, Configured: true, State: "Ready", Formations: []string{"formation"}}
+		failed := newMapping("failed", "globalAccount", "")
+		failed.Status = v1beta1.CompassManagerMappingStatus{State: "Failed"}
+		other := newMapping("other", "otherAccount", "runtime-other")
+		other.Status = v1beta1.CompassManagerMappingStatus{Registered: true, Configured: true, State: "Ready"}
+
+		server := newTestServer(t, newFakeClient(t, ready, failed, other), nil, nil)
+		defer server.Close()
+
+		// when
+		resp := post(t, server, `query { mappings(globalAccount: "globalAccount", state: Ready) { kymaName runtimeID tenant state formations } }`)
+
+		// then
+		require.Empty(t, resp.Errors)
+		assert.JSONEq(t, `{"mappings": [{"kymaName": "ready", "runtimeID": "runtime-ready", "tenant": "globalAccount", "state": "Ready", "formations": ["formation"]}]}`, string(resp.Data))
+	})
+
+	t.Run("should return details of the runtime from Director", func(t *testing.T) {
+		// given
+		directorServer := fake.NewServer(fake.Config{})
+		defer directorServer.Close()
+
+		oauthClient := oauth.NewOauthClient(http.DefaultClient, fake.DefaultClientID, fake.DefaultClientSecret, directorServer.TokensEndpoint())
+		directorClient := director.NewDirectorClient(graphql.NewGraphQLClient(directorServer.DirectorURL(), false, false), oauthClient)
+
+		compassID, err := directorClient.CreateRuntime(&gqlschema.RuntimeInput{Name: "runtime"}, "globalAccount")
+		require.NoError(t, err)
+		directorServer.Director.AddFormation("globalAccount", "formation")
+		require.NoError(t, directorClient.AssignFormation(compassID, "formation", "globalAccount"))
+		directorServer.Director.AddApplication("globalAccount", "application", "formation")
+
+		server := newTestServer(t, newFakeClient(t, newMapping("kyma", "globalAccount", compassID)), nil, director.NewSingleDirectorRegistry(directorClient, fake.ConnectorPath))
+		defer server.Close()
+
+		// when
+		resp := post(t, server, `query { runtime(kymaName: "kyma") { id name formations applicationCount applications } }`)
+
+		// then
+		require.Empty(t, resp.Errors)
+		assert.JSONEq(t, `{"runtime": {"id": "`+compassID+`", "name": "runtime", "formations": ["formation"], "applicationCount": 1, "applications": ["application"]}}`, string(resp.Data))
+	})
+
+	t.Run("should skip missing applications of the runtime", func(t *testing.T) {
+		// given
+		directorClient := directormocks.NewClient(t)
+		directorClient.On("GetRuntime", "runtime-id", "globalAccount").Return(directorgraphql.RuntimeExt{Runtime: directorgraphql.Runtime{ID: "runtime-id"}}, nil)
+		directorClient.On("GetRuntimeFormations", "runtime-id", "globalAccount").Return([]directorgraphql.Formation{}, nil)
+		directorClient.On("GetRuntimeApplications", "runtime-id", "globalAccount").Return(directorgraphql.ApplicationPage{
+			Data:       []*directorgraphql.Application{nil, {Name: "application"}},
+			TotalCount: 2,
+		}, nil)
+
+		server := newTestServer(t, newFakeClient(t, newMapping("kyma", "globalAccount", "runtime-id")), nil, director.NewSingleDirectorRegistry(directorClient, fake.ConnectorPath))
+		defer server.Close()
+
+		// when
+		resp := post(t, server, `query { runtime(kymaName: "kyma") { applicationCount applications } }`)
+
+		// then
+		require.Empty(t, resp.Errors)
+		assert.JSONEq(t, `{"runtime": {"applicationCount": 2, "applications": ["application"]}}`, string(resp.Data))
+	})
+
+	t.Run("should present not found mapping with error code", func(t *testing.T) {
+		// given
+		server := newTestServer(t, newFakeClient(t), nil, nil)
+		defer server.Close()
+
+		// when
+		resp := post(t, server, `query { runtime(kymaName: "missing") { id } }`)
+
+		// then
+		require.Len(t, resp.Errors, 1)
+		assert.EqualValues(t, apperrors.CodeNotFound, resp.Errors[0].Extensions["error_code"])
+	})
+}
+
+func TestServer_Mutations(t *testing.T) {
+	kymaName := types.NamespacedName{Name: "kyma", Namespace: namespace}
+
+	t.Run("should perform the operation and return the mapping", func(t *testing.T) {
+		// given
+		mapping := newMapping("kyma", "globalAccount", "runtime-id")
+		mapping.Status = v1beta1.CompassManagerMappingStatus{Registered: true, Configured: true, State: "Ready"}
+		operations := mocks.NewOperations(t)
+		operations.On("RotateToken", kymaName).Return(nil)
+
+		server := newTestServer(t, newFakeClient(t, mapping), operations, nil)
+		defer server.Close()
+
+		// when
+		resp := post(t, server, `mutation { rotateToken(kymaName: "kyma") { kymaName configured state } }`)
+
+		// then
+		require.Empty(t, resp.Errors)
+		assert.JSONEq(t, `{"rotateToken": {"kymaName": "kyma", "configured": true, "state": "Ready"}}`, string(resp.Data))
+	})
+
+	t.Run("should present error of the operation with its code, reason and component", func(t *testing.T) {
+		// given
+		operations := mocks.NewOperations(t)
+		operations.On("Reregister", kymaName).Return(apperrors.BadGateway("Director unavailable").SetReason(apperrors.ErrDirectorCircuitOpen).SetComponent(apperrors.ErrCompassDirector))
+
+		server := newTestServer(t, newFakeClient(t, newMapping("kyma", "globalAccount", "runtime-id")), operations, nil)
+		defer server.Close()
+
+		// when
+		resp := post(t, server, `mutation { reregister(kymaName: "kyma") { kymaName } }`)
+
+		// then
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, "Director unavailable")
+		assert.EqualValues(t, apperrors.CodeBadGateway, resp.Errors[0].Extensions["error_code"])
+		assert.EqualValues(t, apperrors.ErrDirectorCircuitOpen, resp.Errors[0].Extensions["error_reason"])
+		assert.EqualValues(t, apperrors.ErrCompassDirector, resp.Errors[0].Extensions["error_component"])
+	})
+
+	t.Run("should reject invalid query", func(t *testing.T) {
+		// given
+		server := newTestServer(t, newFakeClient(t), nil, nil)
+		defer server.Close()
+
+		// when
+		resp := post(t, server, `mutation { deregister { kymaName } }`)
+
+		// then
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0].Message, "kymaName")
+	})
+
+	t.Run("should reject too large request", func(t *testing.T) {
+		// given
+		server := newTestServer(t, newFakeClient(t), nil, nil)
+		defer server.Close()
+
+		// when
+		resp, status := postWithToken(t, server, `query { mappings { kymaName } }`+strings.Repeat(" ", 1<<20), token)
+
+		// then
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+		require.Len(t, resp.Errors, 1)
+	})
+}
+
+// newTestServer serves the API to the caller of token, allowed both to read and write
+func newTestServer(t *testing.T, reader client.Reader, operations api.Operations, directors *director.Registry) *httptest.Server {
+	t.Helper()
+	auth := mocks.NewAuth(t)
+	auth.On("Authenticate", mock.Anything, token).Return(caller, true, nil).Maybe()
+	auth.On("Authorize", mock.Anything, caller, mock.Anything).Return(true, "", nil).Maybe()
+	return newAuthTestServer(reader, operations, directors, auth, logrus.New())
+}
+
+func newAuthTestServer(reader client.Reader, operations api.Operations, directors *director.Registry, auth api.Auth, log *logrus.Logger) *httptest.Server {
+	server := api.NewServer("", endpoint, api.NewResolver(reader, operations, directors, nil, namespace, log), auth, log)
+	return httptest.NewServer(server.Handler())
+}
+
+func post(t *testing.T, server *httptest.Server, query string) response {
+	t.Helper()
+	resp, _ := postWithToken(t, server, query, token)
+	return resp
+}
+
+func postWithToken(t *testing.T, server *httptest.Server, query, bearerToken string) (response, int) {
+	t.Helper()
+	body, err := json.Marshal(map[string]string{"query": query})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+endpoint, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	}
+
+	httpResp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer httpResp.Body.Close()
+
+	resp := response{}
+	require.NoError(t, json.NewDecoder(httpResp.Body).Decode(&resp))
+	return resp, httpResp.StatusCode
+}
+
+func newFakeClient(t *testing.T, mappings ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	return ctrlfake.NewClientBuilder().WithScheme(scheme).WithObjects(mappings...).Build()
+}
+
+func newMapping(kymaName, globalAccount, compassID string) *v1beta1.CompassManagerMapping {
+	return &v1beta1.CompassManagerMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kymaName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				controllers.LabelKymaName:        kymaName,
+				controllers.LabelGlobalAccountID: globalAccount,
+				controllers.LabelCompassID:       compassID,
+			},
+		},
+	}
+}
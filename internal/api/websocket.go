@@ -0,0 +1,330 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gorilla/websocket"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Messages of the graphql-transport-ws protocol, see https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+const (
+	websocketProtocol = "graphql-transport-ws"
+
+	messageConnectionInit = "connection_init"
+	messageConnectionAck  = "connection_ack"
+	messagePing           = "ping"
+	messagePong           = "pong"
+	messageSubscribe      = "subscribe"
+	messageNext           = "next"
+	messageError          = "error"
+	messageComplete       = "complete"
+
+	closeBadRequest          = 4400
+	closeUnauthorized        = 4401
+	closeForbidden           = 4403
+	closeInitTimeout         = 4408
+	closeSubscriberExists    = 4409
+	closeTooManyInitRequests = 4429
+
+	connectionInitTimeout = 10 * time.Second
+	keepAliveInterval     = 30 * time.Second
+	writeTimeout          = 10 * time.Second
+)
+
+type message struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Callers are authenticated by bearer tokens rather than cookies, so connections from other origins are accepted
+var upgrader = websocket.Upgrader{
+	Subprotocols: []string{websocketProtocol},
+	CheckOrigin:  func(*http.Request) bool { return true },
+}
+
+// websocketConnection executes operations sent over a websocket connection with the graphql-transport-ws protocol
+type websocketConnection struct {
+	handler *handler
+	conn    *websocket.Conn
+	user    User
+
+	writeMutex sync.Mutex
+
+	operationsMutex sync.Mutex
+	operations      map[string]*operation
+}
+
+// operation is executed for a subscribe message until it's done or completed by the client
+type operation struct {
+	cancel context.CancelFunc
+}
+
+// serveWebsocket serves subscriptions, and other operations, over a websocket connection.
+// The caller is authenticated by the bearer token of the upgrade request or, when it isn't set, of the Authorization field of the connection_init payload.
+func (h *handler) serveWebsocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.log.Warnf("Failed to upgrade API connection to websocket: %v", err)
+		return
+	}
+	defer conn.Close()
+	// messages are limited as request bodies are, before the caller is authenticated too
+	conn.SetReadLimit(maxRequestSize)
+
+	c := &websocketConnection{
+		handler:    h,
+		conn:       conn,
+		operations: map[string]*operation{},
+	}
+	if conn.Subprotocol() != websocketProtocol {
+		c.close(websocket.CloseProtocolError, fmt.Sprintf("subprotocol %s is required", websocketProtocol))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-h.stopped:
+			c.close(websocket.CloseGoingAway, "server is shutting down")
+			conn.Close()
+		}
+	}()
+
+	if !c.init(ctx, r.Header.Get("Authorization")) {
+		return
+	}
+
+	go c.keepAlive(ctx)
+	c.run(ctx)
+}
+
+// init waits for the connection_init message, and acknowledges it when the caller is authenticated
+func (c *websocketConnection) init(ctx context.Context, authorization string) bool {
+	if err := c.conn.SetReadDeadline(time.Now().Add(connectionInitTimeout)); err != nil {
+		return false
+	}
+	var msg message
+	if err := c.conn.ReadJSON(&msg); err != nil {
+		c.close(closeInitTimeout, "connection initialisation timeout")
+		return false
+	}
+	if err := c.conn.SetReadDeadline(time.Time{}); err != nil {
+		return false
+	}
+	if msg.Type != messageConnectionInit {
+		c.close(closeUnauthorized, "unauthorized")
+		return false
+	}
+
+	if authorization == "" && len(msg.Payload) != 0 {
+		var payload struct {
+			Authorization string `json:"Authorization"`
+		}
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.close(closeBadRequest, "invalid connection_init payload")
+			return false
+		}
+		authorization = payload.Authorization
+	}
+
+	token, ok := bearerToken(authorization)
+	if !ok {
+		c.close(closeForbidden, "bearer token is required")
+		return false
+	}
+	user, authenticated, err := c.handler.auth.Authenticate(ctx, token)
+	if err != nil {
+		c.handler.log.Errorf("Failed to authenticate API caller: %v", err)
+		c.close(websocket.CloseInternalServerErr, "failed to authenticate")
+		return false
+	}
+	if !authenticated {
+		c.close(closeForbidden, "bearer token is not valid")
+		return false
+	}
+
+	c.user = user
+	return c.write(message{Type: messageConnectionAck}) == nil
+}
+
+// run reads messages until the connection is closed, operations still running are then cancelled
+func (c *websocketConnection) run(ctx context.Context) {
+	defer c.cancelOperations()
+
+	for {
+		var msg message
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				c.handler.log.Debugf("Closing API websocket connection of %s: %v", c.user.Name, err)
+			}
+			return
+		}
+
+		switch msg.Type {
+		case messagePing:
+			_ = c.write(message{Type: messagePong, Payload: msg.Payload})
+		case messagePong:
+		case messageSubscribe:
+			if !c.subscribe(ctx, msg) {
+				return
+			}
+		case messageComplete:
+			c.cancelOperation(msg.ID)
+		case messageConnectionInit:
+			c.close(closeTooManyInitRequests, "too many initialisation requests")
+			return
+		default:
+			c.close(closeBadRequest, fmt.Sprintf("unexpected message type %s", msg.Type))
+			return
+		}
+	}
+}
+
+// subscribe starts the operation of the subscribe message, false is returned when the connection was closed
+func (c *websocketConnection) subscribe(ctx context.Context, msg message) bool {
+	var req request
+	if msg.ID == "" || json.Unmarshal(msg.Payload, &req) != nil {
+		c.close(closeBadRequest, "invalid subscribe message")
+		return false
+	}
+
+	operationContext, errs := c.handler.operationContext(req)
+	if len(errs) != 0 {
+		_ = c.writeErrors(msg.ID, errs)
+		return true
+	}
+
+	allowed, err := c.handler.authorize(ctx, c.user, operationContext.Operation.Operation)
+	if err != nil {
+		_ = c.writeErrors(msg.ID, gqlerror.List{gqlerror.Errorf("failed to authorize")})
+		return true
+	}
+	if !allowed {
+		_ = c.writeErrors(msg.ID, gqlerror.List{gqlerror.Errorf("%s", forbidden(c.user, operationContext.Operation.Operation))})
+		return true
+	}
+
+	operationCtx, cancel := context.WithCancel(ctx)
+	op := &operation{cancel: cancel}
+	c.operationsMutex.Lock()
+	if _, exists := c.operations[msg.ID]; exists {
+		c.operationsMutex.Unlock()
+		cancel()
+		c.close(closeSubscriberExists, fmt.Sprintf("subscriber for %s already exists", msg.ID))
+		return false
+	}
+	c.operations[msg.ID] = op
+	c.operationsMutex.Unlock()
+
+	go c.execute(c.handler.executionContext(operationCtx, c.user, operationContext), msg.ID, op)
+	return true
+}
+
+// execute sends responses of the operation until it's done, the operation is then completed unless the client completed it
+func (c *websocketConnection) execute(ctx context.Context, id string, op *operation) {
+	defer c.finishOperation(id, op)
+
+	responses := c.handler.schema.Exec(ctx)
+	reported := 0
+	for {
+		response := responses(ctx)
+		if response == nil {
+			break
+		}
+		// errors of the execution are collected in the context for the whole subscription
+		errs := graphql.GetErrors(ctx)
+		response.Errors = append(response.Errors, errs[reported:]...)
+		reported = len(errs)
+
+		payload, err := json.Marshal(response)
+		if err != nil || c.write(message{ID: id, Type: messageNext, Payload: payload}) != nil {
+			return
+		}
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+	if errs := graphql.GetErrors(ctx); len(errs) > reported {
+		_ = c.writeErrors(id, errs[reported:])
+		return
+	}
+	_ = c.write(message{ID: id, Type: messageComplete})
+}
+
+func (c *websocketConnection) cancelOperation(id string) {
+	c.operationsMutex.Lock()
+	defer c.operationsMutex.Unlock()
+	if op, ok := c.operations[id]; ok {
+		op.cancel()
+		delete(c.operations, id)
+	}
+}
+
+// finishOperation releases the operation, unless the client already completed it and reused its ID
+func (c *websocketConnection) finishOperation(id string, op *operation) {
+	op.cancel()
+	c.operationsMutex.Lock()
+	defer c.operationsMutex.Unlock()
+	if c.operations[id] == op {
+		delete(c.operations, id)
+	}
+}
+
+func (c *websocketConnection) cancelOperations() {
+	c.operationsMutex.Lock()
+	defer c.operationsMutex.Unlock()
+	for id, op := range c.operations {
+		op.cancel()
+		delete(c.operations, id)
+	}
+}
+
+// keepAlive pings the client, so that idle subscriptions aren't closed by proxies
+func (c *websocketConnection) keepAlive(ctx context.Context) {
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if c.write(message{Type: messagePing}) != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *websocketConnection) writeErrors(id string, errs gqlerror.List) error {
+	payload, err := json.Marshal(errs)
+	if err != nil {
+		return err
+	}
+	return c.write(message{ID: id, Type: messageError, Payload: payload})
+}
+
+func (c *websocketConnection) write(msg message) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	if err := c.conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return err
+	}
+	return c.conn.WriteJSON(msg)
+}
+
+func (c *websocketConnection) close(code int, reason string) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(writeTimeout))
+}
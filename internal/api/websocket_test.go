@@ -0,0 +1,190 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/internal/api"
+	"github.com/kyma-project/compass-manager/internal/api/mocks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type message struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+func TestServer_Subscriptions(t *testing.T) {
+	t.Run("should stream state changes of the Kyma", func(t *testing.T) {
+		// given
+		events := api.NewMappingEvents(logrus.New())
+		server := newSubscriptionTestServer(t, events, true)
+		defer server.Close()
+		conn := dial(t, server, "Bearer "+token)
+		defer conn.Close()
+
+		// when
+		require.NoError(t, conn.WriteJSON(message{ID: "1", Type: "subscribe", Payload: json.RawMessage(
+			`{"query": "subscription { mappingStateChanged(kymaName: \"kyma\") { previousState mapping { kymaName state } } }"}`,
+		)}))
+		done := publishUntilDone(events)
+		defer close(done)
+
+		// then
+		msg := read(t, conn)
+		assert.Equal(t, "next", msg.Type)
+		assert.Equal(t, "1", msg.ID)
+		assert.JSONEq(t, `{"data": {"mappingStateChanged": {"previousState": "Processing", "mapping": {"kymaName": "kyma", "state": "Ready"}}}}`, string(msg.Payload))
+
+		// when
+		require.NoError(t, conn.WriteJSON(message{ID: "1", Type: "complete"}))
+		require.NoError(t, conn.WriteJSON(message{Type: "ping"}))
+
+		// then
+		for msg = read(t, conn); msg.Type == "next"; msg = read(t, conn) {
+		}
+		assert.Equal(t, "pong", msg.Type)
+	})
+
+	t.Run("should return error to caller not allowed to watch", func(t *testing.T) {
+		// given
+		server := newSubscriptionTestServer(t, api.NewMappingEvents(logrus.New()), false)
+		defer server.Close()
+		conn := dial(t, server, "Bearer "+token)
+		defer conn.Close()
+
+		// when
+		require.NoError(t, conn.WriteJSON(message{ID: "1", Type: "subscribe", Payload: json.RawMessage(
+			`{"query": "subscription { mappingStateChanged { mapping { kymaName } } }"}`,
+		)}))
+
+		// then
+		msg := read(t, conn)
+		assert.Equal(t, "error", msg.Type)
+		assert.Contains(t, string(msg.Payload), api.VerbWatch)
+	})
+
+	t.Run("should close connection of caller without bearer token", func(t *testing.T) {
+		// given
+		server := newSubscriptionTestServer(t, api.NewMappingEvents(logrus.New()), true)
+		defer server.Close()
+
+		conn, _, err := websocket.DefaultDialer.Dial(websocketURL(server), http.Header{"Sec-WebSocket-Protocol": {"graphql-transport-ws"}})
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// when
+		require.NoError(t, conn.WriteJSON(message{Type: "connection_init"}))
+
+		// then
+		_, _, err = conn.ReadMessage()
+		assert.True(t, websocket.IsCloseError(err, 4403))
+	})
+
+	t.Run("should close connection sending too large message", func(t *testing.T) {
+		// given
+		server := newSubscriptionTestServer(t, api.NewMappingEvents(logrus.New()), true)
+		defer server.Close()
+
+		conn, _, err := websocket.DefaultDialer.Dial(websocketURL(server), http.Header{"Sec-WebSocket-Protocol": {"graphql-transport-ws"}})
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// when
+		payload := json.RawMessage(`{"padding": "` + strings.Repeat("x", 2<<20) + `"}`)
+		require.NoError(t, conn.WriteJSON(message{Type: "connection_init", Payload: payload}))
+
+		// then
+		_, _, err = conn.ReadMessage()
+		assert.True(t, websocket.IsCloseError(err, websocket.CloseMessageTooBig), "unexpected error: %v", err)
+	})
+
+	t.Run("should refuse subscription over POST", func(t *testing.T) {
+		// given
+		server := newSubscriptionTestServer(t, api.NewMappingEvents(logrus.New()), true)
+		defer server.Close()
+
+		// when
+		resp, status := postWithToken(t, server, `subscription { mappingStateChanged { mapping { kymaName } } }`, token)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, status)
+		require.Len(t, resp.Errors, 1)
+	})
+}
+
+// newSubscriptionTestServer serves the API to the caller of token, allowed to watch when watch is true
+func newSubscriptionTestServer(t *testing.T, events *api.MappingEvents, watch bool) *httptest.Server {
+	t.Helper()
+	auth := mocks.NewAuth(t)
+	auth.On("Authenticate", mock.Anything, token).Return(caller, true, nil).Maybe()
+	auth.On("Authorize", mock.Anything, caller, api.VerbWatch).Return(watch, "", nil).Maybe()
+
+	log := logrus.New()
+	server := api.NewServer("", endpoint, api.NewResolver(newFakeClient(t), nil, nil, events, namespace, log), auth, log)
+	return httptest.NewServer(server.Handler())
+}
+
+func websocketURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http") + endpoint
+}
+
+// dial opens the connection authorized by the header, and initialises it
+func dial(t *testing.T, server *httptest.Server, authorization string) *websocket.Conn {
+	t.Helper()
+	header := http.Header{
+		"Sec-WebSocket-Protocol": {"graphql-transport-ws"},
+		"Authorization":          {authorization},
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(websocketURL(server), header)
+	require.NoError(t, err)
+
+	require.NoError(t, conn.WriteJSON(message{Type: "connection_init"}))
+	require.Equal(t, "connection_ack", read(t, conn).Type)
+	return conn
+}
+
+func read(t *testing.T, conn *websocket.Conn) message {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	msg := message{}
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+// publishUntilDone publishes state changes of the Kyma, and of another Kyma, until done is closed.
+// They're published repeatedly, as the subscription is started asynchronously.
+func publishUntilDone(events *api.MappingEvents) chan struct{} {
+	done := make(chan struct{})
+	processing := newMapping("kyma", "globalAccount", "runtime-id")
+	processing.Status = v1beta1.CompassManagerMappingStatus{State: "Processing"}
+	ready := processing.DeepCopy()
+	ready.Status.State = "Ready"
+	other := newMapping("other", "globalAccount", "runtime-id")
+	other.Status = v1beta1.CompassManagerMappingStatus{State: "Failed"}
+
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				events.OnAdd(other, false)
+				events.OnUpdate(processing, ready)
+			}
+		}
+	}()
+	return done
+}
@@ -12,6 +12,7 @@ const (
 	ErrCompassDirectorClient ErrComponent = "compass director client"
 	ErrCompassDirector       ErrComponent = "compass director"
 	ErrMpsOAuth2             ErrComponent = "mps oauth2"
+	ErrCompassConnector      ErrComponent = "compass connector"
 )
 
 const (
@@ -21,18 +22,24 @@ const (
 	ErrDirectorRuntimeIDMismatch      ErrReason = "err_director_runtime_id_mismatch"
 	ErrDirectorClientGraphqlizer      ErrReason = "err_director_client_graphqlizer"
 	ErrDirectorRuntimeIDInvalidFormat ErrReason = "err_director_runtime_id_invalid_format"
+	ErrDirectorCircuitOpen            ErrReason = "err_director_circuit_open"
+	ErrDirectorThrottled              ErrReason = "err_director_throttled"
+	ErrDirectorSchemaIncompatible     ErrReason = "err_director_schema_incompatible"
+
+	ErrConnectorTokenValidation ErrReason = "err_connector_token_validation"
 )
 
 type ErrCode int
 type CauseCode int
 
 const (
-	CodeBadGateway ErrCode = 502
-	CodeInternal   ErrCode = 500
-	CodeExternal   ErrCode = 501
-	CodeForbidden  ErrCode = 403
-	CodeBadRequest ErrCode = 400
-	CodeNotFound   ErrCode = 404
+	CodeUnavailable ErrCode = 503
+	CodeBadGateway  ErrCode = 502
+	CodeInternal    ErrCode = 500
+	CodeExternal    ErrCode = 501
+	CodeForbidden   ErrCode = 403
+	CodeBadRequest  ErrCode = 400
+	CodeNotFound    ErrCode = 404
 )
 
 const (
@@ -81,6 +88,10 @@ func External(formatted string, a ...interface{}) AppError {
 	return errorf(CodeExternal, Unknown, formatted, a...)
 }
 
+func Unavailable(formatted string, a ...interface{}) AppError {
+	return errorf(CodeUnavailable, Unknown, formatted, a...)
+}
+
 func Forbidden(formatted string, a ...interface{}) AppError {
 	return errorf(CodeForbidden, Unknown, formatted, a...)
 }
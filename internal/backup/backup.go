@@ -0,0 +1,190 @@
+// Package backup exports Compass Manager Mappings to a versioned JSON document, and imports them into a rebuilt control plane
+// so that Kymas keep their Compass runtimes instead of being registered again.
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/controllers"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Kind identifies documents written by Export
+	Kind = "CompassManagerMappingBackup"
+	// Version is the version of the document written by Export, documents of other versions aren't imported
+	Version = "v1"
+)
+
+// Document is the exported state of mappings of one namespace
+type Document struct {
+	Kind       string    `json:"kind"`
+	Version    string    `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+	Namespace  string    `json:"namespace"`
+	Mappings   []Mapping `json:"mappings"`
+}
+
+// Mapping links a Kyma to its Compass runtime, with the labels of the Kyma the mapping is labelled with
+type Mapping struct {
+	KymaName      string                              `json:"kymaName"`
+	RuntimeID     string                              `json:"runtimeID,omitempty"`
+	GlobalAccount string                              `json:"globalAccount"`
+	Subaccount    string                              `json:"subaccount,omitempty"`
+	Director      string                              `json:"director,omitempty"`
+	Tenant        string                              `json:"tenant,omitempty"`
+	Status        v1beta1.CompassManagerMappingStatus `json:"status"`
+}
+
+// Action is what Import did with a mapping of the document
+type Action string
+
+const (
+	// Imported mapping was created
+	Imported Action = "Imported"
+	// Skipped mapping either has no runtime, or already exists with the same runtime
+	Skipped Action = "Skipped"
+	// Conflict mapping already exists with a different runtime, it's left unchanged
+	Conflict Action = "Conflict"
+	// Failed mapping couldn't be created
+	Failed Action = "Failed"
+)
+
+// Outcome is the result of the import of a mapping
+type Outcome struct {
+	KymaName string `json:"kymaName"`
+	Action   Action `json:"action"`
+	Details  string `json:"details,omitempty"`
+}
+
+// Export reads mappings of the namespace into a document, sorted by the Kyma name
+func Export(ctx context.Context, reader client.Reader, namespace string, now time.Time) (Document, error) {
+	mappings := v1beta1.CompassManagerMappingList{}
+	if err := reader.List(ctx, &mappings, client.InNamespace(namespace)); err != nil {
+		return Document{}, errors.Wrap(err, "failed to list Compass Manager Mappings")
+	}
+
+	document := Document{
+		Kind:       Kind,
+		Version:    Version,
+		ExportedAt: now.UTC(),
+		Namespace:  namespace,
+		Mappings:   []Mapping{},
+	}
+	for _, mapping := range mappings.Items {
+		tenant := controllers.MappingTenant(mapping)
+		if tenant == mapping.Labels[controllers.LabelGlobalAccountID] {
+			tenant = ""
+		}
+		document.Mappings = append(document.Mappings, Mapping{
+			KymaName:      mapping.Labels[controllers.LabelKymaName],
+			RuntimeID:     mapping.Labels[controllers.LabelCompassID],
+			GlobalAccount: mapping.Labels[controllers.LabelGlobalAccountID],
+			Subaccount:    mapping.Labels[controllers.LabelSubaccountID],
+			Director:      mapping.Labels[controllers.LabelCompassDirector],
+			Tenant:        tenant,
+			Status:        mapping.Status,
+		})
+	}
+	sort.Slice(document.Mappings, func(i, j int) bool {
+		return document.Mappings[i].KymaName < document.Mappings[j].KymaName
+	})
+	return document, nil
+}
+
+// Read decodes the document and checks it's of a supported version
+func Read(r io.Reader) (Document, error) {
+	document := Document{}
+	if err := json.NewDecoder(r).Decode(&document); err != nil {
+		return Document{}, errors.Wrap(err, "failed to decode mappings document")
+	}
+	if document.Kind != Kind {
+		return Document{}, errors.Errorf("document of kind %q isn't a %s", document.Kind, Kind)
+	}
+	if document.Version != Version {
+		return Document{}, errors.Errorf("version %q of the document isn't supported, expected %q", document.Version, Version)
+	}
+	return document, nil
+}
+
+// Import creates mappings of the document in the namespace, with the finalizer and labels written for registered runtimes, and their status.
+// Mappings the controller created for Kymas before their runtimes were registered get the runtime IDs of the document,
+// other existing mappings aren't changed, and runtimes aren't registered, so the import can be repeated.
+func Import(kubectl controllers.Client, log *logrus.Logger, document Document, namespace string) []Outcome {
+	cluster := controllers.NewControlPlaneInterface(kubectl, log, false)
+
+	outcomes := make([]Outcome, 0, len(document.Mappings))
+	for _, mapping := range document.Mappings {
+		outcomes = append(outcomes, importMapping(cluster, mapping, namespace))
+	}
+	return outcomes
+}
+
+func importMapping(cluster *controllers.ControlPlaneInterface, mapping Mapping, namespace string) Outcome {
+	outcome := Outcome{KymaName: mapping.KymaName}
+	if mapping.RuntimeID == "" {
+		outcome.Action = Skipped
+		outcome.Details = "runtime isn't registered"
+		return outcome
+	}
+
+	name := types.NamespacedName{Name: mapping.KymaName, Namespace: namespace}
+	existing, err := cluster.GetCompassMapping(name)
+	switch {
+	case err == nil && existing.Labels[controllers.LabelCompassID] == mapping.RuntimeID:
+		outcome.Action = Skipped
+		outcome.Details = "mapping exists"
+		return outcome
+	case err == nil && existing.Labels[controllers.LabelCompassID] == "":
+		return fillMapping(cluster, mapping, name)
+	case err == nil:
+		outcome.Action = Conflict
+		outcome.Details = fmt.Sprintf("mapping exists with runtime %q", existing.Labels[controllers.LabelCompassID])
+		return outcome
+	case !controllers.IsNotFound(err):
+		outcome.Action = Failed
+		outcome.Details = err.Error()
+		return outcome
+	}
+
+	kymaLabels := map[string]string{
+		controllers.LabelKymaName:        mapping.KymaName,
+		controllers.LabelGlobalAccountID: mapping.GlobalAccount,
+		controllers.LabelSubaccountID:    mapping.Subaccount,
+	}
+	if err := cluster.RestoreCompassMapping(name, kymaLabels, mapping.RuntimeID, mapping.Director, mapping.Tenant, mapping.Status); err != nil {
+		outcome.Action = Failed
+		outcome.Details = err.Error()
+		return outcome
+	}
+	outcome.Action = Imported
+	return outcome
+}
+
+// fillMapping stores the runtime ID and the status of the imported mapping in the mapping the controller created for the Kyma before registration,
+// so that the controller doesn't register the Kyma again
+func fillMapping(cluster *controllers.ControlPlaneInterface, mapping Mapping, name types.NamespacedName) Outcome {
+	outcome := Outcome{KymaName: mapping.KymaName}
+	if err := cluster.UpsertCompassMapping(name, mapping.RuntimeID, mapping.Director, mapping.Tenant); err != nil {
+		outcome.Action = Failed
+		outcome.Details = err.Error()
+		return outcome
+	}
+	if err := cluster.RestoreCompassMappingStatus(name, mapping.Status); err != nil {
+		outcome.Action = Failed
+		outcome.Details = err.Error()
+		return outcome
+	}
+	outcome.Action = Imported
+	outcome.Details = "runtime ID set in existing mapping"
+	return outcome
+}
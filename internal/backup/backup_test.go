@@ -0,0 +1,176 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/controllers"
+	s "github.com/kyma-project/compass-manager/controllers/status"
+	kyma "github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const namespace = "kcp-system"
+
+func TestExportImport(t *testing.T) {
+	kymaName := types.NamespacedName{Name: "kyma", Namespace: namespace}
+	status := v1beta1.CompassManagerMappingStatus{
+		Registered:      true,
+		Configured:      true,
+		State:           "Ready",
+		RuntimeContexts: []v1beta1.RuntimeContext{{ID: "context-id", Subaccount: "other-subaccount"}},
+		Formations:      []string{"formation"},
+	}
+
+	t.Run("should recreate mappings as they're written for registered runtimes", func(t *testing.T) {
+		// given
+		source := newFakeClient(t, newKyma())
+		cluster := controllers.NewControlPlaneInterface(source, logrus.New(), false)
+		require.NoError(t, cluster.UpsertCompassMapping(kymaName, "runtime-id", "director", "subaccount"))
+		written, err := cluster.GetCompassMapping(kymaName)
+		require.NoError(t, err)
+		written.Status = status
+		require.NoError(t, source.Status().Update(context.Background(), &written))
+
+		document, err := Export(context.Background(), source, namespace, time.Now())
+		require.NoError(t, err)
+		read := roundTrip(t, document)
+		target := newFakeClient(t)
+
+		// when
+		outcomes := Import(target, logrus.New(), read, namespace)
+
+		// then
+		assert.Equal(t, []Outcome{{KymaName: "kyma", Action: Imported}}, outcomes)
+		imported, err := controllers.NewControlPlaneInterface(target, logrus.New(), false).GetCompassMapping(kymaName)
+		require.NoError(t, err)
+		assert.Equal(t, written.Labels, imported.Labels)
+		assert.Equal(t, []string{controllers.Finalizer}, imported.Finalizers)
+		assert.Equal(t, status, imported.Status)
+	})
+
+	t.Run("should leave existing mappings and mappings without runtime", func(t *testing.T) {
+		// given
+		document := Document{Kind: Kind, Version: Version, Mappings: []Mapping{
+			{KymaName: "kyma", RuntimeID: "runtime-id", GlobalAccount: "globalAccount"},
+			{KymaName: "conflicting", RuntimeID: "runtime-id", GlobalAccount: "globalAccount"},
+			{KymaName: "not-registered", GlobalAccount: "globalAccount"},
+		}}
+		target := newFakeClient(t, newMapping("kyma", "runtime-id"), newMapping("conflicting", "other-runtime-id"))
+
+		// when
+		outcomes := Import(target, logrus.New(), document, namespace)
+
+		// then
+		require.Len(t, outcomes, 3)
+		assert.Equal(t, Skipped, outcomes[0].Action)
+		assert.Equal(t, Conflict, outcomes[1].Action)
+		assert.Equal(t, Skipped, outcomes[2].Action)
+
+		mappings := v1beta1.CompassManagerMappingList{}
+		require.NoError(t, target.List(context.Background(), &mappings))
+		assert.Len(t, mappings.Items, 2)
+	})
+
+	t.Run("should set runtime ID in mapping the controller created before registration", func(t *testing.T) {
+		// given
+		document := Document{Kind: Kind, Version: Version, Mappings: []Mapping{
+			{KymaName: "kyma", RuntimeID: "runtime-id", GlobalAccount: "globalAccount", Director: "director", Tenant: "subaccount", Status: status},
+		}}
+		target := newFakeClient(t, newKyma())
+		cluster := controllers.NewControlPlaneInterface(target, logrus.New(), false)
+		require.NoError(t, cluster.CreateCompassMapping(kymaName))
+		require.NoError(t, cluster.SetCompassMappingStatus(kymaName, s.Processing))
+
+		// when
+		outcomes := Import(target, logrus.New(), document, namespace)
+
+		// then
+		require.Len(t, outcomes, 1)
+		assert.Equal(t, Imported, outcomes[0].Action)
+		imported, err := cluster.GetCompassMapping(kymaName)
+		require.NoError(t, err)
+		assert.Equal(t, "runtime-id", imported.Labels[controllers.LabelCompassID])
+		assert.Equal(t, "director", imported.Labels[controllers.LabelCompassDirector])
+		assert.Equal(t, "subaccount", imported.Labels[controllers.LabelCompassTenant])
+		assert.Equal(t, status, imported.Status)
+	})
+}
+
+func TestRead(t *testing.T) {
+	t.Run("should reject document of other version", func(t *testing.T) {
+		// given
+		document := `{"kind": "CompassManagerMappingBackup", "version": "v2", "mappings": []}`
+
+		// when
+		_, err := Read(bytes.NewBufferString(document))
+
+		// then
+		require.ErrorContains(t, err, "v2")
+	})
+
+	t.Run("should reject document of other kind", func(t *testing.T) {
+		// given
+		document := `{"kind": "Kyma", "version": "v1"}`
+
+		// when
+		_, err := Read(bytes.NewBufferString(document))
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func roundTrip(t *testing.T, document Document) Document {
+	t.Helper()
+	encoded, err := json.Marshal(document)
+	require.NoError(t, err)
+	read, err := Read(bytes.NewReader(encoded))
+	require.NoError(t, err)
+	return read
+}
+
+func newFakeClient(t *testing.T, objects ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	require.NoError(t, kyma.AddToScheme(scheme))
+	return ctrlfake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithStatusSubresource(&v1beta1.CompassManagerMapping{}).Build()
+}
+
+func newKyma() *kyma.Kyma {
+	return &kyma.Kyma{ObjectMeta: metav1.ObjectMeta{
+		Name:      "kyma",
+		Namespace: namespace,
+		Labels: map[string]string{
+			controllers.LabelKymaName:        "kyma",
+			controllers.LabelGlobalAccountID: "globalAccount",
+			controllers.LabelSubaccountID:    "subaccount",
+		},
+	}}
+}
+
+func newMapping(kymaName, compassID string) *v1beta1.CompassManagerMapping {
+	return &v1beta1.CompassManagerMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kymaName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				controllers.LabelKymaName:        kymaName,
+				controllers.LabelCompassID:       compassID,
+				controllers.LabelGlobalAccountID: "globalAccount",
+			},
+		},
+	}
+}
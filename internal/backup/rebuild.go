@@ -0,0 +1,206 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kyma-incubator/compass/components/director/pkg/graphql"
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/controllers"
+	"github.com/kyma-project/compass-manager/internal/director"
+	kyma "github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Recreated mapping was created, or its runtime ID was set, with the runtime matched in Director
+	Recreated Action = "Recreated"
+	// Matched runtime would be set on the mapping, it's reported instead of Recreated in a dry run
+	Matched Action = "Matched"
+	// Ambiguous Kyma is matched by more than one runtime, or its runtime matches more than one Kyma, so no runtime is picked
+	Ambiguous Action = "Ambiguous"
+	// Unmatched Kyma isn't matched by any runtime, it's registered by compass-manager as a new Kyma
+	Unmatched Action = "Unmatched"
+)
+
+// RebuildOptions configure how runtimes are looked up for Kymas
+type RebuildOptions struct {
+	Namespace string
+	// RegisterInSubaccount looks up runtimes in the subaccount tenant of the Kyma, as compass-manager registers them with APP_REGISTER_IN_SUBACCOUNT
+	RegisterInSubaccount bool
+	// DryRun reports the runtimes matched to Kymas without changing mappings
+	DryRun bool
+}
+
+// candidate is a Kyma without the runtime ID on its mapping
+type candidate struct {
+	kyma          kyma.Kyma
+	director      string
+	tenant        string
+	mappingExists bool
+}
+
+// tenantKey is a tenant of a Director whose runtimes are listed
+type tenantKey struct {
+	director string
+	tenant   string
+}
+
+// Rebuild recreates mappings of Kymas with the application-connector module which have no mapping or no runtime ID on it.
+// Runtimes registered by compass-manager are listed from Director and matched to Kymas by the broker instance ID or the shoot name,
+// Kymas with more than one matching runtime, or sharing it with another Kyma, are reported as ambiguous.
+func Rebuild(ctx context.Context, kubectl controllers.Client, directors *director.Registry, log *logrus.Logger, opts RebuildOptions) ([]Outcome, error) {
+	kymaList := kyma.KymaList{}
+	if err := kubectl.List(ctx, &kymaList, client.InNamespace(opts.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list Kymas")
+	}
+	mappings := v1beta1.CompassManagerMappingList{}
+	if err := kubectl.List(ctx, &mappings, client.InNamespace(opts.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list Compass Manager Mappings")
+	}
+	mapped := map[string]bool{}
+	for _, mapping := range mappings.Items {
+		if runtimeID := mapping.Labels[controllers.LabelCompassID]; runtimeID != "" {
+			mapped[runtimeID] = true
+		}
+	}
+
+	cluster := controllers.NewControlPlaneInterface(kubectl, log, false)
+	sort.Slice(kymaList.Items, func(i, j int) bool { return kymaList.Items[i].Name < kymaList.Items[j].Name })
+
+	var outcomes []Outcome
+	var candidates []candidate
+	for _, kymaCR := range kymaList.Items {
+		if !controllers.HasApplicationConnectorModule(kymaCR) {
+			continue
+		}
+		mapping, err := cluster.GetCompassMapping(types.NamespacedName{Name: kymaCR.Name, Namespace: kymaCR.Namespace})
+		switch {
+		case err == nil && mapping.Labels[controllers.LabelCompassID] != "":
+			outcomes = append(outcomes, Outcome{KymaName: kymaCR.Name, Action: Skipped, Details: "mapping exists"})
+			continue
+		case err != nil && !controllers.IsNotFound(err):
+			outcomes = append(outcomes, Outcome{KymaName: kymaCR.Name, Action: Failed, Details: err.Error()})
+			continue
+		}
+
+		globalAccount := kymaCR.Labels[controllers.LabelGlobalAccountID]
+		tenant := globalAccount
+		if opts.RegisterInSubaccount && kymaCR.Labels[controllers.LabelSubaccountID] != "" {
+			tenant = kymaCR.Labels[controllers.LabelSubaccountID]
+		}
+		candidates = append(candidates, candidate{
+			kyma:          kymaCR,
+			director:      directors.SelectDirector(kymaCR.Labels, globalAccount),
+			tenant:        tenant,
+			mappingExists: err == nil,
+		})
+	}
+
+	runtimes, listErrors := listManagedRuntimes(directors, candidates)
+
+	// matches holds runtimes not referred to by any mapping which match the candidate, kymasOfRuntime counts candidates matched by the runtime
+	matches := make([][]graphql.RuntimeExt, len(candidates))
+	kymasOfRuntime := map[string][]string{}
+	for i, c := range candidates {
+		for _, runtime := range runtimes[tenantKey{c.director, c.tenant}] {
+			if !mapped[runtime.ID] && controllers.RuntimeMatchesKyma(runtime.Labels, c.kyma.Labels) {
+				matches[i] = append(matches[i], runtime)
+				kymasOfRuntime[runtime.ID] = append(kymasOfRuntime[runtime.ID], c.kyma.Name)
+			}
+		}
+	}
+
+	for i, c := range candidates {
+		outcome := Outcome{KymaName: c.kyma.Name}
+		if err, failed := listErrors[tenantKey{c.director, c.tenant}]; failed {
+			outcome.Action = Failed
+			outcome.Details = err.Error()
+			outcomes = append(outcomes, outcome)
+			continue
+		}
+
+		switch {
+		case len(matches[i]) == 0:
+			outcome.Action = Unmatched
+			outcome.Details = "no runtime registered by compass-manager matches the Kyma"
+		case len(matches[i]) > 1:
+			outcome.Action = Ambiguous
+			outcome.Details = fmt.Sprintf("runtimes %s match the Kyma", runtimeIDs(matches[i]))
+		case len(kymasOfRuntime[matches[i][0].ID]) > 1:
+			outcome.Action = Ambiguous
+			outcome.Details = fmt.Sprintf("runtime %s matches Kymas %s", matches[i][0].ID, strings.Join(kymasOfRuntime[matches[i][0].ID], ", "))
+		default:
+			outcome = recreateMapping(cluster, c, matches[i][0].ID, opts.DryRun)
+		}
+		outcomes = append(outcomes, outcome)
+	}
+
+	sort.SliceStable(outcomes, func(i, j int) bool { return outcomes[i].KymaName < outcomes[j].KymaName })
+	return outcomes, nil
+}
+
+// listManagedRuntimes lists runtimes registered by compass-manager in tenants of the candidates
+func listManagedRuntimes(directors *director.Registry, candidates []candidate) (map[tenantKey][]graphql.RuntimeExt, map[tenantKey]error) {
+	runtimes := map[tenantKey][]graphql.RuntimeExt{}
+	listErrors := map[tenantKey]error{}
+	for _, c := range candidates {
+		key := tenantKey{c.director, c.tenant}
+		if _, listed := runtimes[key]; listed {
+			continue
+		}
+		if _, failed := listErrors[key]; failed {
+			continue
+		}
+
+		endpoint, err := directors.Get(c.director)
+		if err != nil {
+			listErrors[key] = err
+			continue
+		}
+		listed, appErr := endpoint.Client.ListRuntimes(controllers.RuntimeLabelManagedBy, controllers.ManagedBy, c.tenant)
+		if appErr != nil {
+			listErrors[key] = errors.Wrapf(appErr, "failed to list runtimes of tenant %s", c.tenant)
+			continue
+		}
+		runtimes[key] = listed
+	}
+	return runtimes, listErrors
+}
+
+func recreateMapping(cluster *controllers.ControlPlaneInterface, c candidate, runtimeID string, dryRun bool) Outcome {
+	outcome := Outcome{KymaName: c.kyma.Name, Details: fmt.Sprintf("runtime %s", runtimeID)}
+	if dryRun {
+		outcome.Action = Matched
+		return outcome
+	}
+
+	name := types.NamespacedName{Name: c.kyma.Name, Namespace: c.kyma.Namespace}
+	var err error
+	if c.mappingExists {
+		err = cluster.UpsertCompassMapping(name, runtimeID, c.director, c.tenant)
+	} else {
+		err = cluster.RestoreCompassMapping(name, c.kyma.Labels, runtimeID, c.director, c.tenant, v1beta1.CompassManagerMappingStatus{})
+	}
+	if err != nil {
+		outcome.Action = Failed
+		outcome.Details = err.Error()
+		return outcome
+	}
+	outcome.Action = Recreated
+	return outcome
+}
+
+func runtimeIDs(runtimes []graphql.RuntimeExt) string {
+	ids := make([]string, 0, len(runtimes))
+	for _, runtime := range runtimes {
+		ids = append(ids, runtime.ID)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ", ")
+}
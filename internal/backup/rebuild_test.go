@@ -0,0 +1,139 @@
+package backup
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/controllers"
+	"github.com/kyma-project/compass-manager/internal/director"
+	"github.com/kyma-project/compass-manager/internal/director/fake"
+	"github.com/kyma-project/compass-manager/internal/graphql"
+	"github.com/kyma-project/compass-manager/internal/oauth"
+	"github.com/kyma-project/compass-manager/pkg/gqlschema"
+	kyma "github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRebuild(t *testing.T) {
+	directorServer := fake.NewServer(fake.Config{})
+	defer directorServer.Close()
+
+	oauthClient := oauth.NewOauthClient(http.DefaultClient, fake.DefaultClientID, fake.DefaultClientSecret, directorServer.TokensEndpoint())
+	directorClient := director.NewDirectorClient(graphql.NewGraphQLClient(directorServer.DirectorURL(), false, false), oauthClient)
+	directors := director.NewSingleDirectorRegistry(directorClient, fake.ConnectorPath)
+	register := func(kymaLabels map[string]string) string {
+		labels := gqlschema.Labels{}
+		for key, value := range controllers.CompassRuntimeLabels(kymaLabels) {
+			labels[key] = value
+		}
+		runtimeID, appErr := directorClient.CreateRuntime(&gqlschema.RuntimeInput{Name: kymaLabels[controllers.LabelShootName], Labels: labels}, "globalAccount")
+		require.NoError(t, appErr)
+		return runtimeID
+	}
+
+	mapped := newModuleKyma("mapped")
+	lost := newModuleKyma("lost")
+	reset := newModuleKyma("reset")
+	twice := newModuleKyma("twice")
+	unregistered := newModuleKyma("unregistered")
+	first := newModuleKyma("first")
+	second := newModuleKyma("second")
+
+	mappedID := register(mapped.Labels)
+	lostID := register(lost.Labels)
+	resetID := register(reset.Labels)
+	register(twice.Labels)
+	register(twice.Labels)
+	sharedID := register(map[string]string{
+		controllers.LabelShootName:        first.Labels[controllers.LabelShootName],
+		controllers.LabelBrokerInstanceID: second.Labels[controllers.LabelBrokerInstanceID],
+		controllers.LabelGlobalAccountID:  "globalAccount",
+	})
+
+	objects := func() []client.Object {
+		return []client.Object{
+			mapped.DeepCopy(), lost.DeepCopy(), reset.DeepCopy(), twice.DeepCopy(), unregistered.DeepCopy(), first.DeepCopy(), second.DeepCopy(),
+			newMapping("mapped", mappedID),
+			newMapping("reset", ""),
+		}
+	}
+	actions := func(outcomes []Outcome) map[string]Action {
+		byKyma := map[string]Action{}
+		for _, outcome := range outcomes {
+			byKyma[outcome.KymaName] = outcome.Action
+		}
+		return byKyma
+	}
+
+	t.Run("should recreate mappings of Kymas matched by exactly one runtime", func(t *testing.T) {
+		// given
+		kubectl := newFakeClient(t, objects()...)
+
+		// when
+		outcomes, err := Rebuild(context.Background(), kubectl, directors, logrus.New(), RebuildOptions{Namespace: namespace})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]Action{
+			"first":        Ambiguous,
+			"lost":         Recreated,
+			"mapped":       Skipped,
+			"reset":        Recreated,
+			"second":       Ambiguous,
+			"twice":        Ambiguous,
+			"unregistered": Unmatched,
+		}, actions(outcomes))
+		assert.Equal(t, "first", outcomes[0].KymaName)
+		assert.Contains(t, outcomes[0].Details, sharedID)
+
+		cluster := controllers.NewControlPlaneInterface(kubectl, logrus.New(), false)
+		for kymaName, runtimeID := range map[string]string{"lost": lostID, "reset": resetID} {
+			mapping, err := cluster.GetCompassMapping(types.NamespacedName{Name: kymaName, Namespace: namespace})
+			require.NoError(t, err)
+			assert.Equal(t, runtimeID, mapping.Labels[controllers.LabelCompassID])
+		}
+		mappings := v1beta1.CompassManagerMappingList{}
+		require.NoError(t, kubectl.List(context.Background(), &mappings))
+		assert.Len(t, mappings.Items, 3)
+	})
+
+	t.Run("should only report matches in a dry run", func(t *testing.T) {
+		// given
+		kubectl := newFakeClient(t, objects()...)
+
+		// when
+		outcomes, err := Rebuild(context.Background(), kubectl, directors, logrus.New(), RebuildOptions{Namespace: namespace, DryRun: true})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, Matched, actions(outcomes)["lost"])
+		assert.Equal(t, Matched, actions(outcomes)["reset"])
+
+		mappings := v1beta1.CompassManagerMappingList{}
+		require.NoError(t, kubectl.List(context.Background(), &mappings))
+		assert.Len(t, mappings.Items, 2)
+	})
+}
+
+func newModuleKyma(name string) *kyma.Kyma {
+	return &kyma.Kyma{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				controllers.LabelKymaName:         name,
+				controllers.LabelGlobalAccountID:  "globalAccount",
+				controllers.LabelShootName:        name + "-shoot",
+				controllers.LabelBrokerInstanceID: name + "-instance",
+			},
+		},
+		Status: kyma.KymaStatus{Modules: []kyma.ModuleStatus{{Name: controllers.ApplicationConnectorModuleName}}},
+	}
+}
@@ -0,0 +1,101 @@
+package certificate
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Loader provides a client certificate read from PEM files and reloads it when the files change,
+// which is the case when the Secret mounted with the certificate is rotated
+type Loader struct {
+	certPath      string
+	keyPath       string
+	checkInterval time.Duration
+
+	mu          sync.Mutex
+	certificate *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+	lastCheck   time.Time
+}
+
+// NewLoader reads the certificate and fails if it's not a valid key pair.
+// Files are checked for changes at most once per checkInterval.
+func NewLoader(certPath, keyPath string, checkInterval time.Duration) (*Loader, error) {
+	l := &Loader{
+		certPath:      certPath,
+		keyPath:       keyPath,
+		checkInterval: checkInterval,
+	}
+
+	certModTime, keyModTime, err := l.modTimes()
+	if err != nil {
+		return nil, err
+	}
+	if err := l.load(certModTime, keyModTime); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// GetClientCertificate can be used as tls.Config.GetClientCertificate
+func (l *Loader) GetClientCertificate(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return l.Certificate(), nil
+}
+
+// Certificate returns the current certificate, reloading it first if the files changed.
+// If reloading fails the previous certificate is kept.
+func (l *Loader) Certificate() *tls.Certificate {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if time.Since(l.lastCheck) < l.checkInterval {
+		return l.certificate
+	}
+	l.lastCheck = time.Now()
+
+	certModTime, keyModTime, err := l.modTimes()
+	if err != nil {
+		log.Warnf("Failed to check Director client certificate for changes, using the previous one: %v", err)
+		return l.certificate
+	}
+	if certModTime.Equal(l.certModTime) && keyModTime.Equal(l.keyModTime) {
+		return l.certificate
+	}
+
+	if err := l.load(certModTime, keyModTime); err != nil {
+		log.Warnf("Failed to reload Director client certificate, using the previous one: %v", err)
+		return l.certificate
+	}
+	log.Infof("Reloaded Director client certificate from %s", l.certPath)
+	return l.certificate
+}
+
+func (l *Loader) load(certModTime, keyModTime time.Time) error {
+	certificate, err := tls.LoadX509KeyPair(l.certPath, l.keyPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to load client certificate")
+	}
+
+	l.certificate = &certificate
+	l.certModTime = certModTime
+	l.keyModTime = keyModTime
+	return nil
+}
+
+func (l *Loader) modTimes() (time.Time, time.Time, error) {
+	certInfo, err := os.Stat(l.certPath)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Wrap(err, "failed to read client certificate file")
+	}
+	keyInfo, err := os.Stat(l.keyPath)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Wrap(err, "failed to read client key file")
+	}
+	return certInfo.ModTime(), keyInfo.ModTime(), nil
+}
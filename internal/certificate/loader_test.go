@@ -0,0 +1,119 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader(t *testing.T) {
+	t.Run("should load certificate", func(t *testing.T) {
+		// given
+		certPath, keyPath := writeKeyPair(t, t.TempDir(), "first", time.Now())
+
+		// when
+		loader, err := NewLoader(certPath, keyPath, 0)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "first", commonName(t, loader.Certificate()))
+	})
+
+	t.Run("should fail when certificate is missing", func(t *testing.T) {
+		// when
+		_, err := NewLoader(filepath.Join(t.TempDir(), "tls.crt"), filepath.Join(t.TempDir(), "tls.key"), 0)
+
+		// then
+		require.Error(t, err)
+	})
+
+	t.Run("should reload certificate after rotation", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		certPath, keyPath := writeKeyPair(t, dir, "first", time.Now().Add(-time.Hour))
+		loader, err := NewLoader(certPath, keyPath, 0)
+		require.NoError(t, err)
+
+		// when
+		writeKeyPair(t, dir, "rotated", time.Now())
+		certificate, err := loader.GetClientCertificate(nil)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "rotated", commonName(t, certificate))
+	})
+
+	t.Run("should keep previous certificate when rotated files are invalid", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		certPath, keyPath := writeKeyPair(t, dir, "first", time.Now().Add(-time.Hour))
+		loader, err := NewLoader(certPath, keyPath, 0)
+		require.NoError(t, err)
+
+		// when
+		require.NoError(t, os.WriteFile(certPath, []byte("invalid"), 0600))
+
+		// then
+		assert.Equal(t, "first", commonName(t, loader.Certificate()))
+	})
+
+	t.Run("should not check files before the check interval elapses", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		certPath, keyPath := writeKeyPair(t, dir, "first", time.Now().Add(-time.Hour))
+		loader, err := NewLoader(certPath, keyPath, time.Hour)
+		require.NoError(t, err)
+		loader.Certificate()
+
+		// when
+		writeKeyPair(t, dir, "rotated", time.Now())
+
+		// then
+		assert.Equal(t, "first", commonName(t, loader.Certificate()))
+	})
+}
+
+func writeKeyPair(t *testing.T, dir, commonName string, modTime time.Time) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPath := filepath.Join(dir, "tls.crt")
+	keyPath := filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	require.NoError(t, os.Chtimes(certPath, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyPath, modTime, modTime))
+
+	return certPath, keyPath
+}
+
+func commonName(t *testing.T, certificate *tls.Certificate) string {
+	require.NotNil(t, certificate)
+	parsed, err := x509.ParseCertificate(certificate.Certificate[0])
+	require.NoError(t, err)
+	return parsed.Subject.CommonName
+}
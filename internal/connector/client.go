@@ -0,0 +1,86 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	gcli "github.com/kyma-project/compass-manager/third_party/machinebox/graphql"
+	"github.com/pkg/errors"
+)
+
+const (
+	// TokenHeader authenticates requests to the Connector with a one-time token
+	TokenHeader = "Connector-Token"
+	timeout     = 30 * time.Second
+)
+
+const configurationQuery = `query {
+	result: configuration {
+		token { token }
+}}`
+
+//go:generate mockery --name=Client
+type Client interface {
+	// ValidateToken calls the configuration query of the Connector with the one-time token.
+	// It returns the token issued by the Connector in exchange, as the one-time token may be used up by the call.
+	ValidateToken(connectorURL, token string) (string, apperrors.AppError)
+}
+
+type ConfigurationResponse struct {
+	Result *struct {
+		Token *struct {
+			Token string `json:"token"`
+		} `json:"token"`
+	} `json:"result"`
+}
+
+type connectorClient struct {
+	httpClient *http.Client
+}
+
+// NewConnectorClient creates a client calling the Connector over HTTPS only, its certificate is verified by httpClient
+func NewConnectorClient(httpClient *http.Client) Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: timeout}
+	}
+	return &connectorClient{httpClient: httpClient}
+}
+
+func (c *connectorClient) ValidateToken(connectorURL, token string) (string, apperrors.AppError) {
+	endpoint, err := url.Parse(connectorURL)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return "", validationError(apperrors.BadRequest(fmt.Sprintf("Connector URL %q is not a valid HTTPS URL", connectorURL)))
+	}
+
+	req := gcli.NewRequest(configurationQuery)
+	req.Header.Set(TokenHeader, token)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var response ConfigurationResponse
+	err = gcli.NewClient(connectorURL, gcli.WithHTTPClient(c.httpClient)).Run(ctx, req, &response)
+
+	// transport errors, including failed TLS verification, are returned by the HTTP client as url.Error
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return "", validationError(apperrors.BadGateway(fmt.Sprintf("Connector %s is not reachable: %s", endpoint.Host, urlErr.Err.Error())))
+	}
+	if err != nil {
+		return "", validationError(apperrors.Forbidden("Connector %s rejected the one-time token: %s", endpoint.Host, err.Error()))
+	}
+	// Nil check is necessary due to GraphQL client not checking response code
+	if response.Result == nil || response.Result.Token == nil || response.Result.Token.Token == "" {
+		return "", validationError(apperrors.Internalf("Connector %s returned no token in the configuration", endpoint.Host))
+	}
+
+	return response.Result.Token.Token, nil
+}
+
+func validationError(err apperrors.AppError) apperrors.AppError {
+	return err.Append("Failed to validate one-time token against the Connector").SetComponent(apperrors.ErrCompassConnector).SetReason(apperrors.ErrConnectorTokenValidation)
+}
@@ -0,0 +1,79 @@
+package connector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/kyma-project/compass-manager/internal/director/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const oneTimeToken = "one-time-token"
+
+func TestConnectorClient_ValidateToken(t *testing.T) {
+	t.Run("should exchange valid token and reject it when used again", func(t *testing.T) {
+		// given
+		server := newConnectorServer()
+		defer server.Close()
+		client := NewConnectorClient(server.Client())
+
+		// when
+		exchanged, err := client.ValidateToken(server.URL+fake.ConnectorPath, oneTimeToken)
+		_, usedErr := client.ValidateToken(server.URL+fake.ConnectorPath, oneTimeToken)
+
+		// then
+		require.NoError(t, err)
+		assert.NotEmpty(t, exchanged)
+		assert.NotEqual(t, oneTimeToken, exchanged)
+
+		require.Error(t, usedErr)
+		assert.Equal(t, apperrors.CodeForbidden, usedErr.Code())
+		assert.Equal(t, apperrors.ErrConnectorTokenValidation, usedErr.Reason())
+		assert.Equal(t, apperrors.ErrCompassConnector, usedErr.Component())
+	})
+
+	t.Run("should fail when Connector certificate is not trusted", func(t *testing.T) {
+		// given
+		server := newConnectorServer()
+		defer server.Close()
+		client := NewConnectorClient(&http.Client{})
+
+		// when
+		_, err := client.ValidateToken(server.URL+fake.ConnectorPath, oneTimeToken)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, apperrors.CodeBadGateway, err.Code())
+		assert.Equal(t, apperrors.ErrConnectorTokenValidation, err.Reason())
+	})
+
+	t.Run("should not call Connector without TLS", func(t *testing.T) {
+		// given
+		client := NewConnectorClient(nil)
+
+		// when
+		_, err := client.ValidateToken("http://kyma.cloud.sap/connector/graphql", oneTimeToken)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, apperrors.CodeBadRequest, err.Code())
+		assert.Equal(t, apperrors.ErrConnectorTokenValidation, err.Reason())
+	})
+}
+
+// newConnectorServer serves the fake Connector accepting oneTimeToken once
+func newConnectorServer() *httptest.Server {
+	used := false
+	mux := http.NewServeMux()
+	mux.Handle(fake.ConnectorPath, fake.NewConnector(func(token string) bool {
+		if token != oneTimeToken || used {
+			return false
+		}
+		used = true
+		return true
+	}))
+	return httptest.NewTLSServer(mux)
+}
@@ -0,0 +1,367 @@
+// Package consistency compares Kymas and Compass Manager Mappings in the control plane with runtimes registered in Director
+package consistency
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	directorApperrors "github.com/kyma-incubator/compass/components/director/pkg/apperrors"
+	"github.com/kyma-incubator/compass/components/director/pkg/graphql"
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/controllers"
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/kyma-project/compass-manager/internal/director"
+	kyma "github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Problem is the category of an inconsistency between the control plane and Director
+type Problem string
+
+const (
+	// MissingMapping is a Kyma with the application-connector module without a mapping
+	MissingMapping Problem = "MissingMapping"
+	// MappingWithoutRuntime is a mapping without the ID of a runtime registered in Compass
+	MappingWithoutRuntime Problem = "MappingWithoutRuntime"
+	// RuntimeNotFound is a mapping with the ID of a runtime which doesn't exist in Director
+	RuntimeNotFound Problem = "RuntimeNotFound"
+	// OrphanedRuntime is a runtime registered by compass-manager which no mapping refers to
+	OrphanedRuntime Problem = "OrphanedRuntime"
+	// LabelMismatch is a mapping or runtime whose labels identify a different Kyma than the one it belongs to
+	LabelMismatch Problem = "LabelMismatch"
+	// DuplicateRegistration is a runtime registered for a Kyma which already has another one, or referred to by more than one mapping
+	DuplicateRegistration Problem = "DuplicateRegistration"
+	// RuntimeUnreadable is a runtime, or runtimes of a tenant, which couldn't be read from Director, so it isn't known if they're consistent
+	RuntimeUnreadable Problem = "RuntimeUnreadable"
+)
+
+// identityLabels map runtime labels identifying the Kyma to the Kyma labels they're set from, other labels may change after registration
+var identityLabels = map[string]string{ //nolint:gochecknoglobals
+	controllers.RuntimeLabelGlobalAccountID:  controllers.LabelGlobalAccountID,
+	controllers.RuntimeLabelSubaccountID:     controllers.LabelSubaccountID,
+	controllers.RuntimeLabelBrokerInstanceID: controllers.LabelBrokerInstanceID,
+	controllers.RuntimeLabelShootName:        controllers.LabelShootName,
+}
+
+// Finding is an inconsistency found for a Kyma, or for a runtime not matching any Kyma
+type Finding struct {
+	Problem       Problem `json:"problem"`
+	KymaName      string  `json:"kymaName,omitempty"`
+	GlobalAccount string  `json:"globalAccount,omitempty"`
+	RuntimeID     string  `json:"runtimeID,omitempty"`
+	Director      string  `json:"director,omitempty"`
+	Details       string  `json:"details,omitempty"`
+}
+
+// Report lists inconsistencies found among the checked Kymas, mappings and runtimes, sorted by the Kyma name
+type Report struct {
+	CheckedKymas    int             `json:"checkedKymas"`
+	CheckedMappings int             `json:"checkedMappings"`
+	CheckedRuntimes int             `json:"checkedRuntimes"`
+	Counts          map[Problem]int `json:"counts"`
+	Findings        []Finding       `json:"findings"`
+}
+
+// Checker checks Kymas and mappings in the namespace against Director
+type Checker struct {
+	client    client.Reader
+	directors *director.Registry
+	namespace string
+}
+
+func NewChecker(reader client.Reader, directors *director.Registry, namespace string) *Checker {
+	return &Checker{
+		client:    reader,
+		directors: directors,
+		namespace: namespace,
+	}
+}
+
+// listedRuntime is a runtime registered by compass-manager, listed from a tenant of a Director
+type listedRuntime struct {
+	graphql.RuntimeExt
+	director string
+	tenant   string
+}
+
+// tenant is a tenant of a Director whose runtimes are listed
+type tenant struct {
+	director string
+	name     string
+}
+
+// Check joins Kymas with the application-connector module, mappings, and runtimes registered by compass-manager.
+// Runtimes are listed from tenants of the checked Kymas and mappings only, as Director doesn't list runtimes across tenants.
+func (c *Checker) Check(ctx context.Context) (Report, error) {
+	kymas, err := c.listKymas(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+	mappings := v1beta1.CompassManagerMappingList{}
+	if err := c.client.List(ctx, &mappings, client.InNamespace(c.namespace)); err != nil {
+		return Report{}, errors.Wrap(err, "failed to list Compass Manager Mappings")
+	}
+
+	report := Report{CheckedKymas: len(kymas), CheckedMappings: len(mappings.Items), Counts: map[Problem]int{}, Findings: []Finding{}}
+	runtimes, findings := c.listRuntimes(kymas, mappings.Items)
+	report.CheckedRuntimes = len(runtimes)
+	report.Findings = append(report.Findings, findings...)
+
+	mappingsByKyma := map[string]v1beta1.CompassManagerMapping{}
+	mappingsByRuntime := map[string][]v1beta1.CompassManagerMapping{}
+	for _, mapping := range mappings.Items {
+		mappingsByKyma[mapping.Labels[controllers.LabelKymaName]] = mapping
+		if runtimeID := mapping.Labels[controllers.LabelCompassID]; runtimeID != "" {
+			mappingsByRuntime[runtimeID] = append(mappingsByRuntime[runtimeID], mapping)
+		}
+	}
+
+	for _, kymaCR := range kymas {
+		if _, ok := mappingsByKyma[kymaCR.Name]; !ok {
+			report.Findings = append(report.Findings, Finding{
+				Problem:       MissingMapping,
+				KymaName:      kymaCR.Name,
+				GlobalAccount: kymaCR.Labels[controllers.LabelGlobalAccountID],
+			})
+		}
+	}
+
+	read := c.readRuntimes(mappings.Items, runtimes)
+	for _, mapping := range mappings.Items {
+		report.Findings = append(report.Findings, checkMapping(mapping, kymas, read[mapping.Name], mappingsByRuntime)...)
+	}
+
+	for _, r := range runtimes {
+		if _, ok := mappingsByRuntime[r.ID]; !ok {
+			report.Findings = append(report.Findings, checkUnmappedRuntime(r, kymas, mappingsByKyma))
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.KymaName != b.KymaName {
+			return a.KymaName < b.KymaName
+		}
+		if a.Problem != b.Problem {
+			return a.Problem < b.Problem
+		}
+		return a.RuntimeID < b.RuntimeID
+	})
+	for _, finding := range report.Findings {
+		report.Counts[finding.Problem]++
+	}
+	return report, nil
+}
+
+// listKymas returns Kymas with the application-connector module by name
+func (c *Checker) listKymas(ctx context.Context) (map[string]kyma.Kyma, error) {
+	kymaList := kyma.KymaList{}
+	if err := c.client.List(ctx, &kymaList, client.InNamespace(c.namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list Kymas")
+	}
+
+	kymas := map[string]kyma.Kyma{}
+	for _, kymaCR := range kymaList.Items {
+		if controllers.HasApplicationConnectorModule(kymaCR) {
+			kymas[kymaCR.Name] = kymaCR
+		}
+	}
+	return kymas, nil
+}
+
+// listRuntimes lists runtimes registered by compass-manager in tenants of the Kymas and mappings, by ID
+func (c *Checker) listRuntimes(kymas map[string]kyma.Kyma, mappings []v1beta1.CompassManagerMapping) (map[string]listedRuntime, []Finding) {
+	tenants := map[tenant]bool{}
+	for _, kymaCR := range kymas {
+		globalAccount := kymaCR.Labels[controllers.LabelGlobalAccountID]
+		if globalAccount != "" {
+			tenants[tenant{director: c.directors.SelectDirector(kymaCR.Labels, globalAccount), name: globalAccount}] = true
+		}
+	}
+	for _, mapping := range mappings {
+		if name := controllers.MappingTenant(mapping); name != "" {
+			tenants[tenant{director: mapping.Labels[controllers.LabelCompassDirector], name: name}] = true
+		}
+	}
+
+	runtimes := map[string]listedRuntime{}
+	var findings []Finding
+	for t := range tenants {
+		endpoint, err := c.directors.Get(t.director)
+		if err != nil {
+			findings = append(findings, Finding{Problem: RuntimeUnreadable, GlobalAccount: t.name, Director: t.director, Details: err.Error()})
+			continue
+		}
+
+		listed, appErr := endpoint.Client.ListRuntimes(controllers.RuntimeLabelManagedBy, controllers.ManagedBy, t.name)
+		if appErr != nil {
+			findings = append(findings, Finding{
+				Problem:       RuntimeUnreadable,
+				GlobalAccount: t.name,
+				Director:      t.director,
+				Details:       fmt.Sprintf("failed to list runtimes of tenant %s: %s", t.name, appErr.Error()),
+			})
+			continue
+		}
+		for _, r := range listed {
+			runtimes[r.ID] = listedRuntime{RuntimeExt: r, director: t.director, tenant: t.name}
+		}
+	}
+	return runtimes, findings
+}
+
+func checkMapping(mapping v1beta1.CompassManagerMapping, kymas map[string]kyma.Kyma, read readRuntime, mappingsByRuntime map[string][]v1beta1.CompassManagerMapping) []Finding {
+	finding := Finding{
+		KymaName:      mapping.Labels[controllers.LabelKymaName],
+		GlobalAccount: mapping.Labels[controllers.LabelGlobalAccountID],
+		RuntimeID:     mapping.Labels[controllers.LabelCompassID],
+		Director:      mapping.Labels[controllers.LabelCompassDirector],
+	}
+
+	if finding.RuntimeID == "" {
+		finding.Problem = MappingWithoutRuntime
+		return []Finding{finding}
+	}
+
+	var findings []Finding
+	if sharing := mappingsByRuntime[finding.RuntimeID]; len(sharing) > 1 {
+		duplicate := finding
+		duplicate.Problem = DuplicateRegistration
+		duplicate.Details = fmt.Sprintf("runtime is referred to by %d mappings", len(sharing))
+		findings = append(findings, duplicate)
+	}
+
+	kymaCR, hasKyma := kymas[finding.KymaName]
+	if hasKyma && kymaCR.Labels[controllers.LabelGlobalAccountID] != finding.GlobalAccount {
+		mismatch := finding
+		mismatch.Problem = LabelMismatch
+		mismatch.Details = fmt.Sprintf("mapping has global account %q, Kyma has %q", finding.GlobalAccount, kymaCR.Labels[controllers.LabelGlobalAccountID])
+		findings = append(findings, mismatch)
+	}
+
+	if read.problem != "" {
+		finding.Problem = read.problem
+		finding.Details = read.details
+		return append(findings, finding)
+	}
+
+	if hasKyma {
+		if mismatches := mismatchedLabels(read.labels, kymaCR.Labels); len(mismatches) > 0 {
+			finding.Problem = LabelMismatch
+			finding.Details = fmt.Sprintf("runtime labels differ from the Kyma: %s", strings.Join(mismatches, ", "))
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// readRuntime is the runtime of a mapping, or the problem which prevented reading it
+type readRuntime struct {
+	labels  graphql.Labels
+	problem Problem
+	details string
+}
+
+// readRuntimes returns runtimes of the mappings by the mapping name. Runtimes which aren't among the listed ones are read from Director
+// in batches, as runtimes registered without the managed-by label aren't listed.
+func (c *Checker) readRuntimes(mappings []v1beta1.CompassManagerMapping, runtimes map[string]listedRuntime) map[string]readRuntime {
+	read := map[string]readRuntime{}
+	operations := map[string][]director.BatchOperation{}
+	mappingNames := map[string][]string{}
+	for _, mapping := range mappings {
+		runtimeID := mapping.Labels[controllers.LabelCompassID]
+		if runtimeID == "" {
+			continue
+		}
+		if listed, ok := runtimes[runtimeID]; ok {
+			read[mapping.Name] = readRuntime{labels: listed.Labels}
+			continue
+		}
+
+		directorName := mapping.Labels[controllers.LabelCompassDirector]
+		operations[directorName] = append(operations[directorName], director.GetRuntimeOperation(runtimeID, controllers.MappingTenant(mapping)))
+		mappingNames[directorName] = append(mappingNames[directorName], mapping.Name)
+	}
+
+	for directorName, directorOperations := range operations {
+		endpoint, err := c.directors.Get(directorName)
+		if err != nil {
+			for _, name := range mappingNames[directorName] {
+				read[name] = readRuntime{problem: RuntimeUnreadable, details: err.Error()}
+			}
+			continue
+		}
+
+		for i, result := range director.ExecuteBatch(endpoint.Client, directorOperations) {
+			switch {
+			case result.Err == nil:
+				read[mappingNames[directorName][i]] = readRuntime{labels: result.Runtime.Labels}
+			case isRuntimeNotFound(result.Err):
+				read[mappingNames[directorName][i]] = readRuntime{problem: RuntimeNotFound, details: result.Err.Error()}
+			default:
+				read[mappingNames[directorName][i]] = readRuntime{problem: RuntimeUnreadable, details: result.Err.Error()}
+			}
+		}
+	}
+	return read
+}
+
+// checkUnmappedRuntime reports a runtime no mapping refers to, as a duplicate when it belongs to a Kyma whose mapping refers to another runtime
+func checkUnmappedRuntime(r listedRuntime, kymas map[string]kyma.Kyma, mappingsByKyma map[string]v1beta1.CompassManagerMapping) Finding {
+	finding := Finding{
+		Problem:       OrphanedRuntime,
+		GlobalAccount: r.tenant,
+		RuntimeID:     r.ID,
+		Director:      r.director,
+		Details:       fmt.Sprintf("runtime %s isn't referred to by any mapping", r.Name),
+	}
+
+	kymaCR, ok := matchKyma(r.Labels, kymas)
+	if !ok {
+		return finding
+	}
+	finding.KymaName = kymaCR.Name
+	if mapping, ok := mappingsByKyma[kymaCR.Name]; ok && mapping.Labels[controllers.LabelCompassID] != "" {
+		finding.Problem = DuplicateRegistration
+		finding.Details = fmt.Sprintf("runtime %s is registered for the Kyma mapped to runtime %s", r.Name, mapping.Labels[controllers.LabelCompassID])
+	}
+	return finding
+}
+
+// matchKyma returns the Kyma the runtime with the labels was registered for
+func matchKyma(runtimeLabels graphql.Labels, kymas map[string]kyma.Kyma) (kyma.Kyma, bool) {
+	for _, kymaCR := range kymas {
+		if controllers.RuntimeMatchesKyma(runtimeLabels, kymaCR.Labels) {
+			return kymaCR, true
+		}
+	}
+	return kyma.Kyma{}, false
+}
+
+// mismatchedLabels describes identity labels of the runtime whose values differ from labels of the Kyma, sorted by the label
+func mismatchedLabels(runtimeLabels graphql.Labels, kymaLabels map[string]string) []string {
+	var mismatches []string
+	for runtimeLabel, kymaLabel := range identityLabels {
+		if actual, expected := labelValue(runtimeLabels, runtimeLabel), kymaLabels[kymaLabel]; actual != expected {
+			mismatches = append(mismatches, fmt.Sprintf("%s is %q instead of %q", runtimeLabel, actual, expected))
+		}
+	}
+	sort.Strings(mismatches)
+	return mismatches
+}
+
+func labelValue(labels graphql.Labels, key string) string {
+	value, ok := labels[key]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// isRuntimeNotFound tells if Director doesn't know the runtime, it either reports it's not found or returns no runtime
+func isRuntimeNotFound(err apperrors.AppError) bool {
+	return err.Reason() == apperrors.ErrDirectorNilResponse || err.Reason() == apperrors.ErrReason(directorApperrors.NotFound.String())
+}
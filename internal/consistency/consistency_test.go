@@ -0,0 +1,134 @@
+package consistency
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/kyma-project/compass-manager/api/v1beta1"
+	"github.com/kyma-project/compass-manager/controllers"
+	"github.com/kyma-project/compass-manager/internal/director"
+	"github.com/kyma-project/compass-manager/internal/director/fake"
+	"github.com/kyma-project/compass-manager/internal/graphql"
+	"github.com/kyma-project/compass-manager/internal/oauth"
+	"github.com/kyma-project/compass-manager/pkg/gqlschema"
+	kyma "github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const namespace = "kcp-system"
+
+func TestChecker_Check(t *testing.T) {
+	// given
+	directorServer := fake.NewServer(fake.Config{})
+	defer directorServer.Close()
+
+	oauthClient := oauth.NewOauthClient(http.DefaultClient, fake.DefaultClientID, fake.DefaultClientSecret, directorServer.TokensEndpoint())
+	directorClient := director.NewDirectorClient(graphql.NewGraphQLClient(directorServer.DirectorURL(), false, false), oauthClient)
+	register := func(kymaLabels map[string]string) string {
+		labels := gqlschema.Labels{}
+		for key, value := range controllers.CompassRuntimeLabels(kymaLabels) {
+			labels[key] = value
+		}
+		runtimeID, err := directorClient.CreateRuntime(&gqlschema.RuntimeInput{Name: kymaLabels[controllers.LabelShootName], Labels: labels}, "globalAccount")
+		require.NoError(t, err)
+		return runtimeID
+	}
+
+	registered := newKyma("registered")
+	unmapped := newKyma("unmapped")
+	mismatched := newKyma("mismatched")
+	duplicated := newKyma("duplicated")
+
+	registeredID := register(registered.Labels)
+	mismatchedID := register(map[string]string{controllers.LabelShootName: "other-shoot", controllers.LabelGlobalAccountID: "globalAccount"})
+	duplicatedID := register(duplicated.Labels)
+	duplicateID := register(duplicated.Labels)
+	orphanID := register(map[string]string{controllers.LabelShootName: "deleted-shoot", controllers.LabelGlobalAccountID: "globalAccount"})
+
+	unknownDirector := newMapping("unknown-director", "globalAccount", "runtime-id")
+	unknownDirector.Labels[controllers.LabelCompassDirector] = "unknown"
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	require.NoError(t, kyma.AddToScheme(scheme))
+	kubectl := ctrlfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		registered, unmapped, mismatched, duplicated,
+		newMapping("registered", "globalAccount", registeredID),
+		newMapping("mismatched", "globalAccount", mismatchedID),
+		newMapping("duplicated", "globalAccount", duplicatedID),
+		newMapping("not-registered", "globalAccount", ""),
+		newMapping("deleted", "globalAccount", "deleted-runtime-id"),
+		unknownDirector,
+	).Build()
+
+	checker := NewChecker(kubectl, director.NewSingleDirectorRegistry(directorClient, fake.ConnectorPath), namespace)
+
+	// when
+	report, err := checker.Check(context.Background())
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.CheckedKymas)
+	assert.Equal(t, 6, report.CheckedMappings)
+	assert.Equal(t, 5, report.CheckedRuntimes)
+
+	type problemOf struct {
+		kymaName  string
+		problem   Problem
+		runtimeID string
+	}
+	var problems []problemOf
+	for _, finding := range report.Findings {
+		problems = append(problems, problemOf{finding.KymaName, finding.Problem, finding.RuntimeID})
+	}
+	assert.Equal(t, []problemOf{
+		{"", OrphanedRuntime, orphanID},
+		{"", RuntimeUnreadable, ""},
+		{"deleted", RuntimeNotFound, "deleted-runtime-id"},
+		{"duplicated", DuplicateRegistration, duplicateID},
+		{"mismatched", LabelMismatch, mismatchedID},
+		{"not-registered", MappingWithoutRuntime, ""},
+		{"unknown-director", RuntimeUnreadable, "runtime-id"},
+		{"unmapped", MissingMapping, ""},
+	}, problems)
+	assert.Equal(t, 2, report.Counts[RuntimeUnreadable])
+	assert.Contains(t, report.Findings[4].Details, controllers.RuntimeLabelShootName)
+}
+
+func newKyma(name string) *kyma.Kyma {
+	return &kyma.Kyma{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				controllers.LabelKymaName:         name,
+				controllers.LabelGlobalAccountID:  "globalAccount",
+				controllers.LabelShootName:        name + "-shoot",
+				controllers.LabelBrokerInstanceID: name + "-instance",
+			},
+		},
+		Status: kyma.KymaStatus{Modules: []kyma.ModuleStatus{{Name: controllers.ApplicationConnectorModuleName}}},
+	}
+}
+
+func newMapping(kymaName, globalAccount, compassID string) *v1beta1.CompassManagerMapping {
+	labels := map[string]string{
+		controllers.LabelKymaName:        kymaName,
+		controllers.LabelGlobalAccountID: globalAccount,
+	}
+	if compassID != "" {
+		labels[controllers.LabelCompassID] = compassID
+	}
+	return &v1beta1.CompassManagerMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kymaName,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+	}
+}
@@ -0,0 +1,387 @@
+package director
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/kyma-incubator/compass/components/director/pkg/graphql"
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/kyma-project/compass-manager/pkg/gqlschema"
+	gcli "github.com/kyma-project/compass-manager/third_party/machinebox/graphql"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultBatchSize is the number of operations sent in a single request, unless set with WithBatchSize
+const DefaultBatchSize = 50
+
+var aliasPattern = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`) //nolint:gochecknoglobals
+
+type batchOperationType int
+
+const (
+	createRuntimeOperation batchOperationType = iota
+	getRuntimeOperation
+	deleteRuntimeOperation
+	getConnectionTokenOperation
+	getRuntimeFormationsOperation
+	getRuntimeApplicationsOperation
+)
+
+// BatchOperation is a Director operation sent together with others by ExecuteBatch.
+// Operations of different Global Accounts, as well as queries and mutations, are sent in separate requests.
+type BatchOperation struct {
+	// Alias names the operation in the request and its result, operations without an alias are named by their position, e.g. op0
+	Alias         string
+	GlobalAccount string
+
+	operationType batchOperationType
+	compassID     string
+	config        *gqlschema.RuntimeInput
+}
+
+// CreateRuntimeOperation registers the runtime like CreateRuntime
+func CreateRuntimeOperation(config *gqlschema.RuntimeInput, globalAccount string) BatchOperation {
+	return BatchOperation{GlobalAccount: globalAccount, operationType: createRuntimeOperation, config: config}
+}
+
+// GetRuntimeOperation gets the runtime like GetRuntime
+func GetRuntimeOperation(compassID, globalAccount string) BatchOperation {
+	return BatchOperation{GlobalAccount: globalAccount, operationType: getRuntimeOperation, compassID: compassID}
+}
+
+// DeleteRuntimeOperation unregisters the runtime like DeleteRuntime, a runtime which doesn't exist is not an error
+func DeleteRuntimeOperation(compassID, globalAccount string) BatchOperation {
+	return BatchOperation{GlobalAccount: globalAccount, operationType: deleteRuntimeOperation, compassID: compassID}
+}
+
+// GetConnectionTokenOperation requests a one-time token like GetConnectionToken
+func GetConnectionTokenOperation(compassID, globalAccount string) BatchOperation {
+	return BatchOperation{GlobalAccount: globalAccount, operationType: getConnectionTokenOperation, compassID: compassID}
+}
+
+// GetRuntimeFormationsOperation gets formations of the runtime like GetRuntimeFormations
+func GetRuntimeFormationsOperation(compassID, globalAccount string) BatchOperation {
+	return BatchOperation{GlobalAccount: globalAccount, operationType: getRuntimeFormationsOperation, compassID: compassID}
+}
+
+// GetRuntimeApplicationsOperation gets applications of the runtime like GetRuntimeApplications
+func GetRuntimeApplicationsOperation(compassID, globalAccount string) BatchOperation {
+	return BatchOperation{GlobalAccount: globalAccount, operationType: getRuntimeApplicationsOperation, compassID: compassID}
+}
+
+// BatchResult is the result of the operation at the same position passed to ExecuteBatch
+type BatchResult struct {
+	Alias string
+	// RuntimeID is set by CreateRuntimeOperation
+	RuntimeID string
+	// Runtime is set by GetRuntimeOperation
+	Runtime graphql.RuntimeExt
+	// Token is set by GetConnectionTokenOperation
+	Token graphql.OneTimeTokenForRuntimeExt
+	// Formations are set by GetRuntimeFormationsOperation
+	Formations []graphql.Formation
+	// Applications are set by GetRuntimeApplicationsOperation
+	Applications graphql.ApplicationPage
+	Err          apperrors.AppError
+}
+
+// BatchClient sends many operations in as few requests as possible, returning their results in the order of operations
+type BatchClient interface {
+	ExecuteBatch(operations []BatchOperation) []BatchResult
+}
+
+// ExecuteBatch executes the operations in batches if the client supports it, and one by one otherwise, e.g. with mocks
+func ExecuteBatch(client Client, operations []BatchOperation) []BatchResult {
+	if batchClient, ok := client.(BatchClient); ok {
+		return batchClient.ExecuteBatch(operations)
+	}
+
+	results := make([]BatchResult, len(operations))
+	for i, operation := range operations {
+		results[i].Alias = operation.Alias
+		if results[i].Alias == "" {
+			results[i].Alias = fmt.Sprintf("op%d", i)
+		}
+
+		switch operation.operationType {
+		case createRuntimeOperation:
+			results[i].RuntimeID, results[i].Err = client.CreateRuntime(operation.config, operation.GlobalAccount)
+		case getRuntimeOperation:
+			results[i].Runtime, results[i].Err = client.GetRuntime(operation.compassID, operation.GlobalAccount)
+		case deleteRuntimeOperation:
+			results[i].Err = client.DeleteRuntime(operation.compassID, operation.GlobalAccount)
+		case getConnectionTokenOperation:
+			results[i].Token, results[i].Err = client.GetConnectionToken(operation.compassID, operation.GlobalAccount)
+		case getRuntimeFormationsOperation:
+			results[i].Formations, results[i].Err = client.GetRuntimeFormations(operation.compassID, operation.GlobalAccount)
+		case getRuntimeApplicationsOperation:
+			results[i].Applications, results[i].Err = client.GetRuntimeApplications(operation.compassID, operation.GlobalAccount)
+		}
+	}
+	return results
+}
+
+type batchKey struct {
+	globalAccount string
+	mutation      bool
+}
+
+// batchRequest is a single request to Director, with the positions of its operations
+type batchRequest struct {
+	batchKey
+	fields     []string
+	operations []int
+}
+
+// ExecuteBatch sends operations of the same Global Account and type as aliased fields of one document, up to the batch size per request
+func (cc *directorClient) ExecuteBatch(operations []BatchOperation) []BatchResult {
+	results := make([]BatchResult, len(operations))
+
+	var requests []*batchRequest
+	pending := map[batchKey]*batchRequest{}
+	aliases := map[string]bool{}
+	for i, operation := range operations {
+		alias := operation.Alias
+		if alias == "" {
+			alias = fmt.Sprintf("op%d", i)
+		}
+		results[i].Alias = alias
+
+		if !aliasPattern.MatchString(alias) || aliases[alias] {
+			results[i].Err = apperrors.BadRequest(fmt.Sprintf("Cannot execute Director operation: alias %q is invalid or not unique in the batch", alias))
+			continue
+		}
+		aliases[alias] = true
+
+		field, err := cc.batchField(alias, operation)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		key := batchKey{globalAccount: operation.GlobalAccount, mutation: operation.mutation()}
+		request := pending[key]
+		if request == nil || len(request.operations) >= cc.batchSize {
+			request = &batchRequest{batchKey: key}
+			pending[key] = request
+			requests = append(requests, request)
+		}
+		request.fields = append(request.fields, field)
+		request.operations = append(request.operations, i)
+	}
+
+	for _, request := range requests {
+		cc.executeBatchRequest(request, operations, results)
+	}
+
+	log.Infof("Executed %d Director operations in %d requests", len(operations), len(requests))
+	return results
+}
+
+func (cc *directorClient) batchField(alias string, operation BatchOperation) (string, apperrors.AppError) {
+	switch operation.operationType {
+	case createRuntimeOperation:
+		if operation.config == nil {
+			return "", apperrors.BadRequest("Cannot register runtime in Director: missing Runtime config")
+		}
+		runtimeInput, err := cc.graphqlizer.RuntimeRegisterInputToGQL(graphql.RuntimeRegisterInput{
+			Name:        operation.config.Name,
+			Description: operation.config.Description,
+			Labels:      graphql.Labels(operation.config.Labels),
+		})
+		if err != nil {
+			return "", apperrors.Internalf("Failed to create graphQLized Runtime input: %s", err.Error()).SetComponent(apperrors.ErrCompassDirectorClient).SetReason(apperrors.ErrDirectorClientGraphqlizer)
+		}
+		return cc.queryProvider.registerRuntimeField(alias, runtimeInput), nil
+	case getRuntimeOperation:
+		return cc.queryProvider.runtimeField(alias, operation.compassID), nil
+	case deleteRuntimeOperation:
+		return cc.queryProvider.unregisterRuntimeField(alias, operation.compassID), nil
+	case getRuntimeFormationsOperation:
+		return cc.queryProvider.runtimeFormationsField(alias, operation.compassID), nil
+	case getRuntimeApplicationsOperation:
+		return cc.queryProvider.runtimeApplicationsField(alias, operation.compassID, RuntimeApplicationsLimit), nil
+	default:
+		return cc.queryProvider.requestOneTimeTokenField(alias, operation.compassID), nil
+	}
+}
+
+// executeBatchRequest sets the results of the request's operations, errors located at a field fail only the operation with its alias
+func (cc *directorClient) executeBatchRequest(request *batchRequest, operations []BatchOperation, results []BatchResult) {
+	operationType := "query"
+	if request.mutation {
+		operationType = "mutation"
+	}
+	document := cc.queryProvider.batchDocument(operationType, request.fields)
+
+	var response map[string]json.RawMessage
+	var fieldErrors []gcli.FieldError
+	err := cc.guardDirectorCall(document, request.globalAccount, func() apperrors.AppError {
+		var err apperrors.AppError
+		fieldErrors, err = cc.doPartialDirectorGraphQLCall(document, request.globalAccount, &response)
+		return err
+	})
+	if err != nil {
+		for _, i := range request.operations {
+			results[i].Err = err.Append("Failed to execute batch of Director operations")
+		}
+		return
+	}
+
+	// errors without a path, e.g. invalid documents, fail all operations of the request
+	var requestErr gcli.FieldError
+	fieldErrorsByAlias := map[string]gcli.FieldError{}
+	for _, fieldErr := range fieldErrors {
+		alias, ok := fieldAlias(fieldErr)
+		if !ok {
+			if requestErr == nil {
+				requestErr = fieldErr
+			}
+			continue
+		}
+		if _, exists := fieldErrorsByAlias[alias]; !exists {
+			fieldErrorsByAlias[alias] = fieldErr
+		}
+	}
+
+	for _, i := range request.operations {
+		fieldErr, failed := fieldErrorsByAlias[results[i].Alias]
+		if !failed && requestErr != nil {
+			fieldErr, failed = requestErr, true
+		}
+		if failed {
+			results[i].Err = operations[i].failed(fieldErr)
+		} else {
+			results[i].Err = operations[i].decode(response[results[i].Alias], &results[i])
+		}
+
+		cc.observer.error(operations[i].operationName(), results[i].Err)
+		if results[i].Err == nil && operations[i].operationType == getConnectionTokenOperation {
+			cc.observer.oneTimeToken()
+		}
+	}
+}
+
+func (cc *directorClient) doPartialDirectorGraphQLCall(directorQuery string, globalAccount string, response interface{}) ([]gcli.FieldError, apperrors.AppError) {
+	req, appErr := cc.newDirectorRequest(directorQuery, globalAccount)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	fieldErrors, err := cc.gqlClient.DoPartial(cc.requestContext(), req, response)
+	if err != nil {
+		return nil, apperrors.Internalf("Failed to execute GraphQL request to Director: %v", err)
+	}
+	return fieldErrors, nil
+}
+
+func fieldAlias(fieldErr gcli.FieldError) (string, bool) {
+	path := fieldErr.Path()
+	if len(path) == 0 {
+		return "", false
+	}
+	alias, ok := path[0].(string)
+	return alias, ok
+}
+
+func (o BatchOperation) failed(fieldErr gcli.FieldError) apperrors.AppError {
+	graceful := o.operationType == deleteRuntimeOperation
+	err := mapDirectorErrorToProvisionerError(fieldErr, graceful)
+	if graceful && err.Cause() == apperrors.RuntimeNotFound {
+		log.Infof("Runtime %s in Director for tenant %s was previously deleted", o.compassID, o.GlobalAccount)
+		return nil
+	}
+	return err.Append("Failed to %s", o.description())
+}
+
+func (o BatchOperation) decode(data json.RawMessage, result *BatchResult) apperrors.AppError {
+	// Nil check is necessary due to GraphQL client not checking response code
+	if len(data) == 0 || string(data) == "null" {
+		return apperrors.Internalf("Failed to %s: received nil response.", o.description()).SetComponent(apperrors.ErrCompassDirector).SetReason(apperrors.ErrDirectorNilResponse)
+	}
+
+	var target interface{}
+	var runtime graphql.Runtime
+	switch o.operationType {
+	case getRuntimeOperation:
+		target = &result.Runtime
+	case getConnectionTokenOperation:
+		target = &result.Token
+	case getRuntimeFormationsOperation:
+		target = &result.Formations
+	case getRuntimeApplicationsOperation:
+		target = &result.Applications
+	default:
+		target = &runtime
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return apperrors.Internalf("Failed to %s: failed to decode response: %s", o.description(), err.Error()).SetComponent(apperrors.ErrCompassDirector)
+	}
+
+	switch o.operationType {
+	case createRuntimeOperation:
+		if _, err := uuid.Parse(runtime.ID); err != nil {
+			return apperrors.Internalf("Failed to %s: received ID is not in UUID format", o.description()).SetComponent(apperrors.ErrCompassDirector).SetReason(apperrors.ErrDirectorRuntimeIDInvalidFormat)
+		}
+		result.RuntimeID = runtime.ID
+	case getRuntimeOperation:
+		if result.Runtime.ID != o.compassID {
+			return o.runtimeIDMismatch()
+		}
+	case deleteRuntimeOperation:
+		if runtime.ID != o.compassID {
+			return o.runtimeIDMismatch()
+		}
+	}
+	return nil
+}
+
+func (o BatchOperation) mutation() bool {
+	switch o.operationType {
+	case createRuntimeOperation, deleteRuntimeOperation, getConnectionTokenOperation:
+		return true
+	default:
+		return false
+	}
+}
+
+// operationName is the Director field called by the operation
+func (o BatchOperation) operationName() string {
+	switch o.operationType {
+	case createRuntimeOperation:
+		return "registerRuntime"
+	case getRuntimeOperation:
+		return "runtime"
+	case deleteRuntimeOperation:
+		return "unregisterRuntime"
+	case getRuntimeFormationsOperation:
+		return "formationsForObject"
+	case getRuntimeApplicationsOperation:
+		return "applicationsForRuntime"
+	default:
+		return "requestOneTimeTokenForRuntime"
+	}
+}
+
+func (o BatchOperation) runtimeIDMismatch() apperrors.AppError {
+	return apperrors.Internalf("Failed to %s: received unexpected RuntimeID", o.description()).SetComponent(apperrors.ErrCompassDirector).SetReason(apperrors.ErrDirectorRuntimeIDMismatch)
+}
+
+func (o BatchOperation) description() string {
+	switch o.operationType {
+	case createRuntimeOperation:
+		return "register runtime in Director"
+	case getRuntimeOperation:
+		return fmt.Sprintf("get runtime %s from Director", o.compassID)
+	case deleteRuntimeOperation:
+		return fmt.Sprintf("unregister runtime %s in Director", o.compassID)
+	case getRuntimeFormationsOperation:
+		return fmt.Sprintf("get formations of runtime %s from Director", o.compassID)
+	case getRuntimeApplicationsOperation:
+		return fmt.Sprintf("get applications of runtime %s from Director", o.compassID)
+	default:
+		return fmt.Sprintf("get OneTimeToken for Runtime %s in Director", o.compassID)
+	}
+}
@@ -0,0 +1,201 @@
+package director
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	directorApperrors "github.com/kyma-incubator/compass/components/director/pkg/apperrors"
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/kyma-project/compass-manager/internal/director/fake"
+	gql "github.com/kyma-project/compass-manager/internal/graphql"
+	"github.com/kyma-project/compass-manager/internal/oauth"
+	"github.com/kyma-project/compass-manager/pkg/gqlschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const otherGlobalAccount = "f9ab1c0a-0a5b-4b64-8d6c-7b0a1a3c2b6e"
+
+func TestDirectorClient_ExecuteBatch(t *testing.T) {
+	t.Run("should return results of operations in their order", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		client := newFakeDirectorClient(server)
+
+		first, err := client.CreateRuntime(&gqlschema.RuntimeInput{Name: "first"}, globalAccountValue)
+		require.NoError(t, err)
+
+		createOperation := CreateRuntimeOperation(&gqlschema.RuntimeInput{Name: "second"}, globalAccountValue)
+		createOperation.Alias = "second"
+
+		// when
+		results := client.ExecuteBatch([]BatchOperation{
+			GetRuntimeOperation(first, globalAccountValue),
+			createOperation,
+			GetConnectionTokenOperation(first, globalAccountValue),
+		})
+
+		// then
+		require.Len(t, results, 3)
+		for _, result := range results {
+			require.NoError(t, result.Err)
+		}
+		assert.Equal(t, "op0", results[0].Alias)
+		assert.Equal(t, "first", results[0].Runtime.Name)
+		assert.Equal(t, "second", results[1].Alias)
+		_, registered := server.Director.Runtime(globalAccountValue, results[1].RuntimeID)
+		assert.True(t, registered)
+		assert.Equal(t, []string{results[2].Token.Token}, server.Director.OneTimeTokens(first))
+	})
+
+	t.Run("should read formations and applications of runtimes in one request", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		var requests []string
+		client := newFakeDirectorClient(server, WithObserver(Observer{OnRequest: func(operation string, _ time.Duration) {
+			requests = append(requests, operation)
+		}}))
+
+		assigned, err := client.CreateRuntime(&gqlschema.RuntimeInput{Name: "assigned"}, globalAccountValue)
+		require.NoError(t, err)
+		unassigned, err := client.CreateRuntime(&gqlschema.RuntimeInput{Name: "unassigned"}, globalAccountValue)
+		require.NoError(t, err)
+		server.Director.AddFormation(globalAccountValue, "formation")
+		server.Director.AddApplication(globalAccountValue, "application", "formation")
+		require.NoError(t, client.AssignFormation(assigned, "formation", globalAccountValue))
+		requests = nil
+
+		// when
+		results := client.ExecuteBatch([]BatchOperation{
+			GetRuntimeFormationsOperation(assigned, globalAccountValue),
+			GetRuntimeApplicationsOperation(assigned, globalAccountValue),
+			GetRuntimeFormationsOperation(unassigned, globalAccountValue),
+			GetRuntimeApplicationsOperation(unassigned, globalAccountValue),
+		})
+
+		// then
+		require.Len(t, results, 4)
+		for _, result := range results {
+			require.NoError(t, result.Err)
+		}
+		require.Len(t, results[0].Formations, 1)
+		assert.Equal(t, "formation", results[0].Formations[0].Name)
+		assert.Equal(t, 1, results[1].Applications.TotalCount)
+		require.Len(t, results[1].Applications.Data, 1)
+		assert.Equal(t, "application", results[1].Applications.Data[0].Name)
+		assert.Empty(t, results[2].Formations)
+		assert.Zero(t, results[3].Applications.TotalCount)
+		assert.Equal(t, []string{OperationBatch}, requests)
+	})
+
+	t.Run("should fail only operations with field errors", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		client := newFakeDirectorClient(server)
+
+		existing, err := client.CreateRuntime(&gqlschema.RuntimeInput{Name: "existing"}, globalAccountValue)
+		require.NoError(t, err)
+		server.Director.FailNext("requestOneTimeTokenForRuntime", fake.Failure{ErrorType: directorApperrors.InvalidOperation})
+
+		// when
+		results := client.ExecuteBatch([]BatchOperation{
+			GetConnectionTokenOperation(existing, globalAccountValue),
+			DeleteRuntimeOperation(compassTestingID, globalAccountValue),
+			DeleteRuntimeOperation(existing, globalAccountValue),
+			GetRuntimeOperation(compassTestingID, globalAccountValue),
+			CreateRuntimeOperation(nil, globalAccountValue),
+		})
+
+		// then
+		require.Error(t, results[0].Err)
+		assert.Equal(t, apperrors.CodeBadRequest, results[0].Err.Code())
+		assert.NoError(t, results[1].Err, "runtime which doesn't exist should be deleted gracefully")
+		assert.NoError(t, results[2].Err)
+		require.Error(t, results[3].Err)
+		assert.Equal(t, apperrors.CodeBadRequest, results[3].Err.Code())
+		require.Error(t, results[4].Err)
+		assert.Empty(t, server.Director.Runtimes(globalAccountValue))
+	})
+
+	t.Run("should send Global Accounts and batches over the batch size in separate requests", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		client := newFakeDirectorClient(server, WithBatchSize(2))
+
+		var operations []BatchOperation
+		for _, globalAccount := range []string{globalAccountValue, otherGlobalAccount, globalAccountValue, globalAccountValue} {
+			operations = append(operations, CreateRuntimeOperation(&gqlschema.RuntimeInput{Name: "runtime"}, globalAccount))
+		}
+		server.Director.FailNext("registerRuntime", fake.Failure{StatusCode: http.StatusBadGateway})
+
+		// when
+		results := client.ExecuteBatch(operations)
+
+		// then
+		require.Error(t, results[0].Err, "whole request should fail")
+		require.Error(t, results[2].Err, "whole request should fail")
+		require.NoError(t, results[1].Err)
+		require.NoError(t, results[3].Err)
+		assert.Len(t, server.Director.Runtimes(otherGlobalAccount), 1)
+		assert.Len(t, server.Director.Runtimes(globalAccountValue), 1)
+	})
+
+	t.Run("should reject invalid and duplicated aliases", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		client := newFakeDirectorClient(server)
+
+		invalid := GetRuntimeOperation(compassTestingID, globalAccountValue)
+		invalid.Alias = "my-runtime"
+		duplicated := GetRuntimeOperation(compassTestingID, globalAccountValue)
+		duplicated.Alias = "op0"
+
+		// when
+		results := client.ExecuteBatch([]BatchOperation{
+			DeleteRuntimeOperation(compassTestingID, globalAccountValue),
+			invalid,
+			duplicated,
+		})
+
+		// then
+		assert.NoError(t, results[0].Err)
+		for _, result := range results[1:] {
+			require.Error(t, result.Err)
+			assert.Equal(t, apperrors.CodeBadRequest, result.Err.Code())
+		}
+		assert.Zero(t, server.Director.Calls("runtime"))
+	})
+
+	t.Run("should execute operations one by one if client doesn't support batches", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		client := struct{ Client }{newFakeDirectorClient(server)}
+
+		// when
+		results := ExecuteBatch(client, []BatchOperation{
+			CreateRuntimeOperation(&gqlschema.RuntimeInput{Name: "runtime"}, globalAccountValue),
+			DeleteRuntimeOperation(compassTestingID, globalAccountValue),
+		})
+
+		// then
+		require.Len(t, results, 2)
+		require.NoError(t, results[0].Err)
+		require.NoError(t, results[1].Err)
+		assert.NotEmpty(t, results[0].RuntimeID)
+		assert.Equal(t, "op1", results[1].Alias)
+	})
+}
+
+func newFakeDirectorClient(server *fake.Server, opts ...Option) *directorClient {
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	oauthClient := oauth.NewOauthClient(httpClient, fake.DefaultClientID, fake.DefaultClientSecret, server.TokensEndpoint())
+	gqlClient := gql.NewGraphQLClient(server.DirectorURL(), false, false)
+	return NewDirectorClient(gqlClient, oauthClient, opts...).(*directorClient)
+}
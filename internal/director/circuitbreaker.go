@@ -0,0 +1,146 @@
+package director
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// CircuitBreaker short-circuits calls to Director after a number of consecutive failures.
+// Once the open timeout elapses the breaker half-opens and lets a single probe call through;
+// the probe result decides whether the breaker closes again or stays open for another timeout.
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	state            BreakerState
+	failures         int
+	failureThreshold int
+	openTimeout      time.Duration
+	nextProbe        time.Time
+	probing          bool
+	timer            *time.Timer
+	onStateChange    func(BreakerState)
+}
+
+// NewCircuitBreaker creates a closed breaker. The onStateChange callback is optional and
+// is called with every state the breaker enters, including the initial one.
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration, onStateChange func(BreakerState)) *CircuitBreaker {
+	b := &CircuitBreaker{
+		state:            BreakerClosed,
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		onStateChange:    onStateChange,
+	}
+	b.notify()
+	return b
+}
+
+// Allow reports whether a call to Director may be executed
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerClosed:
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+	if b.state != BreakerClosed {
+		log.Infof("Director circuit breaker closed")
+		b.setState(BreakerClosed)
+	}
+}
+
+func (b *CircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == BreakerHalfOpen || (b.state == BreakerClosed && b.failures >= b.failureThreshold) {
+		b.open()
+	}
+}
+
+// RecordSkipped records that the allowed call wasn't sent, so that a half-open breaker lets another probe through
+func (b *CircuitBreaker) RecordSkipped() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// NextProbe returns the time at which the breaker lets the next call through.
+// The zero time is returned when the breaker is closed.
+func (b *CircuitBreaker) NextProbe() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerClosed {
+		return time.Time{}
+	}
+	return b.nextProbe
+}
+
+func (b *CircuitBreaker) open() {
+	log.Warnf("Director circuit breaker opened after %d consecutive failures, next probe in %s", b.failures, b.openTimeout)
+
+	b.nextProbe = time.Now().Add(b.openTimeout)
+	b.setState(BreakerOpen)
+
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	b.timer = time.AfterFunc(b.openTimeout, b.halfOpen)
+}
+
+func (b *CircuitBreaker) halfOpen() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen {
+		log.Infof("Director circuit breaker half-opened, probing Director")
+		b.setState(BreakerHalfOpen)
+	}
+}
+
+func (b *CircuitBreaker) setState(state BreakerState) {
+	b.state = state
+	b.notify()
+}
+
+func (b *CircuitBreaker) notify() {
+	if b.onStateChange != nil {
+		b.onStateChange(b.state)
+	}
+}
@@ -0,0 +1,186 @@
+package director
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	directorApperrors "github.com/kyma-incubator/compass/components/director/pkg/apperrors"
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	gql "github.com/kyma-project/compass-manager/internal/graphql"
+	"github.com/kyma-project/compass-manager/internal/oauth"
+	oauthmocks "github.com/kyma-project/compass-manager/internal/oauth/mocks"
+	"github.com/kyma-project/compass-manager/internal/util"
+	gcli "github.com/kyma-project/compass-manager/third_party/machinebox/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOpenTimeout = 50 * time.Millisecond
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Run("should open after consecutive failures", func(t *testing.T) {
+		// given
+		var states []BreakerState
+		breaker := NewCircuitBreaker(2, time.Minute, func(s BreakerState) { states = append(states, s) })
+
+		// when
+		breaker.RecordFailure()
+		allowedAfterFirstFailure := breaker.Allow()
+		breaker.RecordFailure()
+
+		// then
+		assert.True(t, allowedAfterFirstFailure)
+		assert.False(t, breaker.Allow())
+		assert.Equal(t, BreakerOpen, breaker.State())
+		assert.WithinDuration(t, time.Now().Add(time.Minute), breaker.NextProbe(), time.Second)
+		assert.Equal(t, []BreakerState{BreakerClosed, BreakerOpen}, states)
+	})
+
+	t.Run("should reset failure count after success", func(t *testing.T) {
+		// given
+		breaker := NewCircuitBreaker(2, time.Minute, nil)
+
+		// when
+		breaker.RecordFailure()
+		breaker.RecordSuccess()
+		breaker.RecordFailure()
+
+		// then
+		assert.Equal(t, BreakerClosed, breaker.State())
+		assert.True(t, breaker.NextProbe().IsZero())
+	})
+
+	t.Run("should let a single probe through when half-open and close on success", func(t *testing.T) {
+		// given
+		breaker := NewCircuitBreaker(1, testOpenTimeout, nil)
+		breaker.RecordFailure()
+
+		// when
+		require.Eventually(t, func() bool { return breaker.State() == BreakerHalfOpen }, time.Second, testOpenTimeout/5)
+
+		// then
+		assert.True(t, breaker.Allow())
+		assert.False(t, breaker.Allow())
+
+		breaker.RecordSuccess()
+		assert.Equal(t, BreakerClosed, breaker.State())
+		assert.True(t, breaker.Allow())
+	})
+
+	t.Run("should let another probe through when the probe wasn't sent", func(t *testing.T) {
+		// given
+		breaker := NewCircuitBreaker(1, testOpenTimeout, nil)
+		breaker.RecordFailure()
+		require.Eventually(t, func() bool { return breaker.State() == BreakerHalfOpen }, time.Second, testOpenTimeout/5)
+		require.True(t, breaker.Allow())
+
+		// when
+		breaker.RecordSkipped()
+
+		// then
+		assert.True(t, breaker.Allow())
+		assert.Equal(t, BreakerHalfOpen, breaker.State())
+	})
+
+	t.Run("should open again when probe fails", func(t *testing.T) {
+		// given
+		breaker := NewCircuitBreaker(1, testOpenTimeout, nil)
+		breaker.RecordFailure()
+		require.Eventually(t, func() bool { return breaker.State() == BreakerHalfOpen }, time.Second, testOpenTimeout/5)
+
+		// when
+		require.True(t, breaker.Allow())
+		breaker.RecordFailure()
+
+		// then
+		assert.Equal(t, BreakerOpen, breaker.State())
+		assert.False(t, breaker.Allow())
+	})
+}
+
+func TestDirectorClient_CircuitBreaker(t *testing.T) {
+	token := oauth.Token{
+		AccessToken: validTokenValue,
+		Expiration:  futureExpirationTime,
+	}
+
+	t.Run("should short-circuit calls after Director fails", func(t *testing.T) {
+		// given
+		gqlClient := gql.NewQueryAssertClient(t, errors.New("connection refused"), []*gcli.Request{newExpectedRequest(expectedOneTimeTokenQuery)})
+
+		mockedOAuthClient := &oauthmocks.Client{}
+		mockedOAuthClient.On("GetAuthorizationToken").Return(token, nil)
+
+		breaker := NewCircuitBreaker(1, time.Minute, nil)
+		configClient := NewDirectorClient(gqlClient, mockedOAuthClient, WithCircuitBreaker(breaker))
+
+		// when
+		_, firstErr := configClient.GetConnectionToken(compassTestingID, globalAccountValue)
+		_, secondErr := configClient.GetConnectionToken(compassTestingID, globalAccountValue)
+
+		// then
+		require.Error(t, firstErr)
+		util.CheckErrorType(t, firstErr, apperrors.CodeInternal)
+
+		require.Error(t, secondErr)
+		util.CheckErrorType(t, secondErr, apperrors.CodeUnavailable)
+		assert.Equal(t, apperrors.ErrDirectorCircuitOpen, secondErr.Reason())
+		assert.Equal(t, apperrors.ErrCompassDirectorClient, secondErr.Component())
+	})
+
+	t.Run("should short-circuit calls without waiting for the throttle", func(t *testing.T) {
+		// given
+		gqlClient := gql.NewQueryAssertClient(t, nil, []*gcli.Request{})
+
+		mockedOAuthClient := &oauthmocks.Client{}
+		mockedOAuthClient.On("GetAuthorizationToken").Return(token, nil)
+
+		breaker := NewCircuitBreaker(1, time.Minute, nil)
+		breaker.RecordFailure()
+		throttle := NewThrottle(ThrottleConfig{MaxConcurrentMutations: 1}, nil)
+		release, throttleErr := throttle.Acquire(globalAccountValue, true)
+		require.NoError(t, throttleErr)
+		defer release()
+		configClient := NewDirectorClient(gqlClient, mockedOAuthClient, WithCircuitBreaker(breaker), WithThrottle(throttle))
+
+		// when
+		start := time.Now()
+		_, err := configClient.GetConnectionToken(compassTestingID, globalAccountValue)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, apperrors.ErrDirectorCircuitOpen, err.Reason())
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("should not count request errors as Director failures", func(t *testing.T) {
+		// given
+		gqlClient := gql.NewQueryAssertClient(t, testGraphQLError{
+			Message: "not found",
+			ErrorExtensions: map[string]interface{}{
+				"error_code": float64(directorApperrors.NotFound),
+			},
+		}, []*gcli.Request{newExpectedRequest(expectedOneTimeTokenQuery)})
+
+		mockedOAuthClient := &oauthmocks.Client{}
+		mockedOAuthClient.On("GetAuthorizationToken").Return(token, nil)
+
+		breaker := NewCircuitBreaker(1, time.Minute, nil)
+		configClient := NewDirectorClient(gqlClient, mockedOAuthClient, WithCircuitBreaker(breaker))
+
+		// when
+		_, err := configClient.GetConnectionToken(compassTestingID, globalAccountValue)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, BreakerClosed, breaker.State())
+	})
+}
+
+func newExpectedRequest(query string) *gcli.Request {
+	req := gcli.NewRequest(query)
+	req.Header.Set(AuthorizationHeader, "Bearer "+validTokenValue)
+	req.Header.Set(TenantHeader, globalAccountValue)
+	return req
+}
@@ -1,8 +1,10 @@
 package director
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	directorApperrors "github.com/kyma-incubator/compass/components/director/pkg/apperrors"
@@ -19,6 +21,12 @@ import (
 const (
 	AuthorizationHeader = "Authorization"
 	TenantHeader        = "Tenant"
+	// RuntimeContextSubaccountKey is the key of runtime contexts created for subaccounts
+	RuntimeContextSubaccountKey = "global_subaccount_id"
+	// RuntimeApplicationsLimit is the number of applications returned by GetRuntimeApplications
+	RuntimeApplicationsLimit = 200
+	// RuntimesPageSize is the number of runtimes read in one request by ListRuntimes
+	RuntimesPageSize = 100
 )
 
 //go:generate mockery --name=Client
@@ -27,24 +35,79 @@ type Client interface {
 	GetRuntime(compassID, globalAccount string) (graphql.RuntimeExt, apperrors.AppError)
 	GetConnectionToken(compassID, globalAccount string) (graphql.OneTimeTokenForRuntimeExt, apperrors.AppError)
 	DeleteRuntime(compassID, globalAccount string) apperrors.AppError
+	CreateRuntimeContext(compassID, subaccount, globalAccount string) (string, apperrors.AppError)
+	DeleteRuntimeContext(runtimeContextID, globalAccount string) apperrors.AppError
+	AssignFormation(compassID, formation, globalAccount string) apperrors.AppError
+	UnassignFormation(compassID, formation, globalAccount string) apperrors.AppError
+	GetRuntimeFormations(compassID, globalAccount string) ([]graphql.Formation, apperrors.AppError)
+	GetRuntimeApplications(compassID, globalAccount string) (graphql.ApplicationPage, apperrors.AppError)
+	ListRuntimes(labelKey, labelValue, globalAccount string) ([]graphql.RuntimeExt, apperrors.AppError)
+	ValidateSchema() apperrors.AppError
 }
 
 type directorClient struct {
 	gqlClient     gql.Client
 	queryProvider queryProvider
 	graphqlizer   graphqlizer.Graphqlizer
-	token         oauth.Token
 	oauthClient   oauth.Client
+	breaker       *CircuitBreaker
+	throttle      *Throttle
+	observer      Observer
+	kymaName      string
+	batchSize     int
 }
 
-func NewDirectorClient(gqlClient gql.Client, oauthClient oauth.Client) Client {
-	return &directorClient{
+type Option func(*directorClient)
+
+// WithCircuitBreaker makes the client short-circuit Director calls while the breaker is open
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(cc *directorClient) {
+		cc.breaker = breaker
+	}
+}
+
+// WithThrottle makes the client wait for the request budget before calling Director
+func WithThrottle(throttle *Throttle) Option {
+	return func(cc *directorClient) {
+		cc.throttle = throttle
+	}
+}
+
+// WithBatchSize limits the number of operations sent in a single request by ExecuteBatch
+func WithBatchSize(size int) Option {
+	return func(cc *directorClient) {
+		if size > 0 {
+			cc.batchSize = size
+		}
+	}
+}
+
+// NewDirectorClient creates a Director client. The oauthClient is nil when Director authenticates the client by its certificate.
+func NewDirectorClient(gqlClient gql.Client, oauthClient oauth.Client, opts ...Option) Client {
+	client := &directorClient{
 		gqlClient:     gqlClient,
 		oauthClient:   oauthClient,
 		queryProvider: queryProvider{},
 		graphqlizer:   graphqlizer.Graphqlizer{},
-		token:         oauth.Token{},
+		batchSize:     DefaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt(client)
 	}
+	return client
+}
+
+// WithKymaName returns a client logging its requests with the name of the Kyma they're sent for.
+// Clients other than the one created with NewDirectorClient are returned unchanged.
+func WithKymaName(client Client, kymaName string) Client {
+	cc, ok := client.(*directorClient)
+	if !ok {
+		return client
+	}
+
+	withKymaName := *cc
+	withKymaName.kymaName = kymaName
+	return &withKymaName
 }
 
 func (cc *directorClient) CreateRuntime(config *gqlschema.RuntimeInput, globalAccount string) (string, apperrors.AppError) {
@@ -129,6 +192,7 @@ func (cc *directorClient) GetConnectionToken(compassID, globalAccount string) (g
 		return graphql.OneTimeTokenForRuntimeExt{}, apperrors.Internalf("Failed to get OneTimeToken for Runtime %s in Director: received nil response.", compassID).SetComponent(apperrors.ErrCompassDirector).SetReason(apperrors.ErrDirectorNilResponse)
 	}
 
+	cc.observer.oneTimeToken()
 	log.Infof("Received OneTimeToken for Runtime %s in Director for Global Account %s", compassID, globalAccount)
 
 	return *response.Result, nil
@@ -160,33 +224,233 @@ func (cc *directorClient) DeleteRuntime(compassID, globalAccount string) apperro
 	return nil
 }
 
-func (cc *directorClient) getToken() apperrors.AppError {
+// CreateRuntimeContext creates a context of the runtime for the subaccount and returns its ID
+func (cc *directorClient) CreateRuntimeContext(compassID, subaccount, globalAccount string) (string, apperrors.AppError) {
+	runtimeContextInput, err := cc.graphqlizer.RuntimeContextInputToGQL(graphql.RuntimeContextInput{
+		Key:   RuntimeContextSubaccountKey,
+		Value: subaccount,
+	})
+	if err != nil {
+		return "", apperrors.Internalf("Failed to create graphQLized Runtime Context input: %s", err.Error()).SetComponent(apperrors.ErrCompassDirectorClient).SetReason(apperrors.ErrDirectorClientGraphqlizer)
+	}
+
+	runtimeContextQuery := cc.queryProvider.createRuntimeContextMutation(compassID, runtimeContextInput)
+
+	var response RuntimeContextResponse
+	appErr := cc.executeDirectorGraphQLCall(runtimeContextQuery, globalAccount, &response, false)
+	if appErr != nil {
+		return "", appErr.Append("Failed to create context of runtime %s for subaccount %s in Director", compassID, subaccount)
+	}
+
+	// Nil check is necessary due to GraphQL client not checking response code
+	if response.Result == nil {
+		return "", apperrors.Internalf("Failed to create context of runtime %s in Director: received nil response.", compassID).SetComponent(apperrors.ErrCompassDirector).SetReason(apperrors.ErrDirectorNilResponse)
+	}
+
+	log.Infof("Successfully created context %s of Runtime %s in Director for subaccount %s", response.Result.ID, compassID, subaccount)
+
+	return response.Result.ID, nil
+}
+
+// DeleteRuntimeContext deletes the runtime context, a context which doesn't exist is not an error
+func (cc *directorClient) DeleteRuntimeContext(runtimeContextID, globalAccount string) apperrors.AppError {
+	runtimeContextQuery := cc.queryProvider.deleteRuntimeContextMutation(runtimeContextID)
+
+	var response RuntimeContextResponse
+	err := cc.executeDirectorGraphQLCall(runtimeContextQuery, globalAccount, &response, true)
+	if err != nil {
+		if err.Cause() == apperrors.RuntimeNotFound {
+			log.Infof("Runtime context %s in Director for tenant %s was previously deleted", runtimeContextID, globalAccount)
+			return nil
+		}
+		return err.Append("Failed to delete runtime context %s in Director", runtimeContextID)
+	}
+	// Nil check is necessary due to GraphQL client not checking response code
+	if response.Result == nil {
+		return apperrors.Internalf("Failed to delete runtime context %s in Director: received nil response.", runtimeContextID).SetComponent(apperrors.ErrCompassDirector).SetReason(apperrors.ErrDirectorNilResponse)
+	}
+
+	log.Infof("Successfully deleted runtime context %s in Director for tenant %s", runtimeContextID, globalAccount)
+
+	return nil
+}
+
+// AssignFormation adds the runtime to the formation, which must exist in the tenant
+func (cc *directorClient) AssignFormation(compassID, formation, globalAccount string) apperrors.AppError {
+	formationQuery := cc.queryProvider.assignFormationMutation(compassID, formation)
+
+	var response FormationResponse
+	err := cc.executeDirectorGraphQLCall(formationQuery, globalAccount, &response, false)
+	if err != nil {
+		return err.Append("Failed to assign runtime %s to formation %s in Director", compassID, formation)
+	}
+	// Nil check is necessary due to GraphQL client not checking response code
+	if response.Result == nil {
+		return apperrors.Internalf("Failed to assign runtime %s to formation %s in Director: received nil response.", compassID, formation).SetComponent(apperrors.ErrCompassDirector).SetReason(apperrors.ErrDirectorNilResponse)
+	}
+
+	log.Infof("Successfully assigned Runtime %s to formation %s in Director for tenant %s", compassID, formation, globalAccount)
+
+	return nil
+}
+
+// UnassignFormation removes the runtime from the formation, a formation which doesn't exist is not an error
+func (cc *directorClient) UnassignFormation(compassID, formation, globalAccount string) apperrors.AppError {
+	formationQuery := cc.queryProvider.unassignFormationMutation(compassID, formation)
+
+	var response FormationResponse
+	err := cc.executeDirectorGraphQLCall(formationQuery, globalAccount, &response, true)
+	if err != nil {
+		if err.Cause() == apperrors.RuntimeNotFound {
+			log.Infof("Formation %s in Director for tenant %s was previously deleted", formation, globalAccount)
+			return nil
+		}
+		return err.Append("Failed to unassign runtime %s from formation %s in Director", compassID, formation)
+	}
+	// Nil check is necessary due to GraphQL client not checking response code
+	if response.Result == nil {
+		return apperrors.Internalf("Failed to unassign runtime %s from formation %s in Director: received nil response.", compassID, formation).SetComponent(apperrors.ErrCompassDirector).SetReason(apperrors.ErrDirectorNilResponse)
+	}
+
+	log.Infof("Successfully unassigned Runtime %s from formation %s in Director for tenant %s", compassID, formation, globalAccount)
+
+	return nil
+}
+
+// GetRuntimeFormations returns formations the runtime is assigned to
+func (cc *directorClient) GetRuntimeFormations(compassID, globalAccount string) ([]graphql.Formation, apperrors.AppError) {
+	formationsQuery := cc.queryProvider.runtimeFormationsQuery(compassID)
+
+	var response FormationsResponse
+	err := cc.executeDirectorGraphQLCall(formationsQuery, globalAccount, &response, false)
+	if err != nil {
+		return nil, err.Append("Failed to get formations of runtime %s from Director", compassID)
+	}
+
+	return response.Result, nil
+}
+
+// GetRuntimeApplications returns up to RuntimeApplicationsLimit applications assigned to the runtime, TotalCount of the page counts all of them
+func (cc *directorClient) GetRuntimeApplications(compassID, globalAccount string) (graphql.ApplicationPage, apperrors.AppError) {
+	applicationsQuery := cc.queryProvider.runtimeApplicationsQuery(compassID, RuntimeApplicationsLimit)
+
+	var response ApplicationPageResponse
+	err := cc.executeDirectorGraphQLCall(applicationsQuery, globalAccount, &response, false)
+	if err != nil {
+		return graphql.ApplicationPage{}, err.Append("Failed to get applications of runtime %s from Director", compassID)
+	}
+	// Nil check is necessary due to GraphQL client not checking response code
+	if response.Result == nil {
+		return graphql.ApplicationPage{}, apperrors.Internalf("Failed to get applications of runtime %s from Director: received nil response.", compassID).SetComponent(apperrors.ErrCompassDirector).SetReason(apperrors.ErrDirectorNilResponse)
+	}
+
+	return *response.Result, nil
+}
+
+// ListRuntimes returns all runtimes of the tenant with the label set to the value, reading them page by page
+func (cc *directorClient) ListRuntimes(labelKey, labelValue, globalAccount string) ([]graphql.RuntimeExt, apperrors.AppError) {
+	var runtimes []graphql.RuntimeExt
+	var after string
+	for {
+		runtimesQuery := cc.queryProvider.runtimesQuery(labelKey, labelValue, RuntimesPageSize, after)
+
+		var response RuntimePageResponse
+		err := cc.executeDirectorGraphQLCall(runtimesQuery, globalAccount, &response, false)
+		if err != nil {
+			return nil, err.Append("Failed to list runtimes with label %s from Director", labelKey)
+		}
+		// Nil check is necessary due to GraphQL client not checking response code
+		if response.Result == nil {
+			return nil, apperrors.Internalf("Failed to list runtimes with label %s from Director: received nil response.", labelKey).SetComponent(apperrors.ErrCompassDirector).SetReason(apperrors.ErrDirectorNilResponse)
+		}
+
+		for _, runtime := range response.Result.Data {
+			if runtime != nil {
+				runtimes = append(runtimes, *runtime)
+			}
+		}
+		if response.Result.PageInfo == nil || !response.Result.PageInfo.HasNextPage {
+			return runtimes, nil
+		}
+		after = string(response.Result.PageInfo.EndCursor)
+	}
+}
+
+// getToken returns the token cached by the OAuth client, so that it's refreshed when the credentials change
+func (cc *directorClient) getToken() (oauth.Token, apperrors.AppError) {
 	token, err := cc.oauthClient.GetAuthorizationToken()
 	if err != nil {
-		return err.Append("Error while obtaining token")
+		return oauth.Token{}, err.Append("Error while obtaining token")
 	}
 
 	if token.EmptyOrExpired() {
-		return apperrors.Internal("Obtained empty or expired token")
+		return oauth.Token{}, apperrors.Internal("Obtained empty or expired token")
 	}
 
-	cc.token = token
-	return nil
+	return token, nil
 }
 
 func (cc *directorClient) executeDirectorGraphQLCall(directorQuery string, globalAccount string, response interface{}, gracefulUnregistration bool) apperrors.AppError {
-	if cc.token.EmptyOrExpired() {
-		log.Infof("Refreshing token to access Director Service")
-		if err := cc.getToken(); err != nil {
+	return cc.guardDirectorCall(directorQuery, globalAccount, func() apperrors.AppError {
+		return cc.doDirectorGraphQLCall(directorQuery, globalAccount, response, gracefulUnregistration)
+	})
+}
+
+// guardDirectorCall runs the call once the throttle lets it through, unless the circuit breaker is open
+func (cc *directorClient) guardDirectorCall(directorQuery string, globalAccount string, call func() apperrors.AppError) apperrors.AppError {
+	operation := operationName(directorQuery)
+	err := cc.throttledDirectorCall(directorQuery, globalAccount, func() apperrors.AppError {
+		start := time.Now()
+		err := call()
+		cc.observer.request(operation, time.Since(start))
+		return err
+	})
+	cc.observer.error(operation, err)
+	return err
+}
+
+func (cc *directorClient) throttledDirectorCall(directorQuery string, globalAccount string, call func() apperrors.AppError) apperrors.AppError {
+	// the breaker is checked first, so that short-circuited calls don't wait for, nor use up, the throttle budget
+	if cc.breaker != nil && !cc.breaker.Allow() {
+		return apperrors.Unavailable("Director calls are suspended until %s after consecutive failures", cc.breaker.NextProbe().Format(time.RFC3339)).
+			SetComponent(apperrors.ErrCompassDirectorClient).SetReason(apperrors.ErrDirectorCircuitOpen)
+	}
+
+	if cc.throttle != nil {
+		release, err := cc.throttle.Acquire(globalAccount, isMutation(directorQuery))
+		if err != nil {
+			if cc.breaker != nil {
+				cc.breaker.RecordSkipped()
+			}
 			return err
 		}
+		defer release()
 	}
 
-	req := gcli.NewRequest(directorQuery)
-	req.Header.Set(AuthorizationHeader, fmt.Sprintf("Bearer %s", cc.token.AccessToken))
-	req.Header.Set(TenantHeader, globalAccount)
+	err := call()
+	if cc.breaker == nil {
+		return err
+	}
+	if err != nil && isDirectorUnavailable(err) {
+		cc.breaker.RecordFailure()
+	} else {
+		cc.breaker.RecordSuccess()
+	}
+	return err
+}
 
-	if err := cc.gqlClient.Do(req, response, gracefulUnregistration); err != nil {
+// isDirectorUnavailable distinguishes Director or token endpoint outages from errors caused by the request itself
+func isDirectorUnavailable(err apperrors.AppError) bool {
+	return err.Code() == apperrors.CodeInternal || err.Code() == apperrors.CodeExternal
+}
+
+func (cc *directorClient) doDirectorGraphQLCall(directorQuery string, globalAccount string, response interface{}, gracefulUnregistration bool) apperrors.AppError {
+	req, appErr := cc.newDirectorRequest(directorQuery, globalAccount)
+	if appErr != nil {
+		return appErr
+	}
+
+	if err := cc.gqlClient.Do(cc.requestContext(), req, response, gracefulUnregistration); err != nil {
 		var egErr gcli.ExtendedError
 		if errors.As(err, &egErr) {
 			return mapDirectorErrorToProvisionerError(egErr, gracefulUnregistration).Append("Failed to execute GraphQL request to Director")
@@ -197,6 +461,28 @@ func (cc *directorClient) executeDirectorGraphQLCall(directorQuery string, globa
 	return nil
 }
 
+func (cc *directorClient) newDirectorRequest(directorQuery string, globalAccount string) (*gcli.Request, apperrors.AppError) {
+	req := gcli.NewRequest(directorQuery)
+
+	if cc.oauthClient != nil {
+		token, err := cc.getToken()
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(AuthorizationHeader, fmt.Sprintf("Bearer %s", token.AccessToken))
+	}
+	if globalAccount != "" {
+		req.Header.Set(TenantHeader, globalAccount)
+	}
+
+	return req, nil
+}
+
+// requestContext passes the name of the Kyma, if set, to the request log of the GraphQL client
+func (cc *directorClient) requestContext() context.Context {
+	return gql.WithKymaName(context.Background(), cc.kymaName)
+}
+
 func mapDirectorErrorToProvisionerError(egErr gcli.ExtendedError, gracefulUnregistration bool) apperrors.AppError {
 	errorCodeValue, present := egErr.Extensions()["error_code"]
 	if !present {
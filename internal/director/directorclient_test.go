@@ -1,6 +1,7 @@
 package director
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -9,13 +10,16 @@ import (
 	directorApperrors "github.com/kyma-incubator/compass/components/director/pkg/apperrors"
 	"github.com/kyma-incubator/compass/components/director/pkg/graphql"
 	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/kyma-project/compass-manager/internal/director/fake"
 	gql "github.com/kyma-project/compass-manager/internal/graphql"
+	gqlmocks "github.com/kyma-project/compass-manager/internal/graphql/mocks"
 	"github.com/kyma-project/compass-manager/internal/oauth"
 	oauthmocks "github.com/kyma-project/compass-manager/internal/oauth/mocks"
 	"github.com/kyma-project/compass-manager/internal/util"
 	"github.com/kyma-project/compass-manager/pkg/gqlschema"
 	gcli "github.com/kyma-project/compass-manager/third_party/machinebox/graphql"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -471,6 +475,30 @@ func TestDirectorClient_GetConnectionToken(t *testing.T) {
 		assert.Equal(t, connectorURL, receivedOneTimeToken.ConnectorURL)
 	})
 
+	t.Run("Should pass Kyma name to the GraphQL client for logging", func(t *testing.T) {
+		// given
+		gqlClient := &gqlmocks.Client{}
+		gqlClient.On("Do", mock.MatchedBy(func(ctx context.Context) bool {
+			return gql.KymaName(ctx) == "my-kyma"
+		}), mock.Anything, mock.Anything, false).Run(func(args mock.Arguments) {
+			response, ok := args.Get(2).(*OneTimeTokenResponse)
+			require.True(t, ok)
+			response.Result = &graphql.OneTimeTokenForRuntimeExt{}
+		}).Return(nil)
+
+		mockedOAuthClient := &oauthmocks.Client{}
+		mockedOAuthClient.On("GetAuthorizationToken").Return(oauth.Token{AccessToken: validTokenValue, Expiration: futureExpirationTime}, nil)
+
+		configClient := WithKymaName(NewDirectorClient(gqlClient, mockedOAuthClient), "my-kyma")
+
+		// when
+		_, err := configClient.GetConnectionToken(compassTestingID, globalAccountValue)
+
+		// then
+		require.NoError(t, err)
+		gqlClient.AssertExpectations(t)
+	})
+
 	t.Run("Should return error when Oauth Token is empty", func(t *testing.T) {
 		// given
 		token := oauth.Token{
@@ -538,6 +566,29 @@ func TestDirectorClient_GetConnectionToken(t *testing.T) {
 	})
 }
 
+func TestDirectorClient_CertificateAuthentication(t *testing.T) {
+	t.Run("should call Director without authorization header when OAuth client is not set", func(t *testing.T) {
+		// given
+		expectedRequest := gcli.NewRequest(expectedGetRuntimeQuery)
+		expectedRequest.Header.Set(TenantHeader, globalAccountValue)
+
+		gqlClient := gql.NewQueryAssertClient(t, nil, []*gcli.Request{expectedRequest}, func(t *testing.T, r interface{}) {
+			cfg, ok := r.(*GetRuntimeResponse)
+			require.True(t, ok)
+			cfg.Result = &graphql.RuntimeExt{Runtime: graphql.Runtime{ID: compassTestingID}}
+		})
+
+		configClient := NewDirectorClient(gqlClient, nil)
+
+		// when
+		runtime, err := configClient.GetRuntime(compassTestingID, globalAccountValue)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, compassTestingID, runtime.ID)
+	})
+}
+
 func TestDirectorClient_GetRuntime(t *testing.T) {
 	expectedRequest := gcli.NewRequest(expectedGetRuntimeQuery)
 	expectedRequest.Header.Set(AuthorizationHeader, fmt.Sprintf("Bearer %s", validTokenValue))
@@ -666,6 +717,140 @@ func (e testGraphQLError) Extensions() map[string]interface{} {
 	return e.ErrorExtensions
 }
 
+func TestDirectorClient_RuntimeContexts(t *testing.T) {
+	t.Run("Should create and delete context of runtime for subaccount", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		configClient := newFakeDirectorClient(server)
+
+		runtimeID, err := configClient.CreateRuntime(&gqlschema.RuntimeInput{Name: compassTestingName}, globalAccountValue)
+		require.NoError(t, err)
+
+		// when
+		contextID, createErr := configClient.CreateRuntimeContext(runtimeID, "subaccount", globalAccountValue)
+		contexts := server.Director.RuntimeContexts(globalAccountValue)
+		deleteErr := configClient.DeleteRuntimeContext(contextID, globalAccountValue)
+		deleteAgainErr := configClient.DeleteRuntimeContext(contextID, globalAccountValue)
+
+		// then
+		require.NoError(t, createErr)
+		require.Len(t, contexts, 1)
+		assert.Equal(t, fake.RuntimeContext{ID: contextID, RuntimeID: runtimeID, Key: RuntimeContextSubaccountKey, Value: "subaccount"}, contexts[0])
+		require.NoError(t, deleteErr)
+		require.NoError(t, deleteAgainErr, "context which doesn't exist should be deleted gracefully")
+		assert.Empty(t, server.Director.RuntimeContexts(globalAccountValue))
+	})
+
+	t.Run("Should return error when runtime of the context doesn't exist", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		configClient := newFakeDirectorClient(server)
+
+		// when
+		_, err := configClient.CreateRuntimeContext(compassTestingID, "subaccount", globalAccountValue)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, apperrors.CodeBadRequest, err.Code())
+	})
+}
+
+func TestDirectorClient_Formations(t *testing.T) {
+	t.Run("Should assign runtime to formation and unassign it", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		configClient := newFakeDirectorClient(server)
+
+		server.Director.AddFormation(globalAccountValue, "formation")
+		runtimeID, err := configClient.CreateRuntime(&gqlschema.RuntimeInput{Name: compassTestingName}, globalAccountValue)
+		require.NoError(t, err)
+
+		// when
+		assignErr := configClient.AssignFormation(runtimeID, "formation", globalAccountValue)
+		assigned, _ := server.Director.Formation(globalAccountValue, "formation")
+		unassignErr := configClient.UnassignFormation(runtimeID, "formation", globalAccountValue)
+
+		// then
+		require.NoError(t, assignErr)
+		assert.Equal(t, []string{runtimeID}, assigned.RuntimeIDs)
+		require.NoError(t, unassignErr)
+		unassigned, _ := server.Director.Formation(globalAccountValue, "formation")
+		assert.Empty(t, unassigned.RuntimeIDs)
+	})
+
+	t.Run("Should fail to assign runtime to formation which doesn't exist and unassign it gracefully", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		configClient := newFakeDirectorClient(server)
+
+		runtimeID, err := configClient.CreateRuntime(&gqlschema.RuntimeInput{Name: compassTestingName}, globalAccountValue)
+		require.NoError(t, err)
+
+		// when
+		assignErr := configClient.AssignFormation(runtimeID, "missing", globalAccountValue)
+		unassignErr := configClient.UnassignFormation(runtimeID, "missing", globalAccountValue)
+
+		// then
+		require.Error(t, assignErr)
+		assert.Equal(t, apperrors.CodeBadRequest, assignErr.Code())
+		require.NoError(t, unassignErr)
+	})
+}
+
+func TestDirectorClient_RuntimeAssignments(t *testing.T) {
+	t.Run("Should return formations of runtime and applications assigned to it", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		configClient := newFakeDirectorClient(server)
+
+		runtimeID, err := configClient.CreateRuntime(&gqlschema.RuntimeInput{Name: compassTestingName}, globalAccountValue)
+		require.NoError(t, err)
+		server.Director.AddFormation(globalAccountValue, "formation")
+		server.Director.AddFormation(globalAccountValue, "other")
+		require.NoError(t, configClient.AssignFormation(runtimeID, "formation", globalAccountValue))
+		application := server.Director.AddApplication(globalAccountValue, "application", "formation")
+		server.Director.AddApplication(globalAccountValue, "not-assigned", "other")
+
+		// when
+		formations, formationsErr := configClient.GetRuntimeFormations(runtimeID, globalAccountValue)
+		applications, applicationsErr := configClient.GetRuntimeApplications(runtimeID, globalAccountValue)
+
+		// then
+		require.NoError(t, formationsErr)
+		require.Len(t, formations, 1)
+		assert.Equal(t, "formation", formations[0].Name)
+
+		require.NoError(t, applicationsErr)
+		assert.Equal(t, 1, applications.TotalCount)
+		require.Len(t, applications.Data, 1)
+		assert.Equal(t, application.ID, applications.Data[0].ID)
+		assert.Equal(t, "application", applications.Data[0].Name)
+	})
+
+	t.Run("Should return no formations and applications of runtime without formations", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		configClient := newFakeDirectorClient(server)
+
+		// when
+		formations, formationsErr := configClient.GetRuntimeFormations(compassTestingID, globalAccountValue)
+		applications, applicationsErr := configClient.GetRuntimeApplications(compassTestingID, globalAccountValue)
+
+		// then
+		require.NoError(t, formationsErr)
+		assert.Empty(t, formations)
+		require.NoError(t, applicationsErr)
+		assert.Zero(t, applications.TotalCount)
+		assert.Empty(t, applications.Data)
+	})
+}
+
 func TestDirectorClient_MapDirectorErrors(t *testing.T) {
 	// given
 	expectedRequest := gcli.NewRequest(expectedRegisterRuntimeQuery)
@@ -0,0 +1,111 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	connectorTokenHeader = "Connector-Token"
+	connectorSchemaSDL   = `
+type Query {
+    configuration: Configuration!
+}
+
+type Configuration {
+    token: Token
+}
+
+type Token {
+    token: String!
+}
+`
+)
+
+// Connector serves the configuration query of the Compass Connector.
+// Requests are authenticated with one-time tokens accepted by useToken, or with tokens the Connector issued in exchange, each of them works once.
+type Connector struct {
+	schema   *ast.Schema
+	useToken func(token string) bool
+
+	mu     sync.Mutex
+	issued map[string]bool
+}
+
+// NewConnector creates a Connector accepting tokens used up by useToken, e.g. Director.UseOneTimeToken
+func NewConnector(useToken func(token string) bool) *Connector {
+	return &Connector{
+		schema:   gqlparser.MustLoadSchema(&ast.Source{Name: "connector.graphql", Input: connectorSchemaSDL}),
+		useToken: useToken,
+		issued:   map[string]bool{},
+	}
+}
+
+func (c *Connector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !c.authorize(r.Header.Get(connectorTokenHeader)) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var request graphqlRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode request: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	query, errs := gqlparser.LoadQueryWithRules(c.schema, request.Query, nil)
+	if len(errs) > 0 {
+		response := graphqlResponse{}
+		for _, err := range errs {
+			response.Errors = append(response.Errors, graphqlError{Message: err.Message})
+		}
+		writeJSON(w, http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	response := graphqlResponse{Data: map[string]interface{}{}}
+	for _, operation := range query.Operations {
+		for _, selection := range operation.SelectionSet {
+			if field, ok := selection.(*ast.Field); ok {
+				configuration := map[string]interface{}{"token": map[string]interface{}{"token": c.issueToken()}}
+				response.Data[field.Alias] = project(configuration, field.SelectionSet)
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (c *Connector) authorize(token string) bool {
+	if token == "" {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.issued[token] {
+		delete(c.issued, token)
+		return true
+	}
+	return c.useToken(token)
+}
+
+func (c *Connector) issueToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	token := newOneTimeToken()
+	c.issued[token] = true
+	return token
+}
@@ -0,0 +1,634 @@
+package fake
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	directorApperrors "github.com/kyma-incubator/compass/components/director/pkg/apperrors"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	authorizationHeader = "Authorization"
+	tenantHeader        = "Tenant"
+	oneTimeTokenBytes   = 32
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// Runtime is a runtime registered in the fake Director
+type Runtime struct {
+	ID          string
+	Tenant      string
+	Name        string
+	Description *string
+	Labels      map[string]interface{}
+}
+
+// RuntimeContext is a context of a runtime registered in the fake Director
+type RuntimeContext struct {
+	ID        string
+	RuntimeID string
+	Key       string
+	Value     string
+}
+
+// Formation is a formation created in the fake Director with AddFormation
+type Formation struct {
+	ID         string
+	Name       string
+	RuntimeIDs []string
+}
+
+// Application is an application created in the fake Director with AddApplication, it's assigned to runtimes sharing its formations
+type Application struct {
+	ID         string
+	Name       string
+	Formations []string
+}
+
+// Failure is returned instead of executing the operation
+type Failure struct {
+	// ErrorType is set in the error_code extension of the GraphQL error, as Director does
+	ErrorType directorApperrors.ErrorType
+	Message   string
+	// StatusCode fails the whole HTTP request with the given status instead of returning a GraphQL error
+	StatusCode int
+}
+
+// Director is an in-memory Director serving the part of the GraphQL API used by compass-manager.
+// Runtimes are kept per tenant, taken from the Tenant header.
+type Director struct {
+	schema       *ast.Schema
+	connectorURL string
+	authorize    func(token string) bool
+
+	mu       sync.Mutex
+	runtimes map[string]map[string]Runtime
+	contexts map[string]map[string]RuntimeContext
+	// formations are kept per tenant by name, as they're assigned by name
+	formations   map[string]map[string]Formation
+	applications map[string][]Application
+	tokens       map[string][]string
+	usedTokens   map[string]bool
+	failures     map[string][]Failure
+	calls        map[string]int
+}
+
+// NewDirector creates a Director returning one-time tokens with the given Connector URL.
+// Requests are authorized with the bearer token checked by authorize, all requests are accepted if it's nil.
+func NewDirector(connectorURL string, authorize func(token string) bool) *Director {
+	return &Director{
+		schema:       gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: schemaSDL}),
+		connectorURL: connectorURL,
+		authorize:    authorize,
+		runtimes:     map[string]map[string]Runtime{},
+		contexts:     map[string]map[string]RuntimeContext{},
+		formations:   map[string]map[string]Formation{},
+		applications: map[string][]Application{},
+		tokens:       map[string][]string{},
+		usedTokens:   map[string]bool{},
+		failures:     map[string][]Failure{},
+		calls:        map[string]int{},
+	}
+}
+
+// FailNext makes the next execution of the operation, e.g. registerRuntime, return the failure.
+// Failures of the same operation are returned in the order they were added.
+func (d *Director) FailNext(operation string, failure Failure) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.failures[operation] = append(d.failures[operation], failure)
+}
+
+// Runtimes returns runtimes registered for the tenant
+func (d *Director) Runtimes(tenant string) []Runtime {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	runtimes := make([]Runtime, 0, len(d.runtimes[tenant]))
+	for _, runtime := range d.runtimes[tenant] {
+		runtimes = append(runtimes, runtime)
+	}
+	return runtimes
+}
+
+// Runtime returns the runtime registered for the tenant
+func (d *Director) Runtime(tenant, id string) (Runtime, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	runtime, ok := d.runtimes[tenant][id]
+	return runtime, ok
+}
+
+// RuntimeContexts returns contexts of runtimes registered for the tenant
+func (d *Director) RuntimeContexts(tenant string) []RuntimeContext {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	contexts := make([]RuntimeContext, 0, len(d.contexts[tenant]))
+	for _, runtimeContext := range d.contexts[tenant] {
+		contexts = append(contexts, runtimeContext)
+	}
+	return contexts
+}
+
+// AddFormation creates an empty formation in the tenant, runtimes can be assigned only to existing formations
+func (d *Director) AddFormation(tenant, name string) Formation {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.formations[tenant] == nil {
+		d.formations[tenant] = map[string]Formation{}
+	}
+	formation := Formation{ID: uuid.New().String(), Name: name}
+	d.formations[tenant][name] = formation
+	return formation
+}
+
+// Formation returns the formation of the tenant with runtimes assigned to it
+func (d *Director) Formation(tenant, name string) (Formation, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	formation, ok := d.formations[tenant][name]
+	formation.RuntimeIDs = append([]string(nil), formation.RuntimeIDs...)
+	return formation, ok
+}
+
+// AddApplication creates an application of the tenant in the formations
+func (d *Director) AddApplication(tenant, name string, formations ...string) Application {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	application := Application{ID: uuid.New().String(), Name: name, Formations: formations}
+	d.applications[tenant] = append(d.applications[tenant], application)
+	return application
+}
+
+// OneTimeTokens returns tokens issued for the runtime, the latest one last
+func (d *Director) OneTimeTokens(runtimeID string) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]string(nil), d.tokens[runtimeID]...)
+}
+
+// UseOneTimeToken marks the token as used, it returns false if the token wasn't issued or was already used
+func (d *Director) UseOneTimeToken(token string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.usedTokens[token] {
+		return false
+	}
+	for _, tokens := range d.tokens {
+		if slices.Contains(tokens, token) {
+			d.usedTokens[token] = true
+			return true
+		}
+	}
+	return false
+}
+
+// Calls returns how many times the operation was requested, including failed attempts
+func (d *Director) Calls(operation string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.calls[operation]
+}
+
+type graphqlRequest struct {
+	Query string `json:"query"`
+}
+
+type graphqlError struct {
+	Message    string                 `json:"message"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+	Path       []interface{}          `json:"path,omitempty"`
+}
+
+type graphqlResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []graphqlError         `json:"errors,omitempty"`
+}
+
+func (d *Director) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if d.authorize != nil {
+		token, ok := strings.CutPrefix(r.Header.Get(authorizationHeader), "Bearer ")
+		if !ok || !d.authorize(token) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	var request graphqlRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode request: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	query, errs := gqlparser.LoadQueryWithRules(d.schema, request.Query, nil)
+	if len(errs) > 0 {
+		response := graphqlResponse{}
+		for _, err := range errs {
+			response.Errors = append(response.Errors, graphqlError{Message: err.Message})
+		}
+		writeJSON(w, http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	response := graphqlResponse{Data: map[string]interface{}{}}
+	tenant := r.Header.Get(tenantHeader)
+	for _, operation := range query.Operations {
+		for _, selection := range operation.SelectionSet {
+			field, ok := selection.(*ast.Field)
+			if !ok {
+				continue
+			}
+
+			failure, failed := d.nextFailure(field.Name)
+			if failed && failure.StatusCode != 0 {
+				http.Error(w, failure.Message, failure.StatusCode)
+				return
+			}
+			if failed {
+				err := directorError(failure.ErrorType, failure.Message)
+				err.Path = []interface{}{field.Alias}
+				response.Data[field.Alias] = nil
+				response.Errors = append(response.Errors, err)
+				continue
+			}
+
+			result, gqlErr := d.execute(field, tenant)
+			if gqlErr != nil {
+				gqlErr.Path = []interface{}{field.Alias}
+				response.Data[field.Alias] = nil
+				response.Errors = append(response.Errors, *gqlErr)
+				continue
+			}
+			response.Data[field.Alias] = project(result, field.SelectionSet)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (d *Director) nextFailure(operation string) (Failure, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.calls[operation]++
+	failures := d.failures[operation]
+	if len(failures) == 0 {
+		return Failure{}, false
+	}
+	d.failures[operation] = failures[1:]
+	return failures[0], true
+}
+
+func (d *Director) execute(field *ast.Field, tenant string) (interface{}, *graphqlError) {
+	if field.Name == "__schema" {
+		return introspect(d.schema), nil
+	}
+
+	if tenant == "" {
+		err := directorError(directorApperrors.TenantRequired, "Tenant is required")
+		return nil, &err
+	}
+
+	args := field.ArgumentMap(nil)
+	id, _ := args["id"].(string)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch field.Name {
+	case "registerRuntime":
+		in, _ := args["in"].(map[string]interface{})
+		return d.registerRuntime(tenant, in), nil
+	case "runtime":
+		runtime, ok := d.runtimes[tenant][id]
+		if !ok {
+			return nil, notFound("runtime", id)
+		}
+		return runtime.toGraphQL(), nil
+	case "unregisterRuntime":
+		runtime, ok := d.runtimes[tenant][id]
+		if !ok {
+			return nil, notFound("runtime", id)
+		}
+		delete(d.runtimes[tenant], id)
+		for contextID, runtimeContext := range d.contexts[tenant] {
+			if runtimeContext.RuntimeID == id {
+				delete(d.contexts[tenant], contextID)
+			}
+		}
+		for name, formation := range d.formations[tenant] {
+			d.formations[tenant][name] = formation.without(id)
+		}
+		return runtime.toGraphQL(), nil
+	case "registerRuntimeContext":
+		runtimeID, _ := args["runtimeID"].(string)
+		if _, ok := d.runtimes[tenant][runtimeID]; !ok {
+			return nil, notFound("runtime", runtimeID)
+		}
+		in, _ := args["in"].(map[string]interface{})
+		return d.registerRuntimeContext(tenant, runtimeID, in), nil
+	case "unregisterRuntimeContext":
+		runtimeContext, ok := d.contexts[tenant][id]
+		if !ok {
+			return nil, notFound("runtimeContext", id)
+		}
+		delete(d.contexts[tenant], id)
+		return runtimeContext.toGraphQL(), nil
+	case "formationsForObject":
+		objectID, _ := args["objectID"].(string)
+		formations := []interface{}{}
+		for _, formation := range d.runtimeFormations(tenant, objectID) {
+			formations = append(formations, formation.toGraphQL())
+		}
+		return formations, nil
+	case "applicationsForRuntime":
+		runtimeID, _ := args["runtimeID"].(string)
+		first, _ := args["first"].(int64)
+		return d.runtimeApplications(tenant, runtimeID, int(first)), nil
+	case "runtimes":
+		return d.listRuntimes(tenant, args), nil
+	case "assignFormation", "unassignFormation":
+		return d.changeFormation(tenant, field.Name == "assignFormation", args)
+	case "requestOneTimeTokenForRuntime":
+		if _, ok := d.runtimes[tenant][id]; !ok {
+			return nil, notFound("runtime", id)
+		}
+		token := newOneTimeToken()
+		d.tokens[id] = append(d.tokens[id], token)
+		return map[string]interface{}{
+			"token":        token,
+			"connectorURL": d.connectorURL,
+			"used":         false,
+		}, nil
+	default:
+		err := directorError(directorApperrors.InvalidOperation, fmt.Sprintf("operation %s is not supported by the fake Director", field.Name))
+		return nil, &err
+	}
+}
+
+// listRuntimes returns the page of runtimes of the tenant matching all label filters, sorted by ID.
+// Label filter queries are compared as JSON literals, the cursor is the offset of the next page.
+func (d *Director) listRuntimes(tenant string, args map[string]interface{}) map[string]interface{} {
+	filters, _ := args["filter"].([]interface{})
+	var runtimes []Runtime
+	for _, runtime := range d.runtimes[tenant] {
+		if matchesLabelFilters(runtime, filters) {
+			runtimes = append(runtimes, runtime)
+		}
+	}
+	slices.SortFunc(runtimes, func(a, b Runtime) int { return strings.Compare(a.ID, b.ID) })
+
+	offset := 0
+	if after, ok := args["after"].(string); ok {
+		offset, _ = strconv.Atoi(after)
+	}
+	offset = min(offset, len(runtimes))
+	end := len(runtimes)
+	if first, ok := args["first"].(int64); ok && first > 0 {
+		end = min(offset+int(first), len(runtimes))
+	}
+
+	data := []interface{}{}
+	for _, runtime := range runtimes[offset:end] {
+		data = append(data, runtime.toGraphQL())
+	}
+	return map[string]interface{}{
+		"data": data,
+		"pageInfo": map[string]interface{}{
+			"startCursor": strconv.Itoa(offset),
+			"endCursor":   strconv.Itoa(end),
+			"hasNextPage": end < len(runtimes),
+		},
+		"totalCount": len(runtimes),
+	}
+}
+
+func matchesLabelFilters(runtime Runtime, filters []interface{}) bool {
+	for _, f := range filters {
+		filter, _ := f.(map[string]interface{})
+		key, _ := filter["key"].(string)
+		value, ok := runtime.Labels[key]
+		if !ok {
+			return false
+		}
+		query, ok := filter["query"].(string)
+		if !ok {
+			continue
+		}
+		var expected interface{}
+		if err := json.Unmarshal([]byte(query), &expected); err != nil || fmt.Sprint(expected) != fmt.Sprint(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *Director) registerRuntime(tenant string, in map[string]interface{}) map[string]interface{} {
+	runtime := Runtime{
+		ID:     uuid.New().String(),
+		Tenant: tenant,
+	}
+	runtime.Name, _ = in["name"].(string)
+	if description, ok := in["description"].(string); ok {
+		runtime.Description = &description
+	}
+	runtime.Labels, _ = in["labels"].(map[string]interface{})
+
+	if d.runtimes[tenant] == nil {
+		d.runtimes[tenant] = map[string]Runtime{}
+	}
+	d.runtimes[tenant][runtime.ID] = runtime
+
+	return runtime.toGraphQL()
+}
+
+func (d *Director) registerRuntimeContext(tenant, runtimeID string, in map[string]interface{}) map[string]interface{} {
+	runtimeContext := RuntimeContext{
+		ID:        uuid.New().String(),
+		RuntimeID: runtimeID,
+	}
+	runtimeContext.Key, _ = in["key"].(string)
+	runtimeContext.Value, _ = in["value"].(string)
+
+	if d.contexts[tenant] == nil {
+		d.contexts[tenant] = map[string]RuntimeContext{}
+	}
+	d.contexts[tenant][runtimeContext.ID] = runtimeContext
+
+	return runtimeContext.toGraphQL()
+}
+
+func (d *Director) changeFormation(tenant string, assign bool, args map[string]interface{}) (interface{}, *graphqlError) {
+	objectID, _ := args["objectID"].(string)
+	in, _ := args["formation"].(map[string]interface{})
+	name, _ := in["name"].(string)
+
+	formation, ok := d.formations[tenant][name]
+	if !ok {
+		return nil, notFound("formation", name)
+	}
+	if assign {
+		if _, ok := d.runtimes[tenant][objectID]; !ok {
+			return nil, notFound("runtime", objectID)
+		}
+		formation = formation.without(objectID)
+		formation.RuntimeIDs = append(formation.RuntimeIDs, objectID)
+	} else {
+		formation = formation.without(objectID)
+	}
+	d.formations[tenant][name] = formation
+
+	return formation.toGraphQL(), nil
+}
+
+func (d *Director) runtimeFormations(tenant, runtimeID string) []Formation {
+	var formations []Formation
+	for _, formation := range d.formations[tenant] {
+		if slices.Contains(formation.RuntimeIDs, runtimeID) {
+			formations = append(formations, formation)
+		}
+	}
+	slices.SortFunc(formations, func(a, b Formation) int { return strings.Compare(a.Name, b.Name) })
+	return formations
+}
+
+// runtimeApplications returns the page with up to first applications sharing a formation with the runtime
+func (d *Director) runtimeApplications(tenant, runtimeID string, first int) map[string]interface{} {
+	var names []string
+	for _, formation := range d.runtimeFormations(tenant, runtimeID) {
+		names = append(names, formation.Name)
+	}
+
+	data := []interface{}{}
+	totalCount := 0
+	for _, application := range d.applications[tenant] {
+		if !slices.ContainsFunc(application.Formations, func(formation string) bool { return slices.Contains(names, formation) }) {
+			continue
+		}
+		totalCount++
+		if first <= 0 || len(data) < first {
+			data = append(data, map[string]interface{}{"id": application.ID, "name": application.Name})
+		}
+	}
+	return map[string]interface{}{"data": data, "totalCount": totalCount}
+}
+
+func (f Formation) without(runtimeID string) Formation {
+	f.RuntimeIDs = slices.DeleteFunc(slices.Clone(f.RuntimeIDs), func(id string) bool { return id == runtimeID })
+	return f
+}
+
+func (f Formation) toGraphQL() map[string]interface{} {
+	return map[string]interface{}{
+		"id":   f.ID,
+		"name": f.Name,
+	}
+}
+
+func (c RuntimeContext) toGraphQL() map[string]interface{} {
+	return map[string]interface{}{
+		"id":    c.ID,
+		"key":   c.Key,
+		"value": c.Value,
+	}
+}
+
+func (r Runtime) toGraphQL() map[string]interface{} {
+	return map[string]interface{}{
+		"id":          r.ID,
+		"name":        r.Name,
+		"description": r.Description,
+		"labels":      r.Labels,
+	}
+}
+
+// project keeps the fields requested in the selection set, under their aliases
+func project(value interface{}, selectionSet ast.SelectionSet) interface{} {
+	if len(selectionSet) == 0 {
+		return value
+	}
+
+	switch v := value.(type) {
+	case []interface{}:
+		projected := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			projected = append(projected, project(item, selectionSet))
+		}
+		return projected
+	case map[string]interface{}:
+		projected := map[string]interface{}{}
+		projectInto(projected, v, selectionSet)
+		return projected
+	default:
+		return value
+	}
+}
+
+func projectInto(projected, object map[string]interface{}, selectionSet ast.SelectionSet) {
+	for _, selection := range selectionSet {
+		switch s := selection.(type) {
+		case *ast.Field:
+			projected[s.Alias] = project(object[s.Name], s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				projectInto(projected, object, s.Definition.SelectionSet)
+			}
+		case *ast.InlineFragment:
+			projectInto(projected, object, s.SelectionSet)
+		}
+	}
+}
+
+func directorError(errorType directorApperrors.ErrorType, message string) graphqlError {
+	if message == "" {
+		message = errorType.String()
+	}
+	return graphqlError{
+		Message:    message,
+		Extensions: map[string]interface{}{"error_code": errorType, "error": errorType.String()},
+	}
+}
+
+func notFound(object, id string) *graphqlError {
+	err := directorError(directorApperrors.NotFound, fmt.Sprintf("Object not found [object=%s, id=%s]", object, id))
+	return &err
+}
+
+func newOneTimeToken() string {
+	token := make([]byte, oneTimeTokenBytes)
+	_, _ = rand.Read(token)
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,135 @@
+package fake
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// introspect renders the schema in the shape of the __schema introspection field, so that clients can check their operations against it
+func introspect(schema *ast.Schema) map[string]interface{} {
+	names := make([]string, 0, len(schema.Types))
+	for name := range schema.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	types := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		types = append(types, introspectType(schema, schema.Types[name]))
+	}
+
+	return map[string]interface{}{
+		"queryType":        namedType(schema.Query),
+		"mutationType":     namedType(schema.Mutation),
+		"subscriptionType": namedType(schema.Subscription),
+		"types":            types,
+		"directives":       []interface{}{},
+	}
+}
+
+func namedType(def *ast.Definition) interface{} {
+	if def == nil {
+		return nil
+	}
+	return map[string]interface{}{"name": def.Name}
+}
+
+func introspectType(schema *ast.Schema, def *ast.Definition) map[string]interface{} {
+	t := map[string]interface{}{
+		"kind":          string(def.Kind),
+		"name":          def.Name,
+		"description":   def.Description,
+		"fields":        nil,
+		"inputFields":   nil,
+		"interfaces":    nil,
+		"enumValues":    nil,
+		"possibleTypes": nil,
+	}
+
+	switch def.Kind {
+	case ast.Object, ast.Interface:
+		fields := []interface{}{}
+		for _, field := range def.Fields {
+			if strings.HasPrefix(field.Name, "__") {
+				continue
+			}
+			fields = append(fields, map[string]interface{}{
+				"name":              field.Name,
+				"description":       field.Description,
+				"args":              inputValues(schema, field.Arguments),
+				"type":              typeRef(schema, field.Type),
+				"isDeprecated":      false,
+				"deprecationReason": nil,
+			})
+		}
+		t["fields"] = fields
+
+		interfaces := []interface{}{}
+		for _, name := range def.Interfaces {
+			interfaces = append(interfaces, map[string]interface{}{"kind": string(ast.Interface), "name": name, "ofType": nil})
+		}
+		t["interfaces"] = interfaces
+	case ast.InputObject:
+		var args ast.ArgumentDefinitionList
+		for _, field := range def.Fields {
+			args = append(args, &ast.ArgumentDefinition{Name: field.Name, Description: field.Description, DefaultValue: field.DefaultValue, Type: field.Type})
+		}
+		t["inputFields"] = inputValues(schema, args)
+	case ast.Enum:
+		values := []interface{}{}
+		for _, value := range def.EnumValues {
+			values = append(values, map[string]interface{}{
+				"name":              value.Name,
+				"description":       value.Description,
+				"isDeprecated":      false,
+				"deprecationReason": nil,
+			})
+		}
+		t["enumValues"] = values
+	case ast.Union:
+		possibleTypes := []interface{}{}
+		for _, name := range def.Types {
+			possibleTypes = append(possibleTypes, map[string]interface{}{"kind": string(ast.Object), "name": name, "ofType": nil})
+		}
+		t["possibleTypes"] = possibleTypes
+	case ast.Scalar:
+	}
+
+	return t
+}
+
+func inputValues(schema *ast.Schema, args ast.ArgumentDefinitionList) []interface{} {
+	values := []interface{}{}
+	for _, arg := range args {
+		var defaultValue interface{}
+		if arg.DefaultValue != nil {
+			defaultValue = arg.DefaultValue.String()
+		}
+		values = append(values, map[string]interface{}{
+			"name":         arg.Name,
+			"description":  arg.Description,
+			"type":         typeRef(schema, arg.Type),
+			"defaultValue": defaultValue,
+		})
+	}
+	return values
+}
+
+func typeRef(schema *ast.Schema, t *ast.Type) map[string]interface{} {
+	if t.NonNull {
+		nullable := *t
+		nullable.NonNull = false
+		return map[string]interface{}{"kind": "NON_NULL", "name": nil, "ofType": typeRef(schema, &nullable)}
+	}
+	if t.Elem != nil {
+		return map[string]interface{}{"kind": "LIST", "name": nil, "ofType": typeRef(schema, t.Elem)}
+	}
+
+	kind := string(ast.Scalar)
+	if def, ok := schema.Types[t.NamedType]; ok {
+		kind = string(def.Kind)
+	}
+	return map[string]interface{}{"kind": kind, "name": t.NamedType, "ofType": nil}
+}
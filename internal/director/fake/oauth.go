@@ -0,0 +1,125 @@
+package fake
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const clientCredentialsGrantType = "client_credentials"
+
+// TokenServer is a fake OAuth2 tokens endpoint issuing opaque tokens with the client credentials grant.
+// Clients authenticate with client_secret_basic or client_secret_post; client assertions are accepted without verifying the signature.
+type TokenServer struct {
+	clientID     string
+	clientSecret string
+	lifetime     time.Duration
+
+	mu       sync.Mutex
+	tokens   map[string]time.Time
+	requests int
+}
+
+func NewTokenServer(clientID, clientSecret string, lifetime time.Duration) *TokenServer {
+	return &TokenServer{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		lifetime:     lifetime,
+		tokens:       map[string]time.Time{},
+	}
+}
+
+// Valid tells if the token was issued by the server and hasn't expired or been revoked
+func (s *TokenServer) Valid(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiration, ok := s.tokens[token]
+	return ok && time.Now().Before(expiration)
+}
+
+// RevokeAll makes all issued tokens invalid
+func (s *TokenServer) RevokeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens = map[string]time.Time{}
+}
+
+// Requests returns the number of token requests, including rejected ones
+func (s *TokenServer) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
+
+func (s *TokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	s.mu.Unlock()
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	if r.PostForm.Get("grant_type") != clientCredentialsGrantType {
+		writeOAuthError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+	if !s.authenticated(r) {
+		writeOAuthError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	token := newOneTimeToken()
+	s.mu.Lock()
+	s.tokens[token] = time.Now().Add(s.lifetime)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": token,
+		"token_type":   "bearer",
+		"expires_in":   int64(s.lifetime.Seconds()),
+		"scope":        r.PostForm.Get("scope"),
+	})
+}
+
+func (s *TokenServer) authenticated(r *http.Request) bool {
+	if clientID, clientSecret, ok := r.BasicAuth(); ok {
+		return clientID == s.clientID && clientSecret == s.clientSecret
+	}
+	if assertion := r.PostForm.Get("client_assertion"); assertion != "" {
+		return r.PostForm.Get("client_id") == s.clientID && assertionIssuer(assertion) == s.clientID
+	}
+	return r.PostForm.Get("client_id") == s.clientID && r.PostForm.Get("client_secret") == s.clientSecret
+}
+
+func assertionIssuer(assertion string) string {
+	parts := strings.Split(assertion, ".")
+	if len(parts) != 3 { //nolint:mnd
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	var claims struct {
+		Issuer string `json:"iss"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return ""
+	}
+	return claims.Issuer
+}
+
+func writeOAuthError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]string{"error": code})
+}
@@ -0,0 +1,109 @@
+// Package fake provides an in-process Director, OAuth2 tokens endpoint and Connector speaking the same wire format as Compass.
+// It's meant for tests, including the envtest suite, and for running compass-manager locally without a Compass landscape.
+package fake
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+const (
+	DirectorPath         = "/director/graphql"
+	TokensPath           = "/oauth2/token"
+	ConnectorPath        = "/connector/graphql"
+	DefaultClientID      = "compass-manager"
+	DefaultClientSecret  = "compass-manager-secret"
+	defaultTokenLifetime = time.Hour
+)
+
+// Config of the fake server, zero values are replaced with defaults
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	// ConnectorURL returned with one-time tokens, defaults to the Connector path of the server
+	ConnectorURL  string
+	TokenLifetime time.Duration
+	// DisableAuth makes Director accept requests without a token, as with client certificate authentication
+	DisableAuth bool
+	// TLS serves over HTTPS with a self-signed certificate trusted by the client returned by Server.Client, as the Connector is only called over HTTPS
+	TLS bool
+}
+
+// Server serves the fake Director, the tokens endpoint and the Connector on one address
+type Server struct {
+	Director *Director
+	Tokens   *TokenServer
+	server   *httptest.Server
+}
+
+// NewServer starts the fake Director and tokens endpoint on a local port
+func NewServer(cfg Config) *Server {
+	s := &Server{}
+	s.server = httptest.NewUnstartedServer(nil)
+	if cfg.ConnectorURL == "" {
+		scheme := "http://"
+		if cfg.TLS {
+			scheme = "https://"
+		}
+		cfg.ConnectorURL = scheme + s.server.Listener.Addr().String() + ConnectorPath
+	}
+	s.Director, s.Tokens = NewComponents(cfg)
+	s.server.Config.Handler = NewHandler(s.Director, s.Tokens)
+	if cfg.TLS {
+		s.server.StartTLS()
+	} else {
+		s.server.Start()
+	}
+	return s
+}
+
+// NewHandler routes requests to the Director, tokens endpoint and Connector accepting one-time tokens issued by the Director
+func NewHandler(director *Director, tokens *TokenServer) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(DirectorPath, director)
+	mux.Handle(TokensPath, tokens)
+	mux.Handle(ConnectorPath, NewConnector(director.UseOneTimeToken))
+	return mux
+}
+
+// NewComponents creates the Director and tokens endpoint, for serving them outside of httptest
+func NewComponents(cfg Config) (*Director, *TokenServer) {
+	if cfg.ClientID == "" {
+		cfg.ClientID = DefaultClientID
+	}
+	if cfg.ClientSecret == "" {
+		cfg.ClientSecret = DefaultClientSecret
+	}
+	if cfg.TokenLifetime == 0 {
+		cfg.TokenLifetime = defaultTokenLifetime
+	}
+
+	tokens := NewTokenServer(cfg.ClientID, cfg.ClientSecret, cfg.TokenLifetime)
+	authorize := tokens.Valid
+	if cfg.DisableAuth {
+		authorize = nil
+	}
+	return NewDirector(cfg.ConnectorURL, authorize), tokens
+}
+
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+func (s *Server) DirectorURL() string {
+	return s.server.URL + DirectorPath
+}
+
+func (s *Server) TokensEndpoint() string {
+	return s.server.URL + TokensPath
+}
+
+// Client returns an HTTP client trusting the certificate of the server
+func (s *Server) Client() *http.Client {
+	return s.server.Client()
+}
+
+func (s *Server) Close() {
+	s.server.Close()
+}
@@ -0,0 +1,201 @@
+package fake_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	directorApperrors "github.com/kyma-incubator/compass/components/director/pkg/apperrors"
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/kyma-project/compass-manager/internal/director"
+	"github.com/kyma-project/compass-manager/internal/director/fake"
+	"github.com/kyma-project/compass-manager/internal/graphql"
+	"github.com/kyma-project/compass-manager/internal/oauth"
+	"github.com/kyma-project/compass-manager/pkg/gqlschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tenant = "3e64ebae-38b5-46a0-b1ed-9ccee153a0ae"
+
+func TestFakeDirector(t *testing.T) {
+	t.Run("should register runtime, issue one-time token and unregister runtime", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		client := newDirectorClient(server, fake.DefaultClientSecret)
+
+		// when
+		runtimeID, err := client.CreateRuntime(&gqlschema.RuntimeInput{
+			Name:   "my-runtime",
+			Labels: gqlschema.Labels{"global_account_id": tenant},
+		}, tenant)
+		require.NoError(t, err)
+
+		runtime, getErr := client.GetRuntime(runtimeID, tenant)
+		token, tokenErr := client.GetConnectionToken(runtimeID, tenant)
+		deleteErr := client.DeleteRuntime(runtimeID, tenant)
+
+		// then
+		require.NoError(t, getErr)
+		assert.Equal(t, "my-runtime", runtime.Name)
+		assert.Equal(t, tenant, runtime.Labels["global_account_id"])
+
+		require.NoError(t, tokenErr)
+		assert.Equal(t, server.URL()+fake.ConnectorPath, token.ConnectorURL)
+		assert.Equal(t, []string{token.Token}, server.Director.OneTimeTokens(runtimeID))
+
+		require.NoError(t, deleteErr)
+		assert.Empty(t, server.Director.Runtimes(tenant))
+	})
+
+	t.Run("should keep runtimes of tenants apart", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		client := newDirectorClient(server, fake.DefaultClientSecret)
+
+		runtimeID, err := client.CreateRuntime(&gqlschema.RuntimeInput{Name: "my-runtime"}, tenant)
+		require.NoError(t, err)
+
+		// when
+		_, getErr := client.GetRuntime(runtimeID, "other-tenant")
+
+		// then
+		require.Error(t, getErr)
+		assert.Equal(t, apperrors.ErrReason(directorApperrors.NotFound.String()), getErr.Reason())
+	})
+
+	t.Run("should list runtimes with the label page by page", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		client := newDirectorClient(server, fake.DefaultClientSecret)
+
+		for i := 0; i <= director.RuntimesPageSize; i++ {
+			_, err := client.CreateRuntime(&gqlschema.RuntimeInput{
+				Name:   "managed-runtime",
+				Labels: gqlschema.Labels{"director_connection_managed_by": "compass-manager"},
+			}, tenant)
+			require.NoError(t, err)
+		}
+		_, err := client.CreateRuntime(&gqlschema.RuntimeInput{
+			Name:   "provisioned-runtime",
+			Labels: gqlschema.Labels{"director_connection_managed_by": "provisioner"},
+		}, tenant)
+		require.NoError(t, err)
+
+		// when
+		runtimes, listErr := client.ListRuntimes("director_connection_managed_by", "compass-manager", tenant)
+
+		// then
+		require.NoError(t, listErr)
+		assert.Len(t, runtimes, director.RuntimesPageSize+1)
+		for _, runtime := range runtimes {
+			assert.Equal(t, "managed-runtime", runtime.Name)
+		}
+	})
+
+	t.Run("should treat unregistering a missing runtime as done", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		client := newDirectorClient(server, fake.DefaultClientSecret)
+
+		// when
+		err := client.DeleteRuntime("ba7ac1d6-8df6-4f3b-8b5a-2d7d1e8e6a10", tenant)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should return injected failures in the Director error format", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		client := newDirectorClient(server, fake.DefaultClientSecret)
+		server.Director.FailNext("registerRuntime", fake.Failure{ErrorType: directorApperrors.NotUnique})
+		server.Director.FailNext("registerRuntime", fake.Failure{StatusCode: http.StatusServiceUnavailable})
+
+		// when
+		_, notUniqueErr := client.CreateRuntime(&gqlschema.RuntimeInput{Name: "my-runtime"}, tenant)
+		_, unavailableErr := client.CreateRuntime(&gqlschema.RuntimeInput{Name: "my-runtime"}, tenant)
+		_, err := client.CreateRuntime(&gqlschema.RuntimeInput{Name: "my-runtime"}, tenant)
+
+		// then
+		require.Error(t, notUniqueErr)
+		assert.Equal(t, apperrors.CodeBadRequest, notUniqueErr.Code())
+		assert.Equal(t, apperrors.ErrReason(directorApperrors.NotUnique.String()), notUniqueErr.Reason())
+
+		require.Error(t, unavailableErr)
+		assert.Equal(t, apperrors.CodeInternal, unavailableErr.Code())
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, server.Director.Calls("registerRuntime"))
+	})
+
+	t.Run("should reject requests with a token not issued by the tokens endpoint", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		client := newDirectorClient(server, fake.DefaultClientSecret)
+		_, err := client.CreateRuntime(&gqlschema.RuntimeInput{Name: "my-runtime"}, tenant)
+		require.NoError(t, err)
+
+		// when
+		server.Tokens.RevokeAll()
+		_, err = client.CreateRuntime(&gqlschema.RuntimeInput{Name: "my-runtime"}, tenant)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, 1, server.Tokens.Requests())
+	})
+
+	t.Run("should reject invalid client credentials", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		oauthClient := oauth.NewReloadableOauthClient(http.DefaultClient)
+		oauthClient.UpdateCredentials(fake.DefaultClientID, "wrong-secret", server.TokensEndpoint())
+
+		// when
+		_, err := oauthClient.GetAuthorizationToken()
+
+		// then
+		require.Error(t, err)
+		require.Error(t, oauthClient.CredentialsValid())
+	})
+
+	t.Run("should serve schema compatible with the client", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+		client := newDirectorClient(server, fake.DefaultClientSecret)
+
+		// when
+		err := client.ValidateSchema()
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should accept requests without token when authentication is disabled", func(t *testing.T) {
+		// given
+		server := fake.NewServer(fake.Config{DisableAuth: true})
+		defer server.Close()
+		client := director.NewDirectorClient(graphql.NewGraphQLClient(server.DirectorURL(), false, false), nil)
+
+		// when
+		_, err := client.CreateRuntime(&gqlschema.RuntimeInput{Name: "my-runtime"}, tenant)
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, server.Director.Runtimes(tenant), 1)
+	})
+}
+
+func newDirectorClient(server *fake.Server, clientSecret string) director.Client {
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	oauthClient := oauth.NewOauthClient(httpClient, fake.DefaultClientID, clientSecret, server.TokensEndpoint())
+	return director.NewDirectorClient(graphql.NewGraphQLClient(server.DirectorURL(), false, false), oauthClient)
+}
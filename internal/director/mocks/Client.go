@@ -14,6 +14,22 @@ type Client struct {
 	mock.Mock
 }
 
+// AssignFormation provides a mock function with given fields: compassID, formation, globalAccount
+func (_m *Client) AssignFormation(compassID string, formation string, globalAccount string) apperrors.AppError {
+	ret := _m.Called(compassID, formation, globalAccount)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string, string) apperrors.AppError); ok {
+		r0 = rf(compassID, formation, globalAccount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // CreateRuntime provides a mock function with given fields: config, globalAccount
 func (_m *Client) CreateRuntime(config *gqlschema.RuntimeInput, globalAccount string) (string, apperrors.AppError) {
 	ret := _m.Called(config, globalAccount)
@@ -40,6 +56,32 @@ func (_m *Client) CreateRuntime(config *gqlschema.RuntimeInput, globalAccount st
 	return r0, r1
 }
 
+// CreateRuntimeContext provides a mock function with given fields: compassID, subaccount, globalAccount
+func (_m *Client) CreateRuntimeContext(compassID string, subaccount string, globalAccount string) (string, apperrors.AppError) {
+	ret := _m.Called(compassID, subaccount, globalAccount)
+
+	var r0 string
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string, string) (string, apperrors.AppError)); ok {
+		return rf(compassID, subaccount, globalAccount)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) string); ok {
+		r0 = rf(compassID, subaccount, globalAccount)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) apperrors.AppError); ok {
+		r1 = rf(compassID, subaccount, globalAccount)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // DeleteRuntime provides a mock function with given fields: compassID, globalAccount
 func (_m *Client) DeleteRuntime(compassID string, globalAccount string) apperrors.AppError {
 	ret := _m.Called(compassID, globalAccount)
@@ -56,6 +98,22 @@ func (_m *Client) DeleteRuntime(compassID string, globalAccount string) apperror
 	return r0
 }
 
+// DeleteRuntimeContext provides a mock function with given fields: runtimeContextID, globalAccount
+func (_m *Client) DeleteRuntimeContext(runtimeContextID string, globalAccount string) apperrors.AppError {
+	ret := _m.Called(runtimeContextID, globalAccount)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string) apperrors.AppError); ok {
+		r0 = rf(runtimeContextID, globalAccount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // GetConnectionToken provides a mock function with given fields: compassID, globalAccount
 func (_m *Client) GetConnectionToken(compassID string, globalAccount string) (graphql.OneTimeTokenForRuntimeExt, apperrors.AppError) {
 	ret := _m.Called(compassID, globalAccount)
@@ -108,6 +166,120 @@ func (_m *Client) GetRuntime(compassID string, globalAccount string) (graphql.Ru
 	return r0, r1
 }
 
+// GetRuntimeApplications provides a mock function with given fields: compassID, globalAccount
+func (_m *Client) GetRuntimeApplications(compassID string, globalAccount string) (graphql.ApplicationPage, apperrors.AppError) {
+	ret := _m.Called(compassID, globalAccount)
+
+	var r0 graphql.ApplicationPage
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string) (graphql.ApplicationPage, apperrors.AppError)); ok {
+		return rf(compassID, globalAccount)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) graphql.ApplicationPage); ok {
+		r0 = rf(compassID, globalAccount)
+	} else {
+		r0 = ret.Get(0).(graphql.ApplicationPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) apperrors.AppError); ok {
+		r1 = rf(compassID, globalAccount)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetRuntimeFormations provides a mock function with given fields: compassID, globalAccount
+func (_m *Client) GetRuntimeFormations(compassID string, globalAccount string) ([]graphql.Formation, apperrors.AppError) {
+	ret := _m.Called(compassID, globalAccount)
+
+	var r0 []graphql.Formation
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string) ([]graphql.Formation, apperrors.AppError)); ok {
+		return rf(compassID, globalAccount)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []graphql.Formation); ok {
+		r0 = rf(compassID, globalAccount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]graphql.Formation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) apperrors.AppError); ok {
+		r1 = rf(compassID, globalAccount)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListRuntimes provides a mock function with given fields: labelKey, labelValue, globalAccount
+func (_m *Client) ListRuntimes(labelKey string, labelValue string, globalAccount string) ([]graphql.RuntimeExt, apperrors.AppError) {
+	ret := _m.Called(labelKey, labelValue, globalAccount)
+
+	var r0 []graphql.RuntimeExt
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string, string) ([]graphql.RuntimeExt, apperrors.AppError)); ok {
+		return rf(labelKey, labelValue, globalAccount)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) []graphql.RuntimeExt); ok {
+		r0 = rf(labelKey, labelValue, globalAccount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]graphql.RuntimeExt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) apperrors.AppError); ok {
+		r1 = rf(labelKey, labelValue, globalAccount)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// UnassignFormation provides a mock function with given fields: compassID, formation, globalAccount
+func (_m *Client) UnassignFormation(compassID string, formation string, globalAccount string) apperrors.AppError {
+	ret := _m.Called(compassID, formation, globalAccount)
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func(string, string, string) apperrors.AppError); ok {
+		r0 = rf(compassID, formation, globalAccount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
+// ValidateSchema provides a mock function with given fields:
+func (_m *Client) ValidateSchema() apperrors.AppError {
+	ret := _m.Called()
+
+	var r0 apperrors.AppError
+	if rf, ok := ret.Get(0).(func() apperrors.AppError); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(apperrors.AppError)
+		}
+	}
+
+	return r0
+}
+
 // NewClient creates a new instance of Client. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewClient(t interface {
@@ -19,3 +19,23 @@ type DeleteRuntimeResponse struct {
 type OneTimeTokenResponse struct {
 	Result *graphql.OneTimeTokenForRuntimeExt `json:"result"`
 }
+
+type RuntimeContextResponse struct {
+	Result *graphql.RuntimeContext `json:"result"`
+}
+
+type FormationResponse struct {
+	Result *graphql.Formation `json:"result"`
+}
+
+type FormationsResponse struct {
+	Result []graphql.Formation `json:"result"`
+}
+
+type ApplicationPageResponse struct {
+	Result *graphql.ApplicationPage `json:"result"`
+}
+
+type RuntimePageResponse struct {
+	Result *graphql.RuntimePageExt `json:"result"`
+}
@@ -0,0 +1,73 @@
+package director
+
+import (
+	"time"
+
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+const (
+	// OperationBatch names requests sending operations of many fields, see ExecuteBatch
+	OperationBatch = "batch"
+	// OperationUnknown names requests whose document couldn't be parsed
+	OperationUnknown = "unknown"
+)
+
+// Observer is notified of requests to Director, e.g. to export their metrics. Functions which aren't set are not called.
+// Operations are named by the Director field they call, like registerRuntime or runtime.
+type Observer struct {
+	// OnRequest is called with the duration of every request sent to Director, without the time it waited for the throttle
+	OnRequest func(operation string, duration time.Duration)
+	// OnError is called for every failed operation, including requests rejected by the throttle or the circuit breaker
+	OnError func(operation string, err apperrors.AppError)
+	// OnOneTimeToken is called for every one-time token issued by Director
+	OnOneTimeToken func()
+}
+
+// WithObserver makes the client notify the observer of its requests
+func WithObserver(observer Observer) Option {
+	return func(cc *directorClient) {
+		cc.observer = observer
+	}
+}
+
+func (o Observer) request(operation string, duration time.Duration) {
+	if o.OnRequest != nil {
+		o.OnRequest(operation, duration)
+	}
+}
+
+func (o Observer) error(operation string, err apperrors.AppError) {
+	if o.OnError != nil && err != nil {
+		o.OnError(operation, err)
+	}
+}
+
+func (o Observer) oneTimeToken() {
+	if o.OnOneTimeToken != nil {
+		o.OnOneTimeToken()
+	}
+}
+
+// operationName returns the name of the field called by the document, or OperationBatch when it calls more than one
+func operationName(document string) string {
+	query, err := parser.ParseQuery(&ast.Source{Input: document})
+	if err != nil || len(query.Operations) != 1 {
+		return OperationUnknown
+	}
+
+	selections := query.Operations[0].SelectionSet
+	if len(selections) == 0 {
+		return OperationUnknown
+	}
+	if len(selections) > 1 {
+		return OperationBatch
+	}
+	field, ok := selections[0].(*ast.Field)
+	if !ok {
+		return OperationUnknown
+	}
+	return field.Name
+}
@@ -0,0 +1,100 @@
+package director
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/compass/components/director/pkg/graphql"
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	gql "github.com/kyma-project/compass-manager/internal/graphql"
+	"github.com/kyma-project/compass-manager/internal/oauth"
+	oauthmocks "github.com/kyma-project/compass-manager/internal/oauth/mocks"
+	gcli "github.com/kyma-project/compass-manager/third_party/machinebox/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectorClient_Observer(t *testing.T) {
+	token := oauth.Token{
+		AccessToken: validTokenValue,
+		Expiration:  futureExpirationTime,
+	}
+
+	type observed struct {
+		requests []string
+		errors   []apperrors.AppError
+		tokens   int
+	}
+	newObserver := func(o *observed) Observer {
+		return Observer{
+			OnRequest: func(operation string, _ time.Duration) {
+				o.requests = append(o.requests, operation)
+			},
+			OnError: func(operation string, err apperrors.AppError) {
+				assert.Equal(t, "requestOneTimeTokenForRuntime", operation)
+				o.errors = append(o.errors, err)
+			},
+			OnOneTimeToken: func() {
+				o.tokens++
+			},
+		}
+	}
+
+	t.Run("should observe issued one-time tokens", func(t *testing.T) {
+		// given
+		gqlClient := gql.NewQueryAssertClient(t, nil, []*gcli.Request{newExpectedRequest(expectedOneTimeTokenQuery)}, func(t *testing.T, r interface{}) {
+			cfg, ok := r.(*OneTimeTokenResponse)
+			require.True(t, ok)
+			cfg.Result = &graphql.OneTimeTokenForRuntimeExt{}
+		})
+
+		mockedOAuthClient := &oauthmocks.Client{}
+		mockedOAuthClient.On("GetAuthorizationToken").Return(token, nil)
+
+		o := observed{}
+		configClient := NewDirectorClient(gqlClient, mockedOAuthClient, WithObserver(newObserver(&o)))
+
+		// when
+		_, err := configClient.GetConnectionToken(compassTestingID, globalAccountValue)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"requestOneTimeTokenForRuntime"}, o.requests)
+		assert.Empty(t, o.errors)
+		assert.Equal(t, 1, o.tokens)
+	})
+
+	t.Run("should observe errors of requests which weren't sent", func(t *testing.T) {
+		// given
+		gqlClient := gql.NewQueryAssertClient(t, errors.New("connection refused"), []*gcli.Request{newExpectedRequest(expectedOneTimeTokenQuery)})
+
+		mockedOAuthClient := &oauthmocks.Client{}
+		mockedOAuthClient.On("GetAuthorizationToken").Return(token, nil)
+
+		o := observed{}
+		breaker := NewCircuitBreaker(1, time.Minute, nil)
+		configClient := NewDirectorClient(gqlClient, mockedOAuthClient, WithCircuitBreaker(breaker), WithObserver(newObserver(&o)))
+
+		// when
+		_, _ = configClient.GetConnectionToken(compassTestingID, globalAccountValue)
+		_, _ = configClient.GetConnectionToken(compassTestingID, globalAccountValue)
+
+		// then
+		assert.Equal(t, []string{"requestOneTimeTokenForRuntime"}, o.requests)
+		require.Len(t, o.errors, 2)
+		assert.Equal(t, apperrors.CodeInternal, o.errors[0].Code())
+		assert.Equal(t, apperrors.ErrDirectorCircuitOpen, o.errors[1].Reason())
+		assert.Zero(t, o.tokens)
+	})
+}
+
+func TestOperationName(t *testing.T) {
+	qp := queryProvider{}
+
+	assert.Equal(t, "registerRuntime", operationName(qp.createRuntimeMutation("{}")))
+	assert.Equal(t, "runtime", operationName(qp.getRuntimeQuery(compassTestingID)))
+	assert.Equal(t, "runtimes", operationName(qp.runtimesQuery("key", "value", RuntimesPageSize, "")))
+	assert.Equal(t, OperationBatch, operationName(qp.batchDocument("query", []string{qp.runtimeField("a", "1"), qp.runtimeField("b", "2")})))
+	assert.Equal(t, OperationUnknown, operationName("not a query {"))
+}
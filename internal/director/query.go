@@ -1,6 +1,10 @@
 package director
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
 
 type queryProvider struct{}
 
@@ -29,3 +33,89 @@ func (qp queryProvider) requestOneTimeTokenMutation(compassID string) string {
 		token connectorURL
 }}`, compassID)
 }
+
+func (qp queryProvider) createRuntimeContextMutation(compassID, runtimeContextInput string) string {
+	return fmt.Sprintf(`mutation {
+	result: registerRuntimeContext(runtimeID: "%s", in: %s) {
+		id key value
+}}`, compassID, runtimeContextInput)
+}
+
+func (qp queryProvider) deleteRuntimeContextMutation(runtimeContextID string) string {
+	return fmt.Sprintf(`mutation {
+	result: unregisterRuntimeContext(id: "%s") {
+		id
+}}`, runtimeContextID)
+}
+
+func (qp queryProvider) assignFormationMutation(compassID, formation string) string {
+	return fmt.Sprintf(`mutation {
+	result: assignFormation(objectID: "%s", objectType: RUNTIME, formation: { name: "%s" }) {
+		id name
+}}`, compassID, formation)
+}
+
+func (qp queryProvider) unassignFormationMutation(compassID, formation string) string {
+	return fmt.Sprintf(`mutation {
+	result: unassignFormation(objectID: "%s", objectType: RUNTIME, formation: { name: "%s" }) {
+		id name
+}}`, compassID, formation)
+}
+
+func (qp queryProvider) runtimeFormationsQuery(compassID string) string {
+	return fmt.Sprintf(`query {
+	result: formationsForObject(objectID: "%s") {
+		id name
+}}`, compassID)
+}
+
+func (qp queryProvider) runtimeApplicationsQuery(compassID string, pageSize int) string {
+	return fmt.Sprintf(`query {
+	result: applicationsForRuntime(runtimeID: "%s", first: %d) {
+		data { id name } totalCount
+}}`, compassID, pageSize)
+}
+
+// runtimesQuery lists a page of runtimes with the label, the value is a JSON literal as Director compares label values as JSON
+func (qp queryProvider) runtimesQuery(labelKey, labelValue string, pageSize int, after string) string {
+	value, _ := json.Marshal(labelValue)
+	var afterArg string
+	if after != "" {
+		afterArg = fmt.Sprintf(", after: %q", after)
+	}
+	return fmt.Sprintf(`query {
+	result: runtimes(filter: [{ key: %q, query: %q }], first: %d%s) {
+		data { id name description labels } pageInfo { endCursor hasNextPage } totalCount
+}}`, labelKey, string(value), pageSize, afterArg)
+}
+
+// The fields below are the operations above under a custom alias, for sending many of them in one request
+
+func (qp queryProvider) registerRuntimeField(alias, runtimeInput string) string {
+	return fmt.Sprintf(`%s: registerRuntime(in: %s) { id }`, alias, runtimeInput)
+}
+
+func (qp queryProvider) runtimeField(alias, compassID string) string {
+	return fmt.Sprintf(`%s: runtime(id: "%s") { id name description labels }`, alias, compassID)
+}
+
+func (qp queryProvider) unregisterRuntimeField(alias, compassID string) string {
+	return fmt.Sprintf(`%s: unregisterRuntime(id: "%s") { id }`, alias, compassID)
+}
+
+func (qp queryProvider) requestOneTimeTokenField(alias, compassID string) string {
+	return fmt.Sprintf(`%s: requestOneTimeTokenForRuntime(id: "%s") { token connectorURL }`, alias, compassID)
+}
+
+func (qp queryProvider) runtimeFormationsField(alias, compassID string) string {
+	return fmt.Sprintf(`%s: formationsForObject(objectID: "%s") { id name }`, alias, compassID)
+}
+
+func (qp queryProvider) runtimeApplicationsField(alias, compassID string, pageSize int) string {
+	return fmt.Sprintf(`%s: applicationsForRuntime(runtimeID: "%s", first: %d) { data { id name } totalCount }`, alias, compassID, pageSize)
+}
+
+// batchDocument joins the fields into a query or mutation
+func (qp queryProvider) batchDocument(operationType string, fields []string) string {
+	return fmt.Sprintf("%s {\n\t%s\n}", operationType, strings.Join(fields, "\n\t"))
+}
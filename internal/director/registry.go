@@ -0,0 +1,139 @@
+package director
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Selector decides which Kyma runtimes are registered in a Director.
+// All KymaLabels must match, or the global account must start with one of GlobalAccountPrefixes.
+type Selector struct {
+	KymaLabels            map[string]string
+	GlobalAccountPrefixes []string
+}
+
+// Endpoint is a Director instance together with the settings of the Compass landscape it belongs to
+type Endpoint struct {
+	Name                string
+	Client              Client
+	ConnectorURLPattern string
+	Breaker             *CircuitBreaker
+	Selector            Selector
+	// Default endpoint is used for Kymas not matched by any selector and for mappings created without a Director name
+	Default bool
+}
+
+// Registry resolves the Director responsible for a Kyma runtime
+type Registry struct {
+	endpoints       []Endpoint
+	defaultEndpoint int
+}
+
+// NewRegistry creates a registry from endpoints with unique names. Exactly one endpoint must be the default, unless there is only one.
+func NewRegistry(endpoints ...Endpoint) (*Registry, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("at least one Director must be configured")
+	}
+
+	registry := &Registry{endpoints: endpoints, defaultEndpoint: -1}
+	names := map[string]bool{}
+	for i, endpoint := range endpoints {
+		if names[endpoint.Name] {
+			return nil, errors.Errorf("Director %q is configured more than once", endpoint.Name)
+		}
+		names[endpoint.Name] = true
+
+		if endpoint.Default {
+			if registry.defaultEndpoint >= 0 {
+				return nil, errors.Errorf("Directors %q and %q are both marked as default", endpoints[registry.defaultEndpoint].Name, endpoint.Name)
+			}
+			registry.defaultEndpoint = i
+		}
+	}
+
+	if registry.defaultEndpoint < 0 {
+		if len(endpoints) > 1 {
+			return nil, errors.New("one of the configured Directors must be marked as default")
+		}
+		registry.defaultEndpoint = 0
+	}
+
+	return registry, nil
+}
+
+// NewSingleDirectorRegistry creates a registry with one default Director
+func NewSingleDirectorRegistry(client Client, connectorURLPattern string) *Registry {
+	return &Registry{
+		endpoints: []Endpoint{{
+			Client:              client,
+			ConnectorURLPattern: connectorURLPattern,
+			Default:             true,
+		}},
+	}
+}
+
+// Endpoints returns all configured Directors
+func (r *Registry) Endpoints() []Endpoint {
+	return r.endpoints
+}
+
+// SelectDirector returns the name of the Director the Kyma with given labels should be registered in.
+// Label selectors take precedence over global account prefixes.
+func (r *Registry) SelectDirector(kymaLabels map[string]string, globalAccount string) string {
+	for _, endpoint := range r.endpoints {
+		if endpoint.Selector.matchesLabels(kymaLabels) {
+			return endpoint.Name
+		}
+	}
+	for _, endpoint := range r.endpoints {
+		if endpoint.Selector.matchesGlobalAccount(globalAccount) {
+			return endpoint.Name
+		}
+	}
+	return r.endpoints[r.defaultEndpoint].Name
+}
+
+// Get returns the Director with given name, or the default one for an empty name
+func (r *Registry) Get(name string) (Endpoint, error) {
+	if name == "" {
+		return r.endpoints[r.defaultEndpoint], nil
+	}
+	for _, endpoint := range r.endpoints {
+		if endpoint.Name == name {
+			return endpoint, nil
+		}
+	}
+	return Endpoint{}, errors.Errorf("Director %q is not configured", name)
+}
+
+// NextProbe returns the time at which short-circuited calls to the Director are allowed again
+func (r *Registry) NextProbe(name string) time.Time {
+	endpoint, err := r.Get(name)
+	if err != nil || endpoint.Breaker == nil {
+		return time.Time{}
+	}
+	return endpoint.Breaker.NextProbe()
+}
+
+func (s Selector) matchesLabels(kymaLabels map[string]string) bool {
+	if len(s.KymaLabels) == 0 {
+		return false
+	}
+	for key, value := range s.KymaLabels {
+		if kymaLabels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (s Selector) matchesGlobalAccount(globalAccount string) bool {
+	for _, prefix := range s.GlobalAccountPrefixes {
+		if prefix != "" && strings.HasPrefix(globalAccount, prefix) {
+			return true
+		}
+	}
+	return false
+}
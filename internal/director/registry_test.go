@@ -0,0 +1,101 @@
+package director
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/compass-manager/internal/director/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	euClient := &mocks.Client{}
+	usClient := &mocks.Client{}
+	trialClient := &mocks.Client{}
+
+	endpoints := []Endpoint{
+		{Name: "eu", Client: euClient, ConnectorURLPattern: "eu.kyma.cloud.sap/connector/graphql", Default: true},
+		{Name: "us", Client: usClient, Selector: Selector{KymaLabels: map[string]string{"kyma-project.io/region": "us"}}},
+		{Name: "trial", Client: trialClient, Selector: Selector{GlobalAccountPrefixes: []string{"trial-"}}},
+	}
+
+	t.Run("should select Director by Kyma labels", func(t *testing.T) {
+		// given
+		registry, err := NewRegistry(endpoints...)
+		require.NoError(t, err)
+
+		// when
+		name := registry.SelectDirector(map[string]string{"kyma-project.io/region": "us"}, "trial-account")
+
+		// then
+		assert.Equal(t, "us", name)
+	})
+
+	t.Run("should select Director by global account prefix", func(t *testing.T) {
+		// given
+		registry, err := NewRegistry(endpoints...)
+		require.NoError(t, err)
+
+		// when
+		name := registry.SelectDirector(map[string]string{"kyma-project.io/region": "eu"}, "trial-account")
+
+		// then
+		assert.Equal(t, "trial", name)
+	})
+
+	t.Run("should select default Director when no selector matches", func(t *testing.T) {
+		// given
+		registry, err := NewRegistry(endpoints...)
+		require.NoError(t, err)
+
+		// when
+		name := registry.SelectDirector(nil, globalAccountValue)
+
+		// then
+		assert.Equal(t, "eu", name)
+	})
+
+	t.Run("should get Director by name and default Director for empty name", func(t *testing.T) {
+		// given
+		registry, err := NewRegistry(endpoints...)
+		require.NoError(t, err)
+
+		// when
+		us, usErr := registry.Get("us")
+		def, defErr := registry.Get("")
+		_, unknownErr := registry.Get("unknown")
+
+		// then
+		require.NoError(t, usErr)
+		assert.Same(t, usClient, us.Client)
+		require.NoError(t, defErr)
+		assert.Same(t, euClient, def.Client)
+		assert.Equal(t, "eu.kyma.cloud.sap/connector/graphql", def.ConnectorURLPattern)
+		require.Error(t, unknownErr)
+	})
+
+	t.Run("should report next probe of the Director circuit breaker", func(t *testing.T) {
+		// given
+		breaker := NewCircuitBreaker(1, time.Minute, nil)
+		breaker.RecordFailure()
+		registry, err := NewRegistry(Endpoint{Name: "eu", Client: euClient, Breaker: breaker})
+		require.NoError(t, err)
+
+		// then
+		assert.False(t, registry.NextProbe("eu").IsZero())
+		assert.True(t, registry.NextProbe("unknown").IsZero())
+	})
+
+	t.Run("should reject invalid configuration", func(t *testing.T) {
+		_, noEndpointsErr := NewRegistry()
+		_, duplicateErr := NewRegistry(Endpoint{Name: "eu", Default: true}, Endpoint{Name: "eu"})
+		_, noDefaultErr := NewRegistry(Endpoint{Name: "eu"}, Endpoint{Name: "us"})
+		_, twoDefaultsErr := NewRegistry(Endpoint{Name: "eu", Default: true}, Endpoint{Name: "us", Default: true})
+
+		require.Error(t, noEndpointsErr)
+		require.Error(t, duplicateErr)
+		require.Error(t, noDefaultErr)
+		require.Error(t, twoDefaultsErr)
+	})
+}
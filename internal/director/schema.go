@@ -0,0 +1,255 @@
+package director
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql/introspection"
+	"github.com/kyma-incubator/compass/components/director/pkg/graphql"
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/pkg/errors"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// builtInScalars are defined by the gqlparser prelude and must not be redefined
+var builtInScalars = map[string]bool{"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true} //nolint:gochecknoglobals
+
+type introspectionResponse struct {
+	Schema introspectionSchema `json:"__schema"`
+}
+
+type introspectionSchema struct {
+	QueryType        *introspectionTypeRef `json:"queryType"`
+	MutationType     *introspectionTypeRef `json:"mutationType"`
+	SubscriptionType *introspectionTypeRef `json:"subscriptionType"`
+	Types            []introspectionType   `json:"types"`
+}
+
+type introspectionType struct {
+	Kind          string                    `json:"kind"`
+	Name          string                    `json:"name"`
+	Fields        []introspectionField      `json:"fields"`
+	InputFields   []introspectionInputValue `json:"inputFields"`
+	Interfaces    []introspectionTypeRef    `json:"interfaces"`
+	EnumValues    []introspectionEnumValue  `json:"enumValues"`
+	PossibleTypes []introspectionTypeRef    `json:"possibleTypes"`
+}
+
+type introspectionField struct {
+	Name string                    `json:"name"`
+	Args []introspectionInputValue `json:"args"`
+	Type introspectionTypeRef      `json:"type"`
+}
+
+type introspectionInputValue struct {
+	Name         string               `json:"name"`
+	Type         introspectionTypeRef `json:"type"`
+	DefaultValue *string              `json:"defaultValue"`
+}
+
+type introspectionEnumValue struct {
+	Name string `json:"name"`
+}
+
+type introspectionTypeRef struct {
+	Kind   string                `json:"kind"`
+	Name   string                `json:"name"`
+	OfType *introspectionTypeRef `json:"ofType"`
+}
+
+func (t introspectionTypeRef) String() string {
+	switch t.Kind {
+	case "NON_NULL":
+		if t.OfType != nil {
+			return t.OfType.String() + "!"
+		}
+	case "LIST":
+		if t.OfType != nil {
+			return "[" + t.OfType.String() + "]"
+		}
+	}
+	return t.Name
+}
+
+// schemaOperation is an operation sent to Director, rendered with sample arguments
+type schemaOperation struct {
+	name  string
+	query string
+}
+
+// ValidateSchema fetches the schema served by Director and checks that every operation sent by the client is valid against it
+func (cc *directorClient) ValidateSchema() apperrors.AppError {
+	var response introspectionResponse
+	err := cc.executeDirectorGraphQLCall(introspection.Query, "", &response, false)
+	if err != nil {
+		return err.Append("Failed to fetch Director schema")
+	}
+
+	schema, schemaErr := response.Schema.load()
+	if schemaErr != nil {
+		return apperrors.Internalf("Failed to load Director schema: %s", schemaErr.Error()).SetComponent(apperrors.ErrCompassDirector).SetReason(apperrors.ErrDirectorSchemaIncompatible)
+	}
+
+	operations, opErr := cc.schemaOperations()
+	if opErr != nil {
+		return apperrors.Internalf("Failed to create Director operations: %s", opErr.Error()).SetComponent(apperrors.ErrCompassDirectorClient).SetReason(apperrors.ErrDirectorClientGraphqlizer)
+	}
+
+	if diff := validateOperations(schema, operations); len(diff) > 0 {
+		return apperrors.Internalf("Director schema is incompatible with the client:\n%s", strings.Join(diff, "\n")).SetComponent(apperrors.ErrCompassDirector).SetReason(apperrors.ErrDirectorSchemaIncompatible)
+	}
+
+	return nil
+}
+
+func (cc *directorClient) schemaOperations() ([]schemaOperation, error) {
+	const sampleID = "00000000-0000-0000-0000-000000000000"
+
+	description := "description"
+	runtimeInput, err := cc.graphqlizer.RuntimeRegisterInputToGQL(graphql.RuntimeRegisterInput{
+		Name:        "name",
+		Description: &description,
+		Labels:      graphql.Labels{"label": "value"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	runtimeContextInput, err := cc.graphqlizer.RuntimeContextInputToGQL(graphql.RuntimeContextInput{
+		Key:   RuntimeContextSubaccountKey,
+		Value: sampleID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []schemaOperation{
+		{name: "registerRuntime", query: cc.queryProvider.createRuntimeMutation(runtimeInput)},
+		{name: "runtime", query: cc.queryProvider.getRuntimeQuery(sampleID)},
+		{name: "unregisterRuntime", query: cc.queryProvider.deleteRuntimeMutation(sampleID)},
+		{name: "requestOneTimeTokenForRuntime", query: cc.queryProvider.requestOneTimeTokenMutation(sampleID)},
+		{name: "registerRuntimeContext", query: cc.queryProvider.createRuntimeContextMutation(sampleID, runtimeContextInput)},
+		{name: "unregisterRuntimeContext", query: cc.queryProvider.deleteRuntimeContextMutation(sampleID)},
+		{name: "assignFormation", query: cc.queryProvider.assignFormationMutation(sampleID, "formation")},
+		{name: "unassignFormation", query: cc.queryProvider.unassignFormationMutation(sampleID, "formation")},
+		{name: "formationsForObject", query: cc.queryProvider.runtimeFormationsQuery(sampleID)},
+		{name: "applicationsForRuntime", query: cc.queryProvider.runtimeApplicationsQuery(sampleID, RuntimeApplicationsLimit)},
+		{name: "runtimes", query: cc.queryProvider.runtimesQuery("label", "value", RuntimesPageSize, "cursor")},
+		{name: "batch query", query: cc.queryProvider.batchDocument("query", []string{
+			cc.queryProvider.runtimeField("op0", sampleID),
+		})},
+		{name: "batch mutation", query: cc.queryProvider.batchDocument("mutation", []string{
+			cc.queryProvider.registerRuntimeField("op0", runtimeInput),
+			cc.queryProvider.unregisterRuntimeField("op1", sampleID),
+			cc.queryProvider.requestOneTimeTokenField("op2", sampleID),
+		})},
+	}, nil
+}
+
+// validateOperations returns one line for every problem found in the operations, prefixed with the operation name
+func validateOperations(schema *ast.Schema, operations []schemaOperation) []string {
+	var diff []string
+	for _, operation := range operations {
+		_, errs := gqlparser.LoadQueryWithRules(schema, operation.query, nil)
+		for _, err := range errs {
+			diff = append(diff, fmt.Sprintf("%s: %s", operation.name, err.Message))
+		}
+	}
+	return diff
+}
+
+// load converts the introspection result to SDL, as gqlparser builds schemas from SDL only
+func (s introspectionSchema) load() (*ast.Schema, error) {
+	if s.QueryType == nil {
+		return nil, errors.New("introspection result has no query type")
+	}
+
+	var sdl strings.Builder
+	sdl.WriteString("schema {\n")
+	fmt.Fprintf(&sdl, "  query: %s\n", s.QueryType.Name)
+	if s.MutationType != nil {
+		fmt.Fprintf(&sdl, "  mutation: %s\n", s.MutationType.Name)
+	}
+	if s.SubscriptionType != nil {
+		fmt.Fprintf(&sdl, "  subscription: %s\n", s.SubscriptionType.Name)
+	}
+	sdl.WriteString("}\n")
+
+	for _, t := range s.Types {
+		if strings.HasPrefix(t.Name, "__") || builtInScalars[t.Name] {
+			continue
+		}
+		writeType(&sdl, t)
+	}
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: "director", Input: sdl.String()})
+	if err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func writeType(sdl *strings.Builder, t introspectionType) {
+	switch t.Kind {
+	case "SCALAR":
+		fmt.Fprintf(sdl, "scalar %s\n", t.Name)
+	case "OBJECT", "INTERFACE":
+		keyword := "type"
+		if t.Kind == "INTERFACE" {
+			keyword = "interface"
+		}
+		fmt.Fprintf(sdl, "%s %s", keyword, t.Name)
+		if len(t.Interfaces) > 0 {
+			sdl.WriteString(" implements " + joinTypeNames(t.Interfaces, " & "))
+		}
+		sdl.WriteString(" {\n")
+		for _, field := range t.Fields {
+			fmt.Fprintf(sdl, "  %s%s: %s\n", field.Name, formatArguments(field.Args), field.Type)
+		}
+		sdl.WriteString("}\n")
+	case "UNION":
+		fmt.Fprintf(sdl, "union %s = %s\n", t.Name, joinTypeNames(t.PossibleTypes, " | "))
+	case "ENUM":
+		fmt.Fprintf(sdl, "enum %s {\n", t.Name)
+		for _, value := range t.EnumValues {
+			fmt.Fprintf(sdl, "  %s\n", value.Name)
+		}
+		sdl.WriteString("}\n")
+	case "INPUT_OBJECT":
+		fmt.Fprintf(sdl, "input %s {\n", t.Name)
+		for _, field := range t.InputFields {
+			fmt.Fprintf(sdl, "  %s\n", formatInputValue(field))
+		}
+		sdl.WriteString("}\n")
+	}
+}
+
+func formatArguments(args []introspectionInputValue) string {
+	if len(args) == 0 {
+		return ""
+	}
+	formatted := make([]string, 0, len(args))
+	for _, arg := range args {
+		formatted = append(formatted, formatInputValue(arg))
+	}
+	return "(" + strings.Join(formatted, ", ") + ")"
+}
+
+func formatInputValue(value introspectionInputValue) string {
+	formatted := fmt.Sprintf("%s: %s", value.Name, value.Type)
+	if value.DefaultValue != nil {
+		formatted += " = " + *value.DefaultValue
+	}
+	return formatted
+}
+
+func joinTypeNames(refs []introspectionTypeRef, separator string) string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, separator)
+}
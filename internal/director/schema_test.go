@@ -0,0 +1,252 @@
+package director
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/introspection"
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/kyma-project/compass-manager/internal/director/mocks"
+	gql "github.com/kyma-project/compass-manager/internal/graphql"
+	"github.com/kyma-project/compass-manager/internal/oauth"
+	oauthmocks "github.com/kyma-project/compass-manager/internal/oauth/mocks"
+	gcli "github.com/kyma-project/compass-manager/third_party/machinebox/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectorClient_ValidateSchema(t *testing.T) {
+	t.Run("should accept schema supporting all operations", func(t *testing.T) {
+		// given
+		client := newSchemaTestClient(t, nil, directorTestSchema("token", "connectorURL"))
+
+		// when
+		err := client.ValidateSchema()
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should report every operation not supported by the schema", func(t *testing.T) {
+		// given
+		client := newSchemaTestClient(t, nil, directorTestSchema("token", "connectorUrl"))
+
+		// when
+		err := client.ValidateSchema()
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, apperrors.ErrDirectorSchemaIncompatible, err.Reason())
+		assert.Contains(t, err.Error(), `requestOneTimeTokenForRuntime: Cannot query field "connectorURL" on type "OneTimeTokenForRuntime"`)
+		assert.NotContains(t, err.Error(), "registerRuntime:")
+	})
+
+	t.Run("should return error when schema cannot be fetched", func(t *testing.T) {
+		// given
+		client := newSchemaTestClient(t, errors.New("connection refused"), introspectionSchema{})
+
+		// when
+		err := client.ValidateSchema()
+
+		// then
+		require.Error(t, err)
+		assert.NotEqual(t, apperrors.ErrDirectorSchemaIncompatible, err.Reason())
+	})
+}
+
+func TestSchemaCheck(t *testing.T) {
+	t.Run("should retry until Director responds and then report the result", func(t *testing.T) {
+		// given
+		client := &mocks.Client{}
+		client.On("ValidateSchema").Return(apperrors.Internal("connection refused")).Once()
+		client.On("ValidateSchema").Return(nil).Once()
+		check := NewSchemaCheck(client, time.Millisecond)
+		require.Error(t, check.Check(nil))
+
+		// when
+		err := check.Start(context.Background())
+
+		// then
+		require.NoError(t, err)
+		require.NoError(t, check.Check(nil))
+		client.AssertExpectations(t)
+	})
+
+	t.Run("should fail readiness while schema is incompatible", func(t *testing.T) {
+		// given
+		var checks atomic.Int32
+		client := &mocks.Client{}
+		client.On("ValidateSchema").Return(apperrors.Internal("field missing").SetReason(apperrors.ErrDirectorSchemaIncompatible)).Run(func(mock.Arguments) {
+			checks.Add(1)
+		})
+		check := NewSchemaCheck(client, time.Millisecond)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+
+		// when
+		go func() {
+			done <- check.Start(ctx)
+		}()
+
+		// then
+		require.Eventually(t, func() bool {
+			return checks.Load() > 1
+		}, time.Second, time.Millisecond)
+		require.ErrorContains(t, check.Check(nil), "field missing")
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	t.Run("should pass readiness once schema becomes compatible", func(t *testing.T) {
+		// given
+		client := &mocks.Client{}
+		client.On("ValidateSchema").Return(apperrors.Internal("field missing").SetReason(apperrors.ErrDirectorSchemaIncompatible)).Twice()
+		client.On("ValidateSchema").Return(nil).Once()
+		check := NewSchemaCheck(client, time.Millisecond)
+
+		// when
+		err := check.Start(context.Background())
+
+		// then
+		require.NoError(t, err)
+		require.NoError(t, check.Check(nil))
+		client.AssertExpectations(t)
+	})
+}
+
+func newSchemaTestClient(t *testing.T, gqlErr error, schema introspectionSchema) Client {
+	expectedRequest := gcli.NewRequest(introspection.Query)
+	expectedRequest.Header.Set(AuthorizationHeader, "Bearer "+validTokenValue)
+
+	gqlClient := gql.NewQueryAssertClient(t, gqlErr, []*gcli.Request{expectedRequest}, func(t *testing.T, r interface{}) {
+		response, ok := r.(*introspectionResponse)
+		require.True(t, ok)
+		response.Schema = schema
+	})
+
+	oauthClient := &oauthmocks.Client{}
+	oauthClient.On("GetAuthorizationToken").Return(oauth.Token{AccessToken: validTokenValue, Expiration: futureExpirationTime}, nil)
+
+	return NewDirectorClient(gqlClient, oauthClient)
+}
+
+// directorTestSchema returns the part of the Director schema used by the client
+func directorTestSchema(tokenFields ...string) introspectionSchema {
+	named := func(kind, name string) introspectionTypeRef {
+		return introspectionTypeRef{Kind: kind, Name: name}
+	}
+	nonNull := func(ref introspectionTypeRef) introspectionTypeRef {
+		return introspectionTypeRef{Kind: "NON_NULL", OfType: &ref}
+	}
+	list := func(ref introspectionTypeRef) introspectionTypeRef {
+		return introspectionTypeRef{Kind: "LIST", OfType: &ref}
+	}
+	idArg := []introspectionInputValue{{Name: "id", Type: nonNull(named("SCALAR", "ID"))}}
+	runtimeFields := []introspectionField{
+		{Name: "id", Type: nonNull(named("SCALAR", "ID"))},
+		{Name: "name", Type: nonNull(named("SCALAR", "String"))},
+		{Name: "description", Type: named("SCALAR", "String")},
+		{Name: "labels", Type: named("SCALAR", "Labels")},
+	}
+	formationArgs := []introspectionInputValue{
+		{Name: "objectID", Type: nonNull(named("SCALAR", "ID"))},
+		{Name: "objectType", Type: nonNull(named("ENUM", "FormationObjectType"))},
+		{Name: "formation", Type: nonNull(named("INPUT_OBJECT", "FormationInput"))},
+	}
+	var tokenTypeFields []introspectionField
+	for _, field := range tokenFields {
+		tokenTypeFields = append(tokenTypeFields, introspectionField{Name: field, Type: nonNull(named("SCALAR", "String"))})
+	}
+
+	return introspectionSchema{
+		QueryType:    &introspectionTypeRef{Name: "Query"},
+		MutationType: &introspectionTypeRef{Name: "Mutation"},
+		Types: []introspectionType{
+			{Kind: "SCALAR", Name: "String"},
+			{Kind: "SCALAR", Name: "ID"},
+			{Kind: "SCALAR", Name: "Labels"},
+			{Kind: "SCALAR", Name: "Int"},
+			{Kind: "SCALAR", Name: "PageCursor"},
+			{Kind: "SCALAR", Name: "Boolean"},
+			{Kind: "OBJECT", Name: "Query", Fields: []introspectionField{
+				{Name: "runtime", Args: idArg, Type: named("OBJECT", "RuntimeExt")},
+				{Name: "formationsForObject", Args: []introspectionInputValue{{Name: "objectID", Type: nonNull(named("SCALAR", "String"))}}, Type: nonNull(list(nonNull(named("OBJECT", "Formation"))))},
+				{Name: "applicationsForRuntime", Args: []introspectionInputValue{
+					{Name: "runtimeID", Type: nonNull(named("SCALAR", "ID"))},
+					{Name: "first", Type: named("SCALAR", "Int")},
+					{Name: "after", Type: named("SCALAR", "PageCursor")},
+				}, Type: nonNull(named("OBJECT", "ApplicationPage"))},
+				{Name: "runtimes", Args: []introspectionInputValue{
+					{Name: "filter", Type: list(nonNull(named("INPUT_OBJECT", "LabelFilter")))},
+					{Name: "first", Type: named("SCALAR", "Int")},
+					{Name: "after", Type: named("SCALAR", "PageCursor")},
+				}, Type: nonNull(named("OBJECT", "RuntimePage"))},
+			}},
+			{Kind: "OBJECT", Name: "Mutation", Fields: []introspectionField{
+				{Name: "registerRuntime", Args: []introspectionInputValue{{Name: "in", Type: nonNull(named("INPUT_OBJECT", "RuntimeRegisterInput"))}}, Type: nonNull(named("OBJECT", "Runtime"))},
+				{Name: "unregisterRuntime", Args: idArg, Type: nonNull(named("OBJECT", "Runtime"))},
+				{Name: "requestOneTimeTokenForRuntime", Args: idArg, Type: nonNull(named("OBJECT", "OneTimeTokenForRuntime"))},
+				{Name: "registerRuntimeContext", Args: []introspectionInputValue{
+					{Name: "runtimeID", Type: nonNull(named("SCALAR", "ID"))},
+					{Name: "in", Type: nonNull(named("INPUT_OBJECT", "RuntimeContextInput"))},
+				}, Type: nonNull(named("OBJECT", "RuntimeContext"))},
+				{Name: "unregisterRuntimeContext", Args: idArg, Type: nonNull(named("OBJECT", "RuntimeContext"))},
+				{Name: "assignFormation", Args: formationArgs, Type: nonNull(named("OBJECT", "Formation"))},
+				{Name: "unassignFormation", Args: formationArgs, Type: nonNull(named("OBJECT", "Formation"))},
+			}},
+			{Kind: "OBJECT", Name: "Runtime", Fields: runtimeFields},
+			{Kind: "OBJECT", Name: "RuntimeExt", Fields: runtimeFields},
+			{Kind: "OBJECT", Name: "OneTimeTokenForRuntime", Fields: tokenTypeFields},
+			{Kind: "OBJECT", Name: "RuntimeContext", Fields: []introspectionField{
+				{Name: "id", Type: nonNull(named("SCALAR", "ID"))},
+				{Name: "key", Type: nonNull(named("SCALAR", "String"))},
+				{Name: "value", Type: nonNull(named("SCALAR", "String"))},
+			}},
+			{Kind: "INPUT_OBJECT", Name: "RuntimeRegisterInput", InputFields: []introspectionInputValue{
+				{Name: "name", Type: nonNull(named("SCALAR", "String"))},
+				{Name: "description", Type: named("SCALAR", "String")},
+				{Name: "labels", Type: named("SCALAR", "Labels")},
+			}},
+			{Kind: "OBJECT", Name: "Application", Fields: []introspectionField{
+				{Name: "id", Type: nonNull(named("SCALAR", "ID"))},
+				{Name: "name", Type: nonNull(named("SCALAR", "String"))},
+			}},
+			{Kind: "OBJECT", Name: "ApplicationPage", Fields: []introspectionField{
+				{Name: "data", Type: nonNull(list(nonNull(named("OBJECT", "Application"))))},
+				{Name: "totalCount", Type: nonNull(named("SCALAR", "Int"))},
+			}},
+			{Kind: "OBJECT", Name: "RuntimePage", Fields: []introspectionField{
+				{Name: "data", Type: nonNull(list(nonNull(named("OBJECT", "Runtime"))))},
+				{Name: "pageInfo", Type: nonNull(named("OBJECT", "PageInfo"))},
+				{Name: "totalCount", Type: nonNull(named("SCALAR", "Int"))},
+			}},
+			{Kind: "OBJECT", Name: "PageInfo", Fields: []introspectionField{
+				{Name: "startCursor", Type: nonNull(named("SCALAR", "PageCursor"))},
+				{Name: "endCursor", Type: nonNull(named("SCALAR", "PageCursor"))},
+				{Name: "hasNextPage", Type: nonNull(named("SCALAR", "Boolean"))},
+			}},
+			{Kind: "INPUT_OBJECT", Name: "LabelFilter", InputFields: []introspectionInputValue{
+				{Name: "key", Type: nonNull(named("SCALAR", "String"))},
+				{Name: "query", Type: named("SCALAR", "String")},
+			}},
+			{Kind: "OBJECT", Name: "Formation", Fields: []introspectionField{
+				{Name: "id", Type: nonNull(named("SCALAR", "ID"))},
+				{Name: "name", Type: nonNull(named("SCALAR", "String"))},
+			}},
+			{Kind: "ENUM", Name: "FormationObjectType", EnumValues: []introspectionEnumValue{{Name: "APPLICATION"}, {Name: "RUNTIME"}}},
+			{Kind: "INPUT_OBJECT", Name: "FormationInput", InputFields: []introspectionInputValue{
+				{Name: "name", Type: nonNull(named("SCALAR", "String"))},
+				{Name: "templateName", Type: named("SCALAR", "String")},
+			}},
+			{Kind: "INPUT_OBJECT", Name: "RuntimeContextInput", InputFields: []introspectionInputValue{
+				{Name: "key", Type: nonNull(named("SCALAR", "String"))},
+				{Name: "value", Type: nonNull(named("SCALAR", "String"))},
+			}},
+			{Kind: "OBJECT", Name: "__Schema"},
+		},
+	}
+}
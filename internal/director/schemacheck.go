@@ -0,0 +1,77 @@
+package director
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// SchemaCheck verifies at startup that the Director schema supports all operations sent by the client.
+// It's retried until the schema is compatible, so that readiness recovers once Director is fixed or rolled back,
+// and reports the result as a readiness check.
+type SchemaCheck struct {
+	client        Client
+	retryInterval time.Duration
+
+	mu  sync.Mutex
+	err error
+}
+
+func NewSchemaCheck(client Client, retryInterval time.Duration) *SchemaCheck {
+	return &SchemaCheck{
+		client:        client,
+		retryInterval: retryInterval,
+		err:           errors.New("Director schema was not checked yet"),
+	}
+}
+
+// Start runs the check, it implements manager.Runnable
+func (c *SchemaCheck) Start(ctx context.Context) error {
+	for {
+		err := c.client.ValidateSchema()
+		if err == nil {
+			log.Infof("Director schema is compatible with the client")
+			c.setResult(nil)
+			return nil
+		}
+
+		if err.Reason() == apperrors.ErrDirectorSchemaIncompatible {
+			log.Errorf("Director schema check failed, next attempt in %s: %s", c.retryInterval, err.Error())
+			c.setResult(err)
+		} else {
+			log.Warnf("Failed to check Director schema, next attempt in %s: %s", c.retryInterval, err.Error())
+			c.setResult(errors.Wrap(err, "Director schema could not be checked"))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(c.retryInterval):
+		}
+	}
+}
+
+// NeedLeaderElection makes the check run on every replica, as each of them calls Director
+func (c *SchemaCheck) NeedLeaderElection() bool {
+	return false
+}
+
+// Check implements healthz.Checker
+func (c *SchemaCheck) Check(_ *http.Request) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+func (c *SchemaCheck) setResult(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.err = err
+}
@@ -0,0 +1,167 @@
+package director
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"golang.org/x/time/rate"
+)
+
+const (
+	ThrottleLimitGlobal        = "global"
+	ThrottleLimitGlobalAccount = "global_account"
+	ThrottleLimitMutations     = "concurrent_mutations"
+
+	defaultMaxThrottleWait = 30 * time.Second
+)
+
+// ThrottleConfig describes the request budget toward Director. Zero values disable the given limit.
+type ThrottleConfig struct {
+	RequestsPerSecond        float64
+	Burst                    int
+	AccountRequestsPerSecond float64
+	AccountBurst             int
+	MaxConcurrentMutations   int
+}
+
+// Throttle limits the rate of requests sent to Director, globally and per global account,
+// and caps the number of mutations executed at the same time
+type Throttle struct {
+	global    *rate.Limiter
+	mutations chan struct{}
+	maxWait   time.Duration
+	onWait    func(limit string, waited time.Duration)
+
+	accountRate  rate.Limit
+	accountBurst int
+	mu           sync.Mutex
+	accounts     map[string]*accountLimit
+	lastEviction time.Time
+	now          func() time.Time
+}
+
+// accountLimit is the limiter of a global account with the time it was used last
+type accountLimit struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// NewThrottle creates a Throttle. The onWait callback is optional and is called with the time spent waiting for every limit.
+func NewThrottle(config ThrottleConfig, onWait func(limit string, waited time.Duration)) *Throttle {
+	t := &Throttle{
+		maxWait:      defaultMaxThrottleWait,
+		onWait:       onWait,
+		accountRate:  rate.Limit(config.AccountRequestsPerSecond),
+		accountBurst: max(config.AccountBurst, 1),
+		accounts:     make(map[string]*accountLimit),
+		now:          time.Now,
+	}
+	if config.RequestsPerSecond > 0 {
+		t.global = rate.NewLimiter(rate.Limit(config.RequestsPerSecond), max(config.Burst, 1))
+	}
+	if config.MaxConcurrentMutations > 0 {
+		t.mutations = make(chan struct{}, config.MaxConcurrentMutations)
+	}
+	return t
+}
+
+// Acquire blocks until the request fits in the budget. The returned function must be called once the request is finished.
+func (t *Throttle) Acquire(globalAccount string, mutation bool) (func(), apperrors.AppError) {
+	ctx, cancel := context.WithTimeout(context.Background(), t.maxWait)
+	defer cancel()
+
+	if err := t.wait(ctx, ThrottleLimitGlobal, t.global); err != nil {
+		return nil, err
+	}
+	if err := t.wait(ctx, ThrottleLimitGlobalAccount, t.accountLimiter(globalAccount)); err != nil {
+		return nil, err.Append("Global account %s", globalAccount)
+	}
+
+	if !mutation || t.mutations == nil {
+		return func() {}, nil
+	}
+
+	start := time.Now()
+	select {
+	case t.mutations <- struct{}{}:
+		t.observe(ThrottleLimitMutations, time.Since(start))
+		return func() { <-t.mutations }, nil
+	case <-ctx.Done():
+		t.observe(ThrottleLimitMutations, time.Since(start))
+		return nil, throttledError(ThrottleLimitMutations)
+	}
+}
+
+func (t *Throttle) wait(ctx context.Context, limit string, limiter *rate.Limiter) apperrors.AppError {
+	if limiter == nil {
+		return nil
+	}
+
+	start := time.Now()
+	err := limiter.Wait(ctx)
+	t.observe(limit, time.Since(start))
+	if err != nil {
+		return throttledError(limit)
+	}
+	return nil
+}
+
+func (t *Throttle) accountLimiter(globalAccount string) *rate.Limiter {
+	if t.accountRate <= 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.evictIdleAccounts(now)
+
+	account, ok := t.accounts[globalAccount]
+	if !ok {
+		account = &accountLimit{limiter: rate.NewLimiter(t.accountRate, t.accountBurst)}
+		t.accounts[globalAccount] = account
+	}
+	account.lastUsed = now
+	return account.limiter
+}
+
+// evictIdleAccounts drops limiters of global accounts which weren't used for long enough to refill completely,
+// as they are equivalent to new ones, so that the number of limiters doesn't grow with the number of global accounts
+func (t *Throttle) evictIdleAccounts(now time.Time) {
+	idle := t.accountIdleTimeout()
+	if now.Sub(t.lastEviction) < idle {
+		return
+	}
+	t.lastEviction = now
+
+	for globalAccount, account := range t.accounts {
+		if now.Sub(account.lastUsed) >= idle {
+			delete(t.accounts, globalAccount)
+		}
+	}
+}
+
+// accountIdleTimeout is the time in which a limiter refills completely, counted from the latest request it may have reserved
+func (t *Throttle) accountIdleTimeout() time.Duration {
+	refill := time.Duration(float64(t.accountBurst) / float64(t.accountRate) * float64(time.Second))
+	return refill + t.maxWait
+}
+
+func (t *Throttle) observe(limit string, waited time.Duration) {
+	if t.onWait != nil {
+		t.onWait(limit, waited)
+	}
+}
+
+func throttledError(limit string) apperrors.AppError {
+	return apperrors.Unavailable("Director request exceeded the %s limit and was not sent", limit).
+		SetComponent(apperrors.ErrCompassDirectorClient).SetReason(apperrors.ErrDirectorThrottled)
+}
+
+func isMutation(query string) bool {
+	return strings.HasPrefix(strings.TrimSpace(query), "mutation")
+}
@@ -0,0 +1,122 @@
+package director
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottle(t *testing.T) {
+	t.Run("should not limit requests when limits are disabled", func(t *testing.T) {
+		// given
+		throttle := NewThrottle(ThrottleConfig{}, nil)
+
+		// when
+		for i := 0; i < 100; i++ {
+			release, err := throttle.Acquire(globalAccountValue, true)
+
+			// then
+			require.NoError(t, err)
+			release()
+		}
+	})
+
+	t.Run("should limit requests per global account independently", func(t *testing.T) {
+		// given
+		throttle := NewThrottle(ThrottleConfig{AccountRequestsPerSecond: 0.001, AccountBurst: 1}, nil)
+		throttle.maxWait = 10 * time.Millisecond
+
+		// when
+		_, firstErr := throttle.Acquire("account-a", false)
+		_, secondErr := throttle.Acquire("account-a", false)
+		_, otherAccountErr := throttle.Acquire("account-b", false)
+
+		// then
+		require.NoError(t, firstErr)
+		require.Error(t, secondErr)
+		assert.Equal(t, apperrors.ErrDirectorThrottled, secondErr.Reason())
+		assert.Equal(t, apperrors.CodeUnavailable, secondErr.Code())
+		require.NoError(t, otherAccountErr)
+	})
+
+	t.Run("should evict limiters of idle global accounts", func(t *testing.T) {
+		// given
+		now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		throttle := NewThrottle(ThrottleConfig{AccountRequestsPerSecond: 1, AccountBurst: 10}, nil)
+		throttle.now = func() time.Time { return now }
+
+		_, err := throttle.Acquire("idle-account", false)
+		require.NoError(t, err)
+		now = now.Add(30 * time.Second)
+		_, err = throttle.Acquire("active-account", false)
+		require.NoError(t, err)
+
+		// when
+		now = now.Add(20 * time.Second)
+		_, err = throttle.Acquire("active-account", false)
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, throttle.accounts, 1)
+		assert.Contains(t, throttle.accounts, "active-account")
+	})
+
+	t.Run("should limit requests globally", func(t *testing.T) {
+		// given
+		throttle := NewThrottle(ThrottleConfig{RequestsPerSecond: 0.001, Burst: 1}, nil)
+		throttle.maxWait = 10 * time.Millisecond
+
+		// when
+		_, firstErr := throttle.Acquire("account-a", false)
+		_, secondErr := throttle.Acquire("account-b", false)
+
+		// then
+		require.NoError(t, firstErr)
+		require.Error(t, secondErr)
+		assert.Equal(t, apperrors.ErrDirectorThrottled, secondErr.Reason())
+	})
+
+	t.Run("should cap concurrent mutations and report waiting time", func(t *testing.T) {
+		// given
+		var mu sync.Mutex
+		waited := map[string]time.Duration{}
+		throttle := NewThrottle(ThrottleConfig{MaxConcurrentMutations: 1}, func(limit string, d time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			waited[limit] += d
+		})
+
+		release, err := throttle.Acquire(globalAccountValue, true)
+		require.NoError(t, err)
+
+		// when
+		_, queryErr := throttle.Acquire(globalAccountValue, false)
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			release()
+		}()
+		secondRelease, mutationErr := throttle.Acquire(globalAccountValue, true)
+
+		// then
+		require.NoError(t, queryErr)
+		require.NoError(t, mutationErr)
+		secondRelease()
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.GreaterOrEqual(t, waited[ThrottleLimitMutations], 15*time.Millisecond)
+	})
+}
+
+func TestIsMutation(t *testing.T) {
+	qp := queryProvider{}
+
+	assert.True(t, isMutation(qp.createRuntimeMutation("{}")))
+	assert.True(t, isMutation(qp.deleteRuntimeMutation(compassTestingID)))
+	assert.True(t, isMutation(qp.requestOneTimeTokenMutation(compassTestingID)))
+	assert.False(t, isMutation(qp.getRuntimeQuery(compassTestingID)))
+}
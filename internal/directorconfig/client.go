@@ -0,0 +1,97 @@
+package directorconfig
+
+import (
+	"crypto/tls"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/compass-manager/internal/certificate"
+	"github.com/kyma-project/compass-manager/internal/director"
+	"github.com/kyma-project/compass-manager/internal/graphql"
+	"github.com/kyma-project/compass-manager/internal/oauth"
+	"github.com/pkg/errors"
+)
+
+const httpTimeout = 30 * time.Second
+
+// NewClient creates a client of the Director authenticating with its client certificate, or with the OAuth credentials read from DirectorOAuthPath.
+// Credentials kept in the Secret set in DirectorOAuthSecretName are read by the caller, and passed to NewOAuthClient.
+func NewClient(cfg Config, transport func(http.RoundTripper) http.RoundTripper, gqlOpts []graphql.Option, oauthOpts []oauth.Option, opts ...director.Option) (director.Client, error) {
+	switch cfg.DirectorAuthMode {
+	case AuthModeOAuth:
+		credentials, err := os.ReadFile(cfg.DirectorOAuthPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to open director config")
+		}
+		return NewOAuthClient(cfg, credentials, transport, gqlOpts, oauthOpts, opts...)
+	case AuthModeMTLS:
+		return newMTLSClient(cfg, gqlOpts, opts...)
+	default:
+		return nil, errors.Errorf("unknown Director auth mode %q, expected %q or %q", cfg.DirectorAuthMode, AuthModeOAuth, AuthModeMTLS)
+	}
+}
+
+// NewOAuthClient creates a client of the Director authenticating with the OAuth credentials in the director.yaml format
+func NewOAuthClient(cfg Config, credentials []byte, transport func(http.RoundTripper) http.RoundTripper, gqlOpts []graphql.Option, oauthOpts []oauth.Option, opts ...director.Option) (director.Client, error) {
+	oauthCfg, err := oauth.ParseDirectorOAuth(credentials, oauth.AuthMethod(cfg.DirectorOAuthAuthMethod))
+	if err != nil {
+		return nil, err
+	}
+
+	gqlClient := graphql.NewGraphQLClient(cfg.DirectorURL, true, cfg.SkipDirectorCertVerification, gqlOpts...)
+	oauthClient := oauth.NewOauthClient(NewHTTPClient(cfg.SkipDirectorCertVerification, transport), oauthCfg.Data.ClientID, oauthCfg.Data.ClientSecret, oauthCfg.Data.TokensEndpoint, oauthOpts...)
+
+	return director.NewDirectorClient(gqlClient, oauthClient, opts...), nil
+}
+
+func newMTLSClient(cfg Config, gqlOpts []graphql.Option, opts ...director.Option) (director.Client, error) {
+	loader, err := certificate.NewLoader(cfg.DirectorCertPath, cfg.DirectorKeyPath, cfg.DirectorCertInterval)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to load Director client certificate")
+	}
+
+	gqlClient := graphql.NewMTLSGraphQLClient(loader.GetClientCertificate, cfg.DirectorURL, true, cfg.SkipDirectorCertVerification, gqlOpts...)
+
+	return director.NewDirectorClient(gqlClient, nil, opts...), nil
+}
+
+// NewOAuthOptions returns the options of the OAuth client credentials grant of the config
+func NewOAuthOptions(cfg Config) ([]oauth.Option, error) {
+	opts := []oauth.Option{
+		oauth.WithScopes(strings.Fields(cfg.DirectorOAuthScopes)...),
+		oauth.WithAudience(cfg.DirectorOAuthAudience),
+		oauth.WithResource(cfg.DirectorOAuthResource),
+	}
+
+	switch oauth.AuthMethod(cfg.DirectorOAuthAuthMethod) {
+	case oauth.AuthMethodClientSecretBasic:
+	case oauth.AuthMethodClientSecretPost:
+		opts = append(opts, oauth.WithClientSecretPost())
+	case oauth.AuthMethodPrivateKeyJWT:
+		key, err := oauth.LoadPrivateKey(cfg.DirectorOAuthPrivateKeyPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to load private key for Director OAuth client assertion")
+		}
+		opts = append(opts, oauth.WithPrivateKeyJWT(key, cfg.DirectorOAuthKeyID))
+	default:
+		return nil, errors.Errorf("unknown Director OAuth auth method %q", cfg.DirectorOAuthAuthMethod)
+	}
+
+	return opts, nil
+}
+
+// NewHTTPClient creates the client of the tokens endpoint, its transport is wrapped with transport unless it's nil
+func NewHTTPClient(skipCertVerification bool, transport func(http.RoundTripper) http.RoundTripper) *http.Client {
+	var roundTripper http.RoundTripper = &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: skipCertVerification}, //nolint:gosec
+	}
+	if transport != nil {
+		roundTripper = transport(roundTripper)
+	}
+	return &http.Client{
+		Transport: roundTripper,
+		Timeout:   httpTimeout,
+	}
+}
@@ -0,0 +1,134 @@
+// Package directorconfig reads the configuration of Compass Directors, shared by compass-manager and cmctl, and creates their clients.
+package directorconfig
+
+import (
+	"os"
+	"time"
+
+	"github.com/kyma-project/compass-manager/internal/director"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Config is the Director configured with the envs, Directors listed in the file set in DirectorsConfigPath fall back to it
+type Config struct {
+	SkipDirectorCertVerification bool   `envconfig:"APP_SKIPDIRECTORCERTVERIFICATION,default=false"`
+	DirectorURL                  string `envconfig:"APP_DIRECTOR_URL,default=https://compass-gateway-auth-oauth.cmp-main.dev.kyma.cloud.sap/director/graphql"`
+	DirectorOAuthPath            string `envconfig:"APP_DIRECTOR_OAUTH_PATH,default=./dev/director.yaml"`
+	ConnectorURLPattern          string `envconfig:"APP_CONNECTOR_URL_PATTERN,default=kyma.cloud.sap/connector/graphql"`
+	// DirectorAuthMode selects how compass-manager authenticates to Director: oauth or mtls
+	DirectorAuthMode     string        `envconfig:"APP_DIRECTOR_AUTH_MODE,default=oauth"`
+	DirectorCertPath     string        `envconfig:"APP_DIRECTOR_CERT_PATH,default=./dev/tls.crt"`
+	DirectorKeyPath      string        `envconfig:"APP_DIRECTOR_KEY_PATH,default=./dev/tls.key"`
+	DirectorCertInterval time.Duration `envconfig:"APP_DIRECTOR_CERT_CHECK_INTERVAL,default=1m"`
+	// DirectorOAuthSecretName enables reading OAuth credentials from a Secret, reloaded on every change, instead of DirectorOAuthPath
+	DirectorOAuthSecretName      string `envconfig:"APP_DIRECTOR_OAUTH_SECRET_NAME,optional"`
+	DirectorOAuthSecretNamespace string `envconfig:"APP_DIRECTOR_OAUTH_SECRET_NAMESPACE,default=kcp-system"`
+	DirectorOAuthSecretKey       string `envconfig:"APP_DIRECTOR_OAUTH_SECRET_KEY,default=director.yaml"`
+	// Parameters of the OAuth client credentials grant, the auth method is one of client_secret_basic, client_secret_post, private_key_jwt
+	DirectorOAuthScopes         string `envconfig:"APP_DIRECTOR_OAUTH_SCOPES,default=runtime:read runtime:write"`
+	DirectorOAuthAuthMethod     string `envconfig:"APP_DIRECTOR_OAUTH_AUTH_METHOD,default=client_secret_basic"`
+	DirectorOAuthAudience       string `envconfig:"APP_DIRECTOR_OAUTH_AUDIENCE,optional"`
+	DirectorOAuthResource       string `envconfig:"APP_DIRECTOR_OAUTH_RESOURCE,optional"`
+	DirectorOAuthPrivateKeyPath string `envconfig:"APP_DIRECTOR_OAUTH_PRIVATE_KEY_PATH,optional"`
+	DirectorOAuthKeyID          string `envconfig:"APP_DIRECTOR_OAUTH_KEY_ID,optional"`
+	// DirectorBatchSize is the maximum number of operations sent to Director in a single request by bulk flows
+	DirectorBatchSize int `envconfig:"APP_DIRECTOR_BATCH_SIZE,default=50"`
+	// DirectorsConfigPath points to a file with Directors of multiple Compass landscapes, the Director configured with the envs above is used when not set
+	DirectorsConfigPath string `envconfig:"APP_DIRECTORS_CONFIG_PATH,optional"`
+}
+
+const (
+	AuthModeOAuth = "oauth"
+	AuthModeMTLS  = "mtls"
+)
+
+// Directors is the format of the file with Directors of multiple Compass landscapes.
+// Empty fields of a Director fall back to the values configured with the envs.
+type Directors struct {
+	Directors []Director `json:"directors"`
+}
+
+type Director struct {
+	Name                  string            `json:"name"`
+	Default               bool              `json:"default"`
+	URL                   string            `json:"url"`
+	ConnectorURLPattern   string            `json:"connectorURLPattern"`
+	AuthMode              string            `json:"authMode"`
+	OAuthPath             string            `json:"oauthPath"`
+	OAuthSecretName       string            `json:"oauthSecretName"`
+	OAuthSecretNamespace  string            `json:"oauthSecretNamespace"`
+	OAuthSecretKey        string            `json:"oauthSecretKey"`
+	CertPath              string            `json:"certPath"`
+	KeyPath               string            `json:"keyPath"`
+	KymaLabels            map[string]string `json:"kymaLabels"`
+	GlobalAccountPrefixes []string          `json:"globalAccountPrefixes"`
+}
+
+// Load returns the Director configured with the envs, or all Directors listed in the file set in DirectorsConfigPath
+func Load(cfg Config) (Directors, error) {
+	if cfg.DirectorsConfigPath == "" {
+		return Directors{Directors: []Director{{}}}, nil
+	}
+
+	file, err := os.ReadFile(cfg.DirectorsConfigPath)
+	if err != nil {
+		return Directors{}, errors.Wrap(err, "Failed to open Directors config")
+	}
+
+	directors := Directors{}
+	if err := yaml.Unmarshal(file, &directors); err != nil {
+		return Directors{}, errors.Wrap(err, "Failed to unmarshal Directors config")
+	}
+	for _, directorCfg := range directors.Directors {
+		if directorCfg.Name == "" {
+			return Directors{}, errors.New("every Director in the Directors config must have a name")
+		}
+	}
+	return directors, nil
+}
+
+// Apply overrides the Director settings of the config with the non-empty fields
+func (d Director) Apply(cfg Config) Config {
+	override := func(target *string, value string) {
+		if value != "" {
+			*target = value
+		}
+	}
+	override(&cfg.DirectorURL, d.URL)
+	override(&cfg.ConnectorURLPattern, d.ConnectorURLPattern)
+	override(&cfg.DirectorAuthMode, d.AuthMode)
+	override(&cfg.DirectorCertPath, d.CertPath)
+	override(&cfg.DirectorKeyPath, d.KeyPath)
+	override(&cfg.DirectorOAuthSecretNamespace, d.OAuthSecretNamespace)
+	override(&cfg.DirectorOAuthSecretKey, d.OAuthSecretKey)
+	if d.OAuthPath != "" {
+		cfg.DirectorOAuthPath = d.OAuthPath
+		cfg.DirectorOAuthSecretName = ""
+	}
+	override(&cfg.DirectorOAuthSecretName, d.OAuthSecretName)
+	return cfg
+}
+
+// NewRegistry creates the registry of the Directors with endpoints created by newEndpoint from the config overridden by every Director
+func (d Directors) NewRegistry(cfg Config, newEndpoint func(name string, cfg Config) (director.Endpoint, error)) (*director.Registry, error) {
+	endpoints := make([]director.Endpoint, 0, len(d.Directors))
+	for _, directorCfg := range d.Directors {
+		endpoint, err := newEndpoint(directorCfg.Name, directorCfg.Apply(cfg))
+		if err != nil {
+			if directorCfg.Name == "" {
+				return nil, err
+			}
+			return nil, errors.Wrapf(err, "Failed to create Director %s", directorCfg.Name)
+		}
+		endpoint.Name = directorCfg.Name
+		endpoint.Default = directorCfg.Default
+		endpoint.Selector = director.Selector{
+			KymaLabels:            directorCfg.KymaLabels,
+			GlobalAccountPrefixes: directorCfg.GlobalAccountPrefixes,
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return director.NewRegistry(endpoints...)
+}
@@ -0,0 +1,82 @@
+package directorconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-project/compass-manager/internal/director"
+	"github.com/kyma-project/compass-manager/internal/director/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("should return the Director configured with the envs without the Directors config", func(t *testing.T) {
+		// when
+		directors, err := Load(Config{})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []Director{{}}, directors.Directors)
+	})
+
+	t.Run("should reject Director without name", func(t *testing.T) {
+		// given
+		path := writeDirectorsConfig(t, "directors:\n- name: eu\n- url: https://director.example.com\n")
+
+		// when
+		_, err := Load(Config{DirectorsConfigPath: path})
+
+		// then
+		require.ErrorContains(t, err, "must have a name")
+	})
+}
+
+func TestDirectors_NewRegistry(t *testing.T) {
+	// given
+	path := writeDirectorsConfig(t, `directors:
+- name: eu
+  default: true
+- name: us
+  url: https://us.example.com/director/graphql
+  oauthPath: ./us.yaml
+  globalAccountPrefixes: ["us-"]
+`)
+	cfg := Config{
+		DirectorsConfigPath:     path,
+		DirectorURL:             "https://eu.example.com/director/graphql",
+		DirectorAuthMode:        AuthModeOAuth,
+		DirectorOAuthSecretName: "director-credentials",
+	}
+	directors, err := Load(cfg)
+	require.NoError(t, err)
+
+	// when
+	configs := map[string]Config{}
+	registry, err := directors.NewRegistry(cfg, func(name string, cfg Config) (director.Endpoint, error) {
+		configs[name] = cfg
+		return director.Endpoint{Client: &mocks.Client{}}, nil
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "https://eu.example.com/director/graphql", configs["eu"].DirectorURL)
+	assert.Equal(t, "director-credentials", configs["eu"].DirectorOAuthSecretName)
+	assert.Equal(t, "https://us.example.com/director/graphql", configs["us"].DirectorURL)
+	assert.Equal(t, "./us.yaml", configs["us"].DirectorOAuthPath)
+	assert.Empty(t, configs["us"].DirectorOAuthSecretName, "the OAuth file of the Director replaces the Secret of the envs")
+
+	endpoint, err := registry.Get("us")
+	require.NoError(t, err)
+	assert.Equal(t, "us", endpoint.Name)
+	assert.Equal(t, "us", registry.SelectDirector(nil, "us-account"))
+	assert.Equal(t, "eu", registry.SelectDirector(nil, "other-account"))
+}
+
+func writeDirectorsConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "directors.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
@@ -13,86 +13,175 @@ import (
 )
 
 const (
-	timeout = 30 * time.Second
+	timeout      = 30 * time.Second
+	tenantHeader = "Tenant"
 )
 
 type ClientConstructor func(certificate *tls.Certificate, graphqlEndpoint string, enableLogging bool, insecureConfigFetch bool) (Client, error)
 
+// CertificateProvider returns the client certificate presented during the TLS handshake
+type CertificateProvider func(*tls.CertificateRequestInfo) (*tls.Certificate, error)
+
 //go:generate mockery --name=Client
 type Client interface {
-	Do(req *graphql.Request, res interface{}, gracefulUnregistration bool) error
+	Do(ctx context.Context, req *graphql.Request, res interface{}, gracefulUnregistration bool) error
+	// DoPartial sends a request with many fields, e.g. aliased operations, and returns the errors of all failed fields.
+	// The error is returned only when the whole request failed.
+	DoPartial(ctx context.Context, req *graphql.Request, res interface{}) ([]graphql.FieldError, error)
 }
 
-type client struct {
-	gqlClient *graphql.Client
-	logs      []string
-	logging   bool
+type kymaNameKey struct{}
+
+// WithKymaName returns a context of requests sent for the Kyma, its name is logged with the requests
+func WithKymaName(ctx context.Context, kymaName string) context.Context {
+	return context.WithValue(ctx, kymaNameKey{}, kymaName)
 }
 
-func NewGraphQLClient(graphqlEndpoint string, enableLogging bool, insecureSkipVerify bool) Client {
-	httpClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: insecureSkipVerify},
-		},
+// KymaName returns the name of the Kyma set with WithKymaName, or an empty string
+func KymaName(ctx context.Context) string {
+	kymaName, _ := ctx.Value(kymaNameKey{}).(string)
+	return kymaName
+}
+
+// Option configures the GraphQL client
+type Option func(*client)
+
+// WithTransport wraps the transport of the client, e.g. to record exchanges
+func WithTransport(wrap func(http.RoundTripper) http.RoundTripper) Option {
+	return func(c *client) {
+		c.httpClient.Transport = wrap(c.httpClient.Transport)
 	}
+}
 
-	gqlClient := graphql.NewClient(graphqlEndpoint, graphql.WithHTTPClient(httpClient))
+// WithLogger sets the logger of requests and the levels of successful and failed requests.
+// Redacted request and response bodies are logged too when the logger is at the trace level.
+func WithLogger(log *logrus.Logger, level, failureLevel logrus.Level) Option {
+	return func(c *client) {
+		c.log = log
+		c.level = level
+		c.failureLevel = failureLevel
+	}
+}
 
+type client struct {
+	gqlClient    *graphql.Client
+	httpClient   *http.Client
+	logging      bool
+	log          *logrus.Logger
+	level        logrus.Level
+	failureLevel logrus.Level
+}
+
+func NewGraphQLClient(graphqlEndpoint string, enableLogging bool, insecureSkipVerify bool, opts ...Option) Client {
+	return newClient(&tls.Config{InsecureSkipVerify: insecureSkipVerify}, graphqlEndpoint, enableLogging, opts...)
+}
+
+// NewMTLSGraphQLClient creates a client authenticating to the GraphQL endpoint with the certificate returned by certificateProvider
+func NewMTLSGraphQLClient(certificateProvider CertificateProvider, graphqlEndpoint string, enableLogging bool, insecureSkipVerify bool, opts ...Option) Client {
+	return newClient(&tls.Config{
+		InsecureSkipVerify:   insecureSkipVerify,
+		GetClientCertificate: certificateProvider,
+	}, graphqlEndpoint, enableLogging, opts...)
+}
+
+func newClient(tlsConfig *tls.Config, graphqlEndpoint string, enableLogging bool, opts ...Option) Client {
 	client := &client{
-		gqlClient: gqlClient,
-		logging:   enableLogging,
-		logs:      []string{},
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: tlsConfig,
+			},
+		},
+		logging:      enableLogging,
+		log:          logrus.StandardLogger(),
+		level:        logrus.DebugLevel,
+		failureLevel: logrus.WarnLevel,
+	}
+	for _, opt := range opts {
+		opt(client)
 	}
 
-	client.gqlClient.Log = client.addLog
+	client.httpClient.Transport = &loggingTransport{next: client.httpClient.Transport}
+	client.gqlClient = graphql.NewClient(graphqlEndpoint, graphql.WithHTTPClient(client.httpClient))
 
 	return client
 }
 
-func (c *client) Do(req *graphql.Request, res interface{}, gracefulUnregistration bool) error {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+// Do sends the request and logs it with the operation, tenant, Kyma, duration, HTTP status and Director error code.
+// A runtime not found during graceful unregistration is logged at the level of successful requests.
+func (c *client) Do(ctx context.Context, req *graphql.Request, res interface{}, gracefulUnregistration bool) error {
+	_, err := c.run(ctx, req, gracefulUnregistration, func(ctx context.Context) ([]graphql.FieldError, error) {
+		return nil, c.gqlClient.Run(ctx, req, res)
+	})
+	return err
+}
+
+func (c *client) DoPartial(ctx context.Context, req *graphql.Request, res interface{}) ([]graphql.FieldError, error) {
+	return c.run(ctx, req, false, func(ctx context.Context) ([]graphql.FieldError, error) {
+		return c.gqlClient.RunPartial(ctx, req, res)
+	})
+}
+
+func (c *client) run(ctx context.Context, req *graphql.Request, gracefulUnregistration bool, run func(ctx context.Context) ([]graphql.FieldError, error)) ([]graphql.FieldError, error) {
+	entry := &requestLog{traceBodies: c.logging && c.log.IsLevelEnabled(logrus.TraceLevel)}
+	runCtx, cancel := context.WithTimeout(context.WithValue(ctx, requestLogKey{}, entry), timeout)
 	defer cancel()
 
-	c.clearLogs()
-	err := c.gqlClient.Run(ctx, req, res)
-	if err == nil {
-		return nil
+	start := time.Now()
+	fieldErrors, err := run(runCtx)
+	if c.logging {
+		c.logRequest(req, KymaName(ctx), time.Since(start), entry, err, fieldErrors, gracefulUnregistration)
 	}
 
-	var egErr graphql.ExtendedError
-	if errors.As(err, &egErr) && gracefulUnregistration {
-		errorCodeValue, present := egErr.Extensions()["error_code"]
-		if !present {
-			errs := errors.New("failed to read the error code from the error response. Original error: ")
-			return errors.Join(errs, egErr)
-		}
-		errorCode, ok := errorCodeValue.(int64)
-		if !ok {
-			errs := errors.New("failed to cast the error code from the error response. Original error: ")
-			return errors.Join(errs, egErr)
-		}
+	return fieldErrors, err
+}
 
-		if directorApperrors.ErrorType(errorCode) == directorApperrors.NotFound {
-			return err
-		}
+func (c *client) logRequest(req *graphql.Request, kymaName string, duration time.Duration, entry *requestLog, err error, fieldErrors []graphql.FieldError, gracefulUnregistration bool) {
+	fields := logrus.Fields{
+		"operation": operationName(req.Query()),
+		"tenant":    req.Header.Get(tenantHeader),
+		"duration":  duration.String(),
+		"status":    entry.status(),
 	}
-	for _, l := range c.logs {
-		if l != "" {
-			logrus.Info(l)
-		}
+	if kymaName != "" {
+		fields["kyma"] = kymaName
+	}
+	if entry.traceBodies {
+		fields["request"] = entry.requestBody
+		fields["response"] = entry.responseBody
 	}
 
-	return err
-}
-
-func (c *client) addLog(log string) {
-	if !c.logging {
+	if err == nil && len(fieldErrors) > 0 {
+		fields["failed_fields"] = len(fieldErrors)
+		err = fieldErrors[0]
+	}
+	if err == nil {
+		c.log.WithFields(fields).Log(c.level, "GraphQL request succeeded")
 		return
 	}
 
-	c.logs = append(c.logs, log)
+	level := c.failureLevel
+	var egErr graphql.ExtendedError
+	if errors.As(err, &egErr) {
+		if errorCode, ok := directorErrorCode(egErr); ok {
+			fields["error_code"] = errorCode.String()
+			if gracefulUnregistration && errorCode == directorApperrors.NotFound {
+				level = c.level
+			}
+		}
+	}
+	c.log.WithFields(fields).WithError(err).Log(level, "GraphQL request failed")
 }
 
-func (c *client) clearLogs() {
-	c.logs = []string{}
+// directorErrorCode reads the error_code extension set by Director, decoded from JSON as a float64
+func directorErrorCode(egErr graphql.ExtendedError) (directorApperrors.ErrorType, bool) {
+	switch code := egErr.Extensions()["error_code"].(type) {
+	case float64:
+		return directorApperrors.ErrorType(code), true
+	case int64:
+		return directorApperrors.ErrorType(code), true
+	case int:
+		return directorApperrors.ErrorType(code), true
+	default:
+		return 0, false
+	}
 }
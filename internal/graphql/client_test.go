@@ -0,0 +1,173 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/kyma-project/compass-manager/third_party/machinebox/graphql"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	tokenQuery    = `mutation { result: requestOneTimeTokenForRuntime(id: "runtime-id") { token connectorURL } }`
+	tokenResponse = `{"data":{"result":{"token":"one-time-token","connectorURL":"https://connector"}}}`
+	notFound      = `{"data":null,"errors":[{"message":"Object not found","extensions":{"error_code":20,"error":"NotFound"}}]}`
+)
+
+func TestClientLogging(t *testing.T) {
+	t.Run("should log successful request with operation, tenant, Kyma and status", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(tokenResponse))
+		}))
+		defer server.Close()
+
+		log, hook := test.NewNullLogger()
+		log.SetLevel(logrus.DebugLevel)
+		client := NewGraphQLClient(server.URL, true, false, WithLogger(log, logrus.DebugLevel, logrus.WarnLevel))
+
+		// when
+		var res interface{}
+		err := client.Do(WithKymaName(context.Background(), "my-kyma"), newRequest("tenant"), &res, false)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, hook.AllEntries(), 1)
+		entry := hook.LastEntry()
+		assert.Equal(t, logrus.DebugLevel, entry.Level)
+		assert.Equal(t, "requestOneTimeTokenForRuntime", entry.Data["operation"])
+		assert.Equal(t, "tenant", entry.Data["tenant"])
+		assert.Equal(t, "my-kyma", entry.Data["kyma"])
+		assert.Equal(t, http.StatusOK, entry.Data["status"])
+		assert.NotContains(t, entry.Data, "request")
+	})
+
+	t.Run("should log failed request with Director error code at the failure level", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(notFound))
+		}))
+		defer server.Close()
+
+		log, hook := test.NewNullLogger()
+		log.SetLevel(logrus.DebugLevel)
+		client := NewGraphQLClient(server.URL, true, false, WithLogger(log, logrus.DebugLevel, logrus.ErrorLevel))
+
+		// when
+		err := client.Do(context.Background(), newRequest("tenant"), nil, false)
+		gracefulErr := client.Do(context.Background(), newRequest("tenant"), nil, true)
+
+		// then
+		require.Error(t, err)
+		require.Error(t, gracefulErr)
+		entries := hook.AllEntries()
+		require.Len(t, entries, 2)
+		assert.Equal(t, logrus.ErrorLevel, entries[0].Level)
+		assert.Equal(t, "NotFound", entries[0].Data["error_code"])
+		assert.Equal(t, err, entries[0].Data[logrus.ErrorKey])
+		assert.NotContains(t, entries[0].Data, "kyma")
+		assert.Equal(t, logrus.DebugLevel, entries[1].Level, "runtime not found during graceful unregistration is expected")
+	})
+
+	t.Run("should log redacted bodies at the trace level", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(tokenResponse))
+		}))
+		defer server.Close()
+
+		log, hook := test.NewNullLogger()
+		log.SetLevel(logrus.TraceLevel)
+		client := NewGraphQLClient(server.URL, true, false, WithLogger(log, logrus.DebugLevel, logrus.WarnLevel))
+
+		// when
+		err := client.Do(WithKymaName(context.Background(), "my-kyma"), newRequest("tenant"), nil, false)
+
+		// then
+		require.NoError(t, err)
+		entry := hook.LastEntry()
+		assert.Contains(t, entry.Data["request"], "requestOneTimeTokenForRuntime")
+		assert.Contains(t, entry.Data["response"], "https://connector")
+		assert.NotContains(t, entry.Data["response"], "one-time-token")
+		for _, field := range entry.Data {
+			assert.NotContains(t, fmt.Sprint(field), "Bearer")
+		}
+	})
+
+	t.Run("should not log when logging is disabled", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(notFound))
+		}))
+		defer server.Close()
+
+		log, hook := test.NewNullLogger()
+		client := NewGraphQLClient(server.URL, false, false, WithLogger(log, logrus.InfoLevel, logrus.ErrorLevel))
+
+		// when
+		err := client.Do(context.Background(), newRequest("tenant"), nil, false)
+
+		// then
+		require.Error(t, err)
+		assert.Empty(t, hook.AllEntries())
+	})
+
+	t.Run("should log concurrent requests with their own fields", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Tenant") == "failing" {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			_, _ = w.Write([]byte(tokenResponse))
+		}))
+		defer server.Close()
+
+		log, hook := test.NewNullLogger()
+		client := NewGraphQLClient(server.URL, true, false, WithLogger(log, logrus.InfoLevel, logrus.ErrorLevel))
+
+		// when
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				tenant := "tenant"
+				if i%2 == 0 {
+					tenant = "failing"
+				}
+				_ = client.Do(WithKymaName(context.Background(), fmt.Sprintf("kyma-%d", i)), newRequest(tenant), nil, false)
+			}()
+		}
+		wg.Wait()
+
+		// then
+		entries := hook.AllEntries()
+		require.Len(t, entries, 20)
+		for _, entry := range entries {
+			if entry.Data["tenant"] == "failing" {
+				assert.Equal(t, http.StatusBadGateway, entry.Data["status"], entry.Data["kyma"])
+				assert.Equal(t, logrus.ErrorLevel, entry.Level)
+			} else {
+				assert.Equal(t, http.StatusOK, entry.Data["status"], entry.Data["kyma"])
+				assert.Equal(t, logrus.InfoLevel, entry.Level)
+			}
+		}
+	})
+}
+
+func newRequest(tenant string) *graphql.Request {
+	req := graphql.NewRequest(tokenQuery)
+	req.Header.Set("Authorization", "Bearer access-token")
+	req.Header.Set(tenantHeader, tenant)
+	return req
+}
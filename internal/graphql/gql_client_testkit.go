@@ -1,6 +1,7 @@
 package graphql
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -17,7 +18,7 @@ type QueryAssertClient struct {
 
 type ModifyResponseFunc []func(t *testing.T, r interface{})
 
-func (c *QueryAssertClient) Do(req *graphql.Request, res interface{}, _ bool) error {
+func (c *QueryAssertClient) Do(_ context.Context, req *graphql.Request, res interface{}, _ bool) error {
 	if len(c.expectedRequests) == 0 {
 		return errors.New("no more requests were expected")
 	}
@@ -37,6 +38,11 @@ func (c *QueryAssertClient) Do(req *graphql.Request, res interface{}, _ bool) er
 	return c.err
 }
 
+// DoPartial asserts the request like Do, the error is returned as the error of the whole request
+func (c *QueryAssertClient) DoPartial(ctx context.Context, req *graphql.Request, res interface{}) ([]graphql.FieldError, error) {
+	return nil, c.Do(ctx, req, res, false)
+}
+
 func NewQueryAssertClient(t *testing.T, err error, expectedReq []*graphql.Request, modifyResponseFunc ...func(t *testing.T, r interface{})) Client {
 	return &QueryAssertClient{
 		t:                  t,
@@ -0,0 +1,102 @@
+package graphql
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/kyma-project/compass-manager/internal/redact"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+type requestLogKey struct{}
+
+// requestLog collects what the transport sees of a single request, it's passed in the request context so that concurrent requests don't share it
+type requestLog struct {
+	traceBodies bool
+
+	mu           sync.Mutex
+	statusCode   int
+	requestBody  string
+	responseBody string
+}
+
+func (l *requestLog) status() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.statusCode
+}
+
+// loggingTransport records the HTTP status, and the redacted bodies when they're traced, in the requestLog of the request context
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	entry, ok := req.Context().Value(requestLogKey{}).(*requestLog)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	var requestBody []byte
+	if entry.traceBodies && req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		requestBody = body
+	}
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseBody []byte
+	if entry.traceBodies && res.Body != nil {
+		body, err := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		responseBody = body
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	entry.statusCode = res.StatusCode
+	if entry.traceBodies {
+		entry.requestBody = redact.Body(req.Header.Get("Content-Type"), requestBody)
+		entry.responseBody = redact.Body(res.Header.Get("Content-Type"), responseBody)
+	}
+
+	return res, nil
+}
+
+// operationName returns the fields requested by the query, joined with commas, as Director operations are named by their top-level fields
+func operationName(query string) string {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil || len(doc.Operations) == 0 {
+		return ""
+	}
+
+	operation := doc.Operations[0]
+	if operation.Name != "" {
+		return operation.Name
+	}
+	var names []string
+	for _, selection := range operation.SelectionSet {
+		if field, ok := selection.(*ast.Field); ok && !slices.Contains(names, field.Name) {
+			names = append(names, field.Name)
+		}
+	}
+	return strings.Join(names, ",")
+}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	graphql "github.com/kyma-project/compass-manager/third_party/machinebox/graphql"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,13 +14,13 @@ type Client struct {
 	mock.Mock
 }
 
-// Do provides a mock function with given fields: req, res, gracefulUnregistration
-func (_m *Client) Do(req *graphql.Request, res interface{}, gracefulUnregistration bool) error {
-	ret := _m.Called(req, res, gracefulUnregistration)
+// Do provides a mock function with given fields: ctx, req, res, gracefulUnregistration
+func (_m *Client) Do(ctx context.Context, req *graphql.Request, res interface{}, gracefulUnregistration bool) error {
+	ret := _m.Called(ctx, req, res, gracefulUnregistration)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*graphql.Request, interface{}, bool) error); ok {
-		r0 = rf(req, res, gracefulUnregistration)
+	if rf, ok := ret.Get(0).(func(context.Context, *graphql.Request, interface{}, bool) error); ok {
+		r0 = rf(ctx, req, res, gracefulUnregistration)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -26,6 +28,32 @@ func (_m *Client) Do(req *graphql.Request, res interface{}, gracefulUnregistrati
 	return r0
 }
 
+// DoPartial provides a mock function with given fields: ctx, req, res
+func (_m *Client) DoPartial(ctx context.Context, req *graphql.Request, res interface{}) ([]graphql.FieldError, error) {
+	ret := _m.Called(ctx, req, res)
+
+	var r0 []graphql.FieldError
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *graphql.Request, interface{}) ([]graphql.FieldError, error)); ok {
+		return rf(ctx, req, res)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *graphql.Request, interface{}) []graphql.FieldError); ok {
+		r0 = rf(ctx, req, res)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]graphql.FieldError)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *graphql.Request, interface{}) error); ok {
+		r1 = rf(ctx, req, res)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewClient creates a new instance of Client. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewClient(t interface {
@@ -0,0 +1,101 @@
+package oauth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	clientAssertionType     = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	clientAssertionLifetime = 5 * time.Minute
+)
+
+// LoadPrivateKey reads a PEM encoded RSA or ECDSA P-256 key used to sign client assertions
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read private key")
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse private key")
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.Errorf("unsupported private key type %T", key)
+	}
+	if _, err := signingAlgorithm(signer); err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+// newClientAssertion creates a JWT authenticating the client at the tokens endpoint, as described in RFC 7523
+func newClientAssertion(signer crypto.Signer, keyID, clientID, tokensEndpoint string, now time.Time) (string, error) {
+	algorithm, err := signingAlgorithm(signer)
+	if err != nil {
+		return "", err
+	}
+
+	options := (&jose.SignerOptions{}).WithType("JWT")
+	if keyID != "" {
+		options = options.WithHeader(jose.HeaderKey("kid"), keyID)
+	}
+	joseSigner, err := jose.NewSigner(jose.SigningKey{Algorithm: algorithm, Key: signer}, options)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create client assertion signer")
+	}
+
+	assertion, err := jwt.Signed(joseSigner).Claims(jwt.Claims{
+		Issuer:   clientID,
+		Subject:  clientID,
+		Audience: jwt.Audience{tokensEndpoint},
+		ID:       uuid.New().String(),
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(now.Add(clientAssertionLifetime)),
+	}).CompactSerialize()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign client assertion")
+	}
+	return assertion, nil
+}
+
+func signingAlgorithm(signer crypto.Signer) (jose.SignatureAlgorithm, error) {
+	switch key := signer.Public().(type) {
+	case *rsa.PublicKey:
+		return jose.RS256, nil
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return "", errors.Errorf("unsupported ECDSA curve %s, only P-256 is supported", key.Curve.Params().Name)
+		}
+		return jose.ES256, nil
+	default:
+		return "", errors.Errorf("unsupported private key type %T", key)
+	}
+}
@@ -0,0 +1,57 @@
+package oauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPrivateKey(t *testing.T) {
+	writeKey := func(t *testing.T, curve elliptic.Curve) string {
+		key, err := ecdsa.GenerateKey(curve, rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err)
+
+		path := filepath.Join(t.TempDir(), "key.pem")
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+		return path
+	}
+
+	t.Run("Should load PKCS8 ECDSA P-256 key", func(t *testing.T) {
+		// when
+		signer, err := LoadPrivateKey(writeKey(t, elliptic.P256()))
+
+		// then
+		require.NoError(t, err)
+		assert.IsType(t, &ecdsa.PublicKey{}, signer.Public())
+	})
+
+	t.Run("Should reject unsupported curve", func(t *testing.T) {
+		// when
+		_, err := LoadPrivateKey(writeKey(t, elliptic.P384()))
+
+		// then
+		require.ErrorContains(t, err, "unsupported ECDSA curve")
+	})
+
+	t.Run("Should reject file without PEM block", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "key.pem")
+		require.NoError(t, os.WriteFile(path, []byte("not a key"), 0600))
+
+		// when
+		_, err := LoadPrivateKey(path)
+
+		// then
+		require.Error(t, err)
+	})
+}
@@ -1,16 +1,19 @@
 package oauth
 
 import (
+	"crypto"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/kyma-project/compass-manager/internal/apperrors"
 	"github.com/kyma-project/compass-manager/internal/util"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -19,14 +22,104 @@ type Client interface {
 	GetAuthorizationToken() (Token, apperrors.AppError)
 }
 
+// ReloadableClient is a Client whose credentials can be replaced while running
+type ReloadableClient interface {
+	Client
+	// UpdateCredentials replaces the credentials and drops the cached token if they changed
+	UpdateCredentials(clientID, clientSecret, tokensEndpoint string)
+	// InvalidateCredentials marks the credentials as unusable, the reason is reported by CredentialsValid
+	InvalidateCredentials(reason error)
+	// CredentialsValid returns an error if the credentials are missing, invalid or were rejected by the tokens endpoint
+	CredentialsValid() error
+}
+
 type oauthClient struct {
 	httpClient *http.Client
-	creds      credentials
+	request    tokenRequest
+
+	// fetchMu serializes requests to the tokens endpoint, mu guards the state below and is never held during a request,
+	// so CredentialsValid and credential updates don't wait for a slow tokens endpoint
+	fetchMu       sync.Mutex
+	mu            sync.Mutex
+	creds         credentials
+	token         Token
+	credsInvalid  error
+	credsRejected error
+}
+
+// tokenRequest holds the parameters of the client credentials grant other than the credentials themselves
+type tokenRequest struct {
+	scopes     []string
+	audience   string
+	resource   string
+	authMethod AuthMethod
+	signer     crypto.Signer
+	keyID      string
+	onFetch    func(duration time.Duration, err apperrors.AppError)
+}
+
+type Option func(*tokenRequest)
+
+// WithScopes overrides the default scopes requested for the token. No scope is requested when called without arguments.
+func WithScopes(scopes ...string) Option {
+	return func(r *tokenRequest) {
+		r.scopes = scopes
+	}
+}
+
+// WithAudience requests a token for the given audience
+func WithAudience(audience string) Option {
+	return func(r *tokenRequest) {
+		r.audience = audience
+	}
+}
+
+// WithResource requests a token for the given resource indicator, as described in RFC 8707
+func WithResource(resource string) Option {
+	return func(r *tokenRequest) {
+		r.resource = resource
+	}
+}
+
+// WithClientSecretPost sends the client credentials in the request body instead of the Authorization header
+func WithClientSecretPost() Option {
+	return func(r *tokenRequest) {
+		r.authMethod = AuthMethodClientSecretPost
+	}
+}
+
+// WithPrivateKeyJWT authenticates the client with a JWT signed with the given key instead of the client secret
+func WithPrivateKeyJWT(signer crypto.Signer, keyID string) Option {
+	return func(r *tokenRequest) {
+		r.authMethod = AuthMethodPrivateKeyJWT
+		r.signer = signer
+		r.keyID = keyID
+	}
+}
+
+// WithFetchObserver calls onFetch after every request to the tokens endpoint, with its duration and error, e.g. to export metrics.
+// Tokens returned from the cache aren't observed.
+func WithFetchObserver(onFetch func(duration time.Duration, err apperrors.AppError)) Option {
+	return func(r *tokenRequest) {
+		r.onFetch = onFetch
+	}
+}
+
+func newTokenRequest(opts []Option) tokenRequest {
+	request := tokenRequest{
+		scopes:     strings.Fields(scopes),
+		authMethod: AuthMethodClientSecretBasic,
+	}
+	for _, opt := range opts {
+		opt(&request)
+	}
+	return request
 }
 
-func NewOauthClient(client *http.Client, clientID, clientSecret, tokensEndpoint string) Client {
+func NewOauthClient(client *http.Client, clientID, clientSecret, tokensEndpoint string, opts ...Option) Client {
 	return &oauthClient{
 		httpClient: client,
+		request:    newTokenRequest(opts),
 		creds: credentials{
 			clientID:       clientID,
 			clientSecret:   clientSecret,
@@ -35,57 +128,187 @@ func NewOauthClient(client *http.Client, clientID, clientSecret, tokensEndpoint
 	}
 }
 
+// NewReloadableOauthClient creates a client without credentials, they must be provided with UpdateCredentials
+func NewReloadableOauthClient(client *http.Client, opts ...Option) ReloadableClient {
+	return &oauthClient{
+		httpClient:   client,
+		request:      newTokenRequest(opts),
+		credsInvalid: errors.New("credentials were not loaded yet"),
+	}
+}
+
 func (c *oauthClient) GetAuthorizationToken() (Token, apperrors.AppError) {
-	return c.getAuthorizationToken(c.creds)
+	if token, _ := c.cachedToken(); !token.EmptyOrExpired() {
+		return token, nil
+	}
+
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+
+	// the token may have been fetched while waiting for the previous request
+	token, creds := c.cachedToken()
+	if !token.EmptyOrExpired() {
+		return token, nil
+	}
+
+	start := time.Now()
+	token, rejected, err := c.getAuthorizationToken(creds)
+	if c.request.onFetch != nil {
+		c.request.onFetch(time.Since(start), err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// credentials replaced during the request keep their own state
+	if creds == c.creds {
+		if rejected != nil || err == nil {
+			c.credsRejected = rejected
+		}
+		if err == nil {
+			c.token = token
+		}
+	}
+	if err != nil {
+		return Token{}, err
+	}
+	return token, nil
 }
 
-func (c *oauthClient) getAuthorizationToken(credentials credentials) (Token, apperrors.AppError) {
-	log.Infof("Getting authorisation token for credentials to access Director from endpoint: %s", credentials.tokensEndpoint)
+func (c *oauthClient) cachedToken() (Token, credentials) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.token, c.creds
+}
+
+func (c *oauthClient) UpdateCredentials(clientID, clientSecret, tokensEndpoint string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	creds := credentials{
+		clientID:       clientID,
+		clientSecret:   clientSecret,
+		tokensEndpoint: tokensEndpoint,
+	}
+	c.credsInvalid = nil
+	if creds == c.creds {
+		return
+	}
+
+	log.Infof("Updated credentials to access Director, client ID: %s", clientID)
+	c.creds = creds
+	c.token = Token{}
+	c.credsRejected = nil
+}
+
+func (c *oauthClient) InvalidateCredentials(reason error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.credsInvalid = reason
+}
+
+func (c *oauthClient) CredentialsValid() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.credsInvalid != nil {
+		return c.credsInvalid
+	}
+	return c.credsRejected
+}
 
+func (c *oauthClient) tokenRequestForm(credentials credentials, now time.Time) (url.Values, error) {
 	form := url.Values{}
 	form.Add(grantTypeFieldName, credentialsGrantType)
-	form.Add(scopeFieldName, scopes)
+	if len(c.request.scopes) > 0 {
+		form.Add(scopeFieldName, strings.Join(c.request.scopes, " "))
+	}
+	if c.request.audience != "" {
+		form.Add(audienceFieldName, c.request.audience)
+	}
+	if c.request.resource != "" {
+		form.Add(resourceFieldName, c.request.resource)
+	}
 
-	request, err := http.NewRequest(http.MethodPost, credentials.tokensEndpoint, strings.NewReader(form.Encode()))
+	switch c.request.authMethod {
+	case AuthMethodClientSecretPost:
+		form.Add(clientIDKey, credentials.clientID)
+		form.Add(clientSecretKey, credentials.clientSecret)
+	case AuthMethodPrivateKeyJWT:
+		if c.request.signer == nil {
+			return nil, errors.New("private key for the client assertion is not set")
+		}
+		assertion, err := newClientAssertion(c.request.signer, c.request.keyID, credentials.clientID, credentials.tokensEndpoint, now)
+		if err != nil {
+			return nil, err
+		}
+		form.Add(clientIDKey, credentials.clientID)
+		form.Add(clientAssertionTypeFieldName, clientAssertionType)
+		form.Add(clientAssertionFieldName, assertion)
+	case AuthMethodClientSecretBasic:
+		// credentials are sent in the Authorization header
+	}
+
+	return form, nil
+}
+
+// getAuthorizationToken requests a token with the credentials, the second error is set when the tokens endpoint rejected the credentials
+func (c *oauthClient) getAuthorizationToken(credentials credentials) (Token, error, apperrors.AppError) {
+	log.Infof("Getting authorisation token for credentials to access Director from endpoint: %s", credentials.tokensEndpoint)
+
+	now := time.Now()
+
+	form, err := c.tokenRequestForm(credentials, now)
 	if err != nil {
 		log.Errorf("Failed to create authorisation token request")
-		return Token{}, apperrors.Internalf("Failed to create authorisation token request: %s", err.Error())
+		return Token{}, nil, apperrors.Internalf("Failed to create authorisation token request: %s", err.Error())
 	}
 
-	now := time.Now().Unix()
+	request, err := http.NewRequest(http.MethodPost, credentials.tokensEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		log.Errorf("Failed to create authorisation token request")
+		return Token{}, nil, apperrors.Internalf("Failed to create authorisation token request: %s", err.Error())
+	}
 
-	request.SetBasicAuth(credentials.clientID, credentials.clientSecret)
+	if c.request.authMethod == AuthMethodClientSecretBasic {
+		request.SetBasicAuth(credentials.clientID, credentials.clientSecret)
+	}
 	request.Header.Set(contentTypeHeader, contentTypeApplicationURLEncoded)
 
 	response, err := c.httpClient.Do(request)
 	if err != nil {
-		return Token{}, apperrors.Internalf("Failed to execute http call: %s", err.Error())
+		return Token{}, nil, apperrors.Internalf("Failed to execute http call: %s", err.Error())
 	}
 	defer util.Close(response.Body)
 
 	if response.StatusCode != http.StatusOK {
+		var rejected error
+		if response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusBadRequest {
+			rejected = errors.Errorf("credentials were rejected by the tokens endpoint with status %s", response.Status)
+		}
 		dump, dumpErr := httputil.DumpResponse(response, true)
 		if dumpErr != nil {
 			dump = []byte("failed to dump response body")
 		}
-		return Token{}, apperrors.External("Get token call returned unexpected status: %s. Response dump: %s", response.Status, string(dump)).SetComponent(apperrors.ErrMpsOAuth2)
+		return Token{}, rejected, apperrors.External("Get token call returned unexpected status: %s. Response dump: %s", response.Status, string(dump)).SetComponent(apperrors.ErrMpsOAuth2)
 	}
 
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
-		return Token{}, apperrors.Internalf("Failed to read token response body from '%s': %s", credentials.tokensEndpoint, err.Error())
+		return Token{}, nil, apperrors.Internalf("Failed to read token response body from '%s': %s", credentials.tokensEndpoint, err.Error())
 	}
 
 	tokenResponse := Token{}
 
 	err = json.Unmarshal(body, &tokenResponse)
 	if err != nil {
-		return Token{}, apperrors.Internalf("failed to unmarshal token response body: %s", err.Error())
+		return Token{}, nil, apperrors.Internalf("failed to unmarshal token response body: %s", err.Error())
 	}
 
 	log.Infof("Successfully unmarshal response oauth token for accessing Director")
 
-	tokenResponse.Expiration += now
+	tokenResponse.Expiration += now.Unix()
 
-	return tokenResponse, nil
+	return tokenResponse, nil, nil
 }
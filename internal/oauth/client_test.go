@@ -3,12 +3,21 @@ package oauth
 import (
 	"bytes"
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"testing"
 	"time"
 
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/kyma-project/compass-manager/internal/apperrors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
@@ -72,6 +81,288 @@ func TestOauthClient_GetAuthorizationToken(t *testing.T) {
 	})
 }
 
+func TestOauthClient_ReloadCredentials(t *testing.T) {
+	newTokenServer := func(t *testing.T, calls *int, validSecret string) *http.Client {
+		return NewTestClient(func(req *http.Request) *http.Response {
+			*calls++
+			_, secret, _ := req.BasicAuth()
+			if secret != validSecret {
+				return &http.Response{
+					StatusCode: http.StatusUnauthorized,
+					Status:     "401 Unauthorized",
+					Body:       io.NopCloser(bytes.NewReader(nil)),
+				}
+			}
+
+			jsonToken, err := json.Marshal(&Token{AccessToken: "token-for-" + secret, Expiration: 3600})
+			require.NoError(t, err)
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(jsonToken)),
+			}
+		})
+	}
+
+	t.Run("Should cache token until credentials change", func(t *testing.T) {
+		// given
+		calls := 0
+		oauthClient := NewReloadableOauthClient(newTokenServer(t, &calls, "new-secret"))
+		oauthClient.UpdateCredentials("12345", "new-secret", "http://hydra:4445")
+
+		// when
+		firstToken, firstErr := oauthClient.GetAuthorizationToken()
+		cachedToken, cachedErr := oauthClient.GetAuthorizationToken()
+		oauthClient.UpdateCredentials("12345", "new-secret", "http://hydra:4445")
+		unchangedToken, unchangedErr := oauthClient.GetAuthorizationToken()
+
+		// then
+		require.NoError(t, firstErr)
+		require.NoError(t, cachedErr)
+		require.NoError(t, unchangedErr)
+		assert.Equal(t, "token-for-new-secret", firstToken.AccessToken)
+		assert.Equal(t, firstToken, cachedToken)
+		assert.Equal(t, firstToken, unchangedToken)
+		assert.Equal(t, 1, calls)
+		assert.NoError(t, oauthClient.CredentialsValid())
+	})
+
+	t.Run("Should drop cached token and report invalid credentials after rotation", func(t *testing.T) {
+		// given
+		calls := 0
+		oauthClient := NewReloadableOauthClient(newTokenServer(t, &calls, "new-secret"))
+		require.Error(t, oauthClient.CredentialsValid())
+
+		oauthClient.UpdateCredentials("12345", "old-secret", "http://hydra:4445")
+
+		// when
+		_, rejectedErr := oauthClient.GetAuthorizationToken()
+		rejectedValidity := oauthClient.CredentialsValid()
+
+		oauthClient.UpdateCredentials("12345", "new-secret", "http://hydra:4445")
+		token, err := oauthClient.GetAuthorizationToken()
+
+		// then
+		require.Error(t, rejectedErr)
+		require.ErrorContains(t, rejectedValidity, "rejected by the tokens endpoint")
+		require.NoError(t, err)
+		assert.Equal(t, "token-for-new-secret", token.AccessToken)
+		assert.NoError(t, oauthClient.CredentialsValid())
+	})
+
+	t.Run("Should observe requests to the tokens endpoint and not cached tokens", func(t *testing.T) {
+		// given
+		calls := 0
+		var fetchErrors []error
+		oauthClient := NewReloadableOauthClient(newTokenServer(t, &calls, "new-secret"), WithFetchObserver(func(_ time.Duration, err apperrors.AppError) {
+			fetchErrors = append(fetchErrors, err)
+		}))
+		oauthClient.UpdateCredentials("12345", "old-secret", "http://hydra:4445")
+
+		// when
+		_, _ = oauthClient.GetAuthorizationToken()
+		oauthClient.UpdateCredentials("12345", "new-secret", "http://hydra:4445")
+		_, _ = oauthClient.GetAuthorizationToken()
+		_, _ = oauthClient.GetAuthorizationToken()
+
+		// then
+		require.Len(t, fetchErrors, 2)
+		assert.Error(t, fetchErrors[0])
+		assert.Nil(t, fetchErrors[1])
+	})
+
+	t.Run("Should report credentials validity while the tokens endpoint is slow", func(t *testing.T) {
+		// given
+		requested := make(chan struct{})
+		release := make(chan struct{})
+		oauthClient := NewReloadableOauthClient(NewTestClient(func(req *http.Request) *http.Response {
+			close(requested)
+			<-release
+			return &http.Response{
+				StatusCode: http.StatusUnauthorized,
+				Status:     "401 Unauthorized",
+				Body:       io.NopCloser(bytes.NewReader(nil)),
+			}
+		}))
+		oauthClient.UpdateCredentials("12345", "old-secret", "http://hydra:4445")
+
+		fetched := make(chan apperrors.AppError)
+		go func() {
+			_, err := oauthClient.GetAuthorizationToken()
+			fetched <- err
+		}()
+		<-requested
+
+		// when
+		validity := make(chan error)
+		go func() {
+			oauthClient.UpdateCredentials("12345", "new-secret", "http://hydra:4445")
+			validity <- oauthClient.CredentialsValid()
+		}()
+
+		// then
+		select {
+		case err := <-validity:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("credentials validity waits for the tokens endpoint")
+		}
+		close(release)
+		require.Error(t, <-fetched)
+		assert.NoError(t, oauthClient.CredentialsValid(), "rejection of the replaced credentials should not be reported")
+	})
+
+	t.Run("Should report credentials invalidated by their source", func(t *testing.T) {
+		// given
+		calls := 0
+		oauthClient := NewReloadableOauthClient(newTokenServer(t, &calls, "new-secret"))
+		oauthClient.UpdateCredentials("12345", "new-secret", "http://hydra:4445")
+
+		// when
+		oauthClient.InvalidateCredentials(errors.New("secret not found"))
+
+		// then
+		require.ErrorContains(t, oauthClient.CredentialsValid(), "secret not found")
+	})
+}
+
+func TestOauthClient_TokenRequestParameters(t *testing.T) {
+	creds := credentials{
+		clientID:       "12345",
+		clientSecret:   "some dark and scary secret",
+		tokensEndpoint: "http://hydra:4445",
+	}
+
+	requestFor := func(t *testing.T, opts ...Option) *http.Request {
+		var captured *http.Request
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			require.NoError(t, req.ParseForm())
+			captured = req
+
+			jsonToken, err := json.Marshal(&Token{AccessToken: "12345", Expiration: 1234})
+			require.NoError(t, err)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(jsonToken)),
+			}
+		})
+
+		_, err := NewOauthClient(client, creds.clientID, creds.clientSecret, creds.tokensEndpoint, opts...).GetAuthorizationToken()
+		require.NoError(t, err)
+		require.NotNil(t, captured)
+		return captured
+	}
+
+	t.Run("Should request default scopes with client_secret_basic", func(t *testing.T) {
+		// when
+		req := requestFor(t)
+
+		// then
+		username, secret, ok := req.BasicAuth()
+		require.True(t, ok)
+		assert.Equal(t, creds.clientID, username)
+		assert.Equal(t, creds.clientSecret, secret)
+		assert.Equal(t, credentialsGrantType, req.PostForm.Get(grantTypeFieldName))
+		assert.Equal(t, "runtime:read runtime:write", req.PostForm.Get(scopeFieldName))
+		assert.False(t, req.PostForm.Has(clientSecretKey))
+	})
+
+	t.Run("Should request configured scopes, audience and resource", func(t *testing.T) {
+		// when
+		req := requestFor(t, WithScopes("runtime:read", "formation:write"), WithAudience("director"), WithResource("https://director.example.com"))
+
+		// then
+		assert.Equal(t, "runtime:read formation:write", req.PostForm.Get(scopeFieldName))
+		assert.Equal(t, "director", req.PostForm.Get(audienceFieldName))
+		assert.Equal(t, "https://director.example.com", req.PostForm.Get(resourceFieldName))
+	})
+
+	t.Run("Should not request scope when scopes are empty", func(t *testing.T) {
+		// when
+		req := requestFor(t, WithScopes())
+
+		// then
+		assert.False(t, req.PostForm.Has(scopeFieldName))
+		assert.False(t, req.PostForm.Has(audienceFieldName))
+		assert.False(t, req.PostForm.Has(resourceFieldName))
+	})
+
+	t.Run("Should send credentials in body with client_secret_post", func(t *testing.T) {
+		// when
+		req := requestFor(t, WithClientSecretPost())
+
+		// then
+		_, _, ok := req.BasicAuth()
+		assert.False(t, ok)
+		assert.Equal(t, creds.clientID, req.PostForm.Get(clientIDKey))
+		assert.Equal(t, creds.clientSecret, req.PostForm.Get(clientSecretKey))
+	})
+
+	t.Run("Should send signed client assertion with private_key_jwt", func(t *testing.T) {
+		for name, key := range map[string]crypto.Signer{
+			"ES256": mustGenerateECDSAKey(t),
+			"RS256": mustGenerateRSAKey(t),
+		} {
+			t.Run(name, func(t *testing.T) {
+				// when
+				req := requestFor(t, WithPrivateKeyJWT(key, "key-1"))
+
+				// then
+				_, _, ok := req.BasicAuth()
+				assert.False(t, ok)
+				assert.False(t, req.PostForm.Has(clientSecretKey))
+				assert.Equal(t, creds.clientID, req.PostForm.Get(clientIDKey))
+				assert.Equal(t, clientAssertionType, req.PostForm.Get(clientAssertionTypeFieldName))
+
+				header, claims := verifyClientAssertion(t, req.PostForm.Get(clientAssertionFieldName), key.Public())
+				assert.Equal(t, name, header.Algorithm)
+				assert.Equal(t, "key-1", header.KeyID)
+				assert.Equal(t, creds.clientID, claims.Issuer)
+				assert.Equal(t, creds.clientID, claims.Subject)
+				assert.Equal(t, jwt.Audience{creds.tokensEndpoint}, claims.Audience)
+				assert.NotEmpty(t, claims.ID)
+				assert.NoError(t, claims.Validate(jwt.Expected{Time: time.Now()}))
+			})
+		}
+	})
+
+	t.Run("Should fail when private key is missing for private_key_jwt", func(t *testing.T) {
+		// given
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			t.Fatal("token endpoint should not be called")
+			return nil
+		})
+
+		// when
+		_, err := NewOauthClient(client, creds.clientID, creds.clientSecret, creds.tokensEndpoint, WithPrivateKeyJWT(nil, "")).GetAuthorizationToken()
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func mustGenerateECDSAKey(t *testing.T) crypto.Signer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func mustGenerateRSAKey(t *testing.T) crypto.Signer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func verifyClientAssertion(t *testing.T, assertion string, publicKey crypto.PublicKey) (jose.Header, jwt.Claims) {
+	token, err := jwt.ParseSigned(assertion)
+	require.NoError(t, err)
+	require.Len(t, token.Headers, 1)
+
+	var claims jwt.Claims
+	require.NoError(t, token.Claims(publicKey, &claims))
+	return token.Headers[0], claims
+}
+
 func NewTestClient(fn RoundTripFunc) *http.Client {
 	return &http.Client{
 		Transport: fn,
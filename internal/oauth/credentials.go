@@ -0,0 +1,38 @@
+package oauth
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// DirectorOAuth is the format of the director.yaml file with the credentials of the compass-manager OAuth client
+type DirectorOAuth struct {
+	Data struct {
+		ClientID       string `json:"client_id"`
+		ClientSecret   string `json:"client_secret"`
+		TokensEndpoint string `json:"tokens_endpoint"`
+	} `json:"data"`
+}
+
+// ParseDirectorOAuth reads the director.yaml content and checks that all credentials required by the auth method are present.
+// The client secret isn't required with private_key_jwt, as the client authenticates with its private key instead.
+func ParseDirectorOAuth(data []byte, authMethod AuthMethod) (DirectorOAuth, error) {
+	cfg := DirectorOAuth{}
+	err := yaml.Unmarshal(data, &cfg)
+	if err != nil {
+		return DirectorOAuth{}, errors.Wrap(err, "Failed to unmarshal director config")
+	}
+
+	if authMethod == AuthMethodPrivateKeyJWT {
+		if cfg.Data.ClientID == "" || cfg.Data.TokensEndpoint == "" {
+			return DirectorOAuth{}, errors.Errorf("director config must contain %s and %s", clientIDKey, tokensEndpointKey)
+		}
+		return cfg, nil
+	}
+
+	if cfg.Data.ClientID == "" || cfg.Data.ClientSecret == "" || cfg.Data.TokensEndpoint == "" {
+		return DirectorOAuth{}, errors.Errorf("director config must contain %s, %s and %s", clientIDKey, clientSecretKey, tokensEndpointKey)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,87 @@
+package oauth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDirectorOAuth(t *testing.T) {
+	t.Run("Should parse director config", func(t *testing.T) {
+		// given
+		data := []byte(`data:
+  client_id: "some-ID"
+  client_secret: "some-Secret"
+  tokens_endpoint: "https://example.com/oauth2/token"`)
+
+		// when
+		cfg, err := ParseDirectorOAuth(data, AuthMethodClientSecretBasic)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "some-ID", cfg.Data.ClientID)
+		assert.Equal(t, "some-Secret", cfg.Data.ClientSecret)
+		assert.Equal(t, "https://example.com/oauth2/token", cfg.Data.TokensEndpoint)
+	})
+
+	t.Run("Should return error when credentials are incomplete", func(t *testing.T) {
+		// given
+		data := []byte(`data:
+  client_id: "some-ID"`)
+
+		// when
+		_, err := ParseDirectorOAuth(data, AuthMethodClientSecretBasic)
+
+		// then
+		require.Error(t, err)
+	})
+
+	t.Run("Should parse director config without client secret for private_key_jwt", func(t *testing.T) {
+		// given
+		data := []byte(`data:
+  client_id: "some-ID"
+  tokens_endpoint: "https://example.com/oauth2/token"`)
+
+		// when
+		cfg, err := ParseDirectorOAuth(data, AuthMethodPrivateKeyJWT)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "some-ID", cfg.Data.ClientID)
+		assert.Empty(t, cfg.Data.ClientSecret)
+	})
+
+	t.Run("Should return error when client secret is missing for client_secret_post", func(t *testing.T) {
+		// given
+		data := []byte(`data:
+  client_id: "some-ID"
+  tokens_endpoint: "https://example.com/oauth2/token"`)
+
+		// when
+		_, err := ParseDirectorOAuth(data, AuthMethodClientSecretPost)
+
+		// then
+		require.ErrorContains(t, err, "client_secret")
+	})
+
+	t.Run("Should return error when tokens endpoint is missing for private_key_jwt", func(t *testing.T) {
+		// given
+		data := []byte(`data:
+  client_id: "some-ID"`)
+
+		// when
+		_, err := ParseDirectorOAuth(data, AuthMethodPrivateKeyJWT)
+
+		// then
+		require.Error(t, err)
+	})
+
+	t.Run("Should return error when config is not valid yaml", func(t *testing.T) {
+		// when
+		_, err := ParseDirectorOAuth([]byte("data: ["), AuthMethodClientSecretBasic)
+
+		// then
+		require.Error(t, err)
+	})
+}
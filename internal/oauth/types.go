@@ -12,11 +12,25 @@ const (
 	scopeFieldName = "scope"
 	scopes         = "runtime:read runtime:write"
 
+	audienceFieldName            = "audience"
+	resourceFieldName            = "resource"
+	clientAssertionTypeFieldName = "client_assertion_type"
+	clientAssertionFieldName     = "client_assertion"
+
 	clientIDKey       = "client_id"
 	clientSecretKey   = "client_secret"
 	tokensEndpointKey = "tokens_endpoint"
 )
 
+// AuthMethod is the way the client authenticates at the tokens endpoint, as registered in RFC 7591
+type AuthMethod string
+
+const (
+	AuthMethodClientSecretBasic AuthMethod = "client_secret_basic"
+	AuthMethodClientSecretPost  AuthMethod = "client_secret_post"
+	AuthMethodPrivateKeyJWT     AuthMethod = "private_key_jwt"
+)
+
 type Token struct {
 	AccessToken string `json:"access_token"`
 	Expiration  int64  `json:"expires_in"`
@@ -0,0 +1,122 @@
+// Package recording records HTTP exchanges with Director and its tokens endpoint to files, with credentials redacted,
+// and replays them, so that Director interactions seen in production can be turned into deterministic tests.
+package recording
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kyma-project/compass-manager/internal/redact"
+	"github.com/pkg/errors"
+)
+
+const (
+	fileExtension = ".json"
+	fileMode      = 0o600
+	dirMode       = 0o750
+)
+
+// Exchange is a recorded request with its response, stored as one JSON file
+type Exchange struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// readRequest reads the body of the request, leaving it readable for the next transport, and returns the redacted request
+func readRequest(r *http.Request) (Request, error) {
+	body, err := readBody(&r.Body)
+	if err != nil {
+		return Request{}, errors.Wrap(err, "failed to read request body")
+	}
+
+	return Request{
+		Method: r.Method,
+		URL:    redact.URL(r.URL),
+		Header: redact.Header(r.Header),
+		Body:   redact.Body(r.Header.Get("Content-Type"), body),
+	}, nil
+}
+
+func readResponse(r *http.Response) (Response, error) {
+	body, err := readBody(&r.Body)
+	if err != nil {
+		return Response{}, errors.Wrap(err, "failed to read response body")
+	}
+
+	return Response{
+		StatusCode: r.StatusCode,
+		Header:     redact.Header(r.Header),
+		Body:       redact.Body(r.Header.Get("Content-Type"), body),
+	}, nil
+}
+
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	defer (*body).Close()
+
+	data, err := io.ReadAll(*body)
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+func writeExchange(path string, exchange Exchange) error {
+	data, err := json.MarshalIndent(exchange, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal exchange")
+	}
+	return errors.Wrapf(os.WriteFile(path, data, fileMode), "failed to write exchange to %s", path)
+}
+
+// readExchanges reads exchanges from the directory in the order of file names
+func readExchanges(dir string) ([]Exchange, []string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to read recordings from %s", dir)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), fileExtension) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	exchanges := make([]Exchange, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to read recording %s", name)
+		}
+		var exchange Exchange
+		if err := json.Unmarshal(data, &exchange); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to parse recording %s", name)
+		}
+		exchanges = append(exchanges, exchange)
+	}
+	return exchanges, names, nil
+}
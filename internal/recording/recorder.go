@@ -0,0 +1,97 @@
+package recording
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Recorder writes every exchange passing through the wrapped transports to a numbered file in its directory.
+// Failing to write an exchange is logged and doesn't fail the request.
+type Recorder struct {
+	dir string
+	log *logrus.Logger
+
+	mu  sync.Mutex
+	seq int
+}
+
+// NewRecorder creates the directory if needed, numbering continues after the exchanges already recorded there
+func NewRecorder(dir string, log *logrus.Logger) (*Recorder, error) {
+	if err := os.MkdirAll(dir, dirMode); err != nil {
+		return nil, errors.Wrapf(err, "failed to create recordings directory %s", dir)
+	}
+	_, names, err := readExchanges(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Recorder{dir: dir, log: log, seq: len(names)}, nil
+}
+
+// Wrap returns a transport recording exchanges sent with next, http.DefaultTransport is used if next is nil
+func (r *Recorder) Wrap(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		request, err := readRequest(req)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := readResponse(res)
+		if err != nil {
+			return nil, err
+		}
+
+		r.record(Exchange{Request: request, Response: response})
+		return res, nil
+	})
+}
+
+func (r *Recorder) record(exchange Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seq++
+	path := filepath.Join(r.dir, fileName(r.seq, exchange.Request))
+	if err := writeExchange(path, exchange); err != nil {
+		r.log.Warnf("Failed to record Director exchange: %v", err)
+	}
+}
+
+func fileName(seq int, request Request) string {
+	name := request.URL
+	if i := strings.IndexAny(name, "?#"); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.Index(name, "://"); i >= 0 {
+		name = name[i+3:]
+	}
+	name = strings.Trim(strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '.' {
+			return r
+		}
+		return '_'
+	}, name), "_")
+
+	return fmt.Sprintf("%06d-%s-%s%s", seq, strings.ToLower(request.Method), name, fileExtension)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,184 @@
+package recording
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/compass-manager/controllers"
+	"github.com/kyma-project/compass-manager/internal/director"
+	"github.com/kyma-project/compass-manager/internal/director/fake"
+	"github.com/kyma-project/compass-manager/internal/graphql"
+	"github.com/kyma-project/compass-manager/internal/oauth"
+	"github.com/kyma-project/compass-manager/internal/redact"
+	"github.com/kyma-project/compass-manager/pkg/gqlschema"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tenant = "3e64ebae-38b5-46a0-b1ed-9ccee153a0ae"
+
+func TestRecordAndReplay(t *testing.T) {
+	t.Run("should record redacted exchanges and replay them without Director", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		server := fake.NewServer(fake.Config{})
+
+		recorder, err := NewRecorder(dir, logrus.New())
+		require.NoError(t, err)
+
+		client := newDirectorClient(server, recorder.Wrap)
+		runtimeID, err := client.CreateRuntime(&gqlschema.RuntimeInput{Name: "my-runtime"}, tenant)
+		require.NoError(t, err)
+		recordedToken, err := client.GetConnectionToken(runtimeID, tenant)
+		require.NoError(t, err)
+		server.Close()
+
+		// when
+		replayer, err := NewReplayer(dir)
+		require.NoError(t, err)
+
+		client = newDirectorClient(server, replayer.Wrap)
+		_, createErr := client.CreateRuntime(&gqlschema.RuntimeInput{Name: "my-runtime"}, tenant)
+		token, tokenErr := client.GetConnectionToken(runtimeID, tenant)
+		_, unrecordedErr := client.GetRuntime(runtimeID, tenant)
+
+		// then
+		require.NoError(t, createErr)
+		require.NoError(t, tokenErr)
+		assert.Equal(t, redact.Redacted, token.Token)
+		assert.Equal(t, recordedToken.ConnectorURL, token.ConnectorURL)
+		require.Error(t, unrecordedErr)
+		assert.Empty(t, replayer.Unused())
+
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, files, 3)
+		for _, file := range files {
+			data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+			require.NoError(t, err)
+			assert.NotContains(t, string(data), fake.DefaultClientSecret)
+			assert.NotContains(t, string(data), recordedToken.Token)
+			assert.NotContains(t, string(data), "Bearer")
+		}
+	})
+
+	t.Run("should replay recorded registration of a runtime with a generated name", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		server := fake.NewServer(fake.Config{})
+		labels := controllers.CompassRuntimeLabels(map[string]string{
+			controllers.LabelShootName:       "c-1a2b3c",
+			controllers.LabelGlobalAccountID: tenant,
+		})
+
+		recorder, err := NewRecorder(dir, logrus.New())
+		require.NoError(t, err)
+		registrator := controllers.NewCompassRegistrator(director.NewSingleDirectorRegistry(newDirectorClient(server, recorder.Wrap), ""), logrus.New())
+		recordedID, err := registrator.RegisterInCompass("my-kyma", labels, tenant, "")
+		require.NoError(t, err)
+		server.Close()
+
+		// when
+		replayer, err := NewReplayer(dir)
+		require.NoError(t, err)
+
+		registrator = controllers.NewCompassRegistrator(director.NewSingleDirectorRegistry(newDirectorClient(server, replayer.Wrap), ""), logrus.New())
+		runtimeID, registerErr := registrator.RegisterInCompass("my-kyma", labels, tenant, "")
+
+		// then
+		require.NoError(t, registerErr)
+		assert.Equal(t, recordedID, runtimeID)
+		assert.Empty(t, replayer.Unused())
+	})
+
+	t.Run("should not replay registration of a runtime with other labels", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		server := fake.NewServer(fake.Config{})
+
+		recorder, err := NewRecorder(dir, logrus.New())
+		require.NoError(t, err)
+		_, err = newDirectorClient(server, recorder.Wrap).CreateRuntime(&gqlschema.RuntimeInput{
+			Name:   "my-runtime-abcd",
+			Labels: gqlschema.Labels{"global_account_id": tenant},
+		}, tenant)
+		require.NoError(t, err)
+		server.Close()
+
+		// when
+		replayer, err := NewReplayer(dir)
+		require.NoError(t, err)
+
+		_, createErr := newDirectorClient(server, replayer.Wrap).CreateRuntime(&gqlschema.RuntimeInput{
+			Name:   "my-runtime-efgh",
+			Labels: gqlschema.Labels{"global_account_id": "other-account"},
+		}, tenant)
+
+		// then
+		require.Error(t, createErr)
+	})
+
+	t.Run("should continue numbering after exchanges already recorded", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		server := fake.NewServer(fake.Config{})
+		defer server.Close()
+
+		for range 2 {
+			recorder, err := NewRecorder(dir, logrus.New())
+			require.NoError(t, err)
+
+			// when
+			_, err = newDirectorClient(server, recorder.Wrap).CreateRuntime(&gqlschema.RuntimeInput{Name: "my-runtime"}, tenant)
+			require.NoError(t, err)
+		}
+
+		// then
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, files, 4)
+		assert.True(t, strings.HasPrefix(files[3].Name(), "000004-post-127.0.0.1"))
+	})
+
+	t.Run("should replay exchanges in the recorded order when matching only method and URL", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		require.NoError(t, writeExchange(filepath.Join(dir, "000001.json"), Exchange{
+			Request:  Request{Method: http.MethodPost, URL: "https://director/graphql", Body: "first"},
+			Response: Response{StatusCode: http.StatusOK, Body: "1"},
+		}))
+		require.NoError(t, writeExchange(filepath.Join(dir, "000002.json"), Exchange{
+			Request:  Request{Method: http.MethodPost, URL: "https://director/graphql", Body: "second"},
+			Response: Response{StatusCode: http.StatusBadGateway, Body: "2"},
+		}))
+
+		replayer, err := NewReplayer(dir, WithMatcher(MatchMethodAndURL))
+		require.NoError(t, err)
+		httpClient := &http.Client{Transport: replayer.Wrap(nil)}
+
+		// when
+		first, err := httpClient.Post("https://director/graphql", "text/plain", strings.NewReader("other"))
+		require.NoError(t, err)
+		defer first.Body.Close()
+		second, err := httpClient.Post("https://director/graphql", "text/plain", strings.NewReader("other"))
+		require.NoError(t, err)
+		defer second.Body.Close()
+
+		// then
+		assert.Equal(t, http.StatusOK, first.StatusCode)
+		assert.Equal(t, http.StatusBadGateway, second.StatusCode)
+		assert.Empty(t, replayer.Unused())
+	})
+}
+
+func newDirectorClient(server *fake.Server, transport func(http.RoundTripper) http.RoundTripper) director.Client {
+	httpClient := &http.Client{Transport: transport(http.DefaultTransport), Timeout: 5 * time.Second}
+	oauthClient := oauth.NewOauthClient(httpClient, fake.DefaultClientID, fake.DefaultClientSecret, server.TokensEndpoint())
+	gqlClient := graphql.NewGraphQLClient(server.DirectorURL(), false, false, graphql.WithTransport(transport))
+	return director.NewDirectorClient(gqlClient, oauthClient)
+}
@@ -0,0 +1,184 @@
+package recording
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// generatedValue replaces values generated anew for every request when GraphQL requests are compared
+const generatedValue = "GENERATED"
+
+// generatedInputFields are fields of GraphQL input objects, by the operation they're passed to, which are generated for every request,
+// e.g. the random suffix of names of registered runtimes
+var generatedInputFields = map[string]string{ //nolint:gochecknoglobals
+	"registerRuntime": "name",
+}
+
+// Matcher tells if the recorded request answers the request being replayed, both are redacted
+type Matcher func(recorded, actual Request) bool
+
+// MatchMethodURLAndBody is the default Matcher. GraphQL requests match when they run the same operations with the same arguments and variables,
+// regardless of formatting and of generated values, such as names of registered runtimes. Other bodies must be equal.
+func MatchMethodURLAndBody(recorded, actual Request) bool {
+	return MatchMethodAndURL(recorded, actual) && normalizeBody(recorded.Body) == normalizeBody(actual.Body)
+}
+
+// MatchMethodAndURL ignores the request body, responses are replayed in the recorded order
+func MatchMethodAndURL(recorded, actual Request) bool {
+	return recorded.Method == actual.Method && recorded.URL == actual.URL
+}
+
+type ReplayerOption func(*Replayer)
+
+func WithMatcher(matcher Matcher) ReplayerOption {
+	return func(r *Replayer) {
+		r.match = matcher
+	}
+}
+
+// Replayer answers requests with exchanges read from a directory instead of sending them.
+// Each recorded exchange is replayed once, the first unused one matching the request is taken.
+type Replayer struct {
+	match Matcher
+
+	mu        sync.Mutex
+	exchanges []Exchange
+	names     []string
+	used      []bool
+}
+
+func NewReplayer(dir string, opts ...ReplayerOption) (*Replayer, error) {
+	exchanges, names, err := readExchanges(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Replayer{
+		match:     MatchMethodURLAndBody,
+		exchanges: exchanges,
+		names:     names,
+		used:      make([]bool, len(exchanges)),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
+}
+
+// Wrap returns a transport replaying recorded exchanges, next is never called
+func (r *Replayer) Wrap(_ http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(r.roundTrip)
+}
+
+// Unused returns files of exchanges that haven't been replayed
+func (r *Replayer) Unused() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []string
+	for i, used := range r.used {
+		if !used {
+			unused = append(unused, r.names[i])
+		}
+	}
+	return unused
+}
+
+func (r *Replayer) roundTrip(req *http.Request) (*http.Response, error) {
+	request, err := readRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	exchange, err := r.next(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// redaction may have changed the length of the recorded body
+	header := exchange.Response.Header.Clone()
+	header.Del("Content-Length")
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", exchange.Response.StatusCode, http.StatusText(exchange.Response.StatusCode)),
+		StatusCode:    exchange.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(exchange.Response.Body)),
+		ContentLength: int64(len(exchange.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Replayer) next(request Request) (Exchange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, exchange := range r.exchanges {
+		if !r.used[i] && r.match(exchange.Request, request) {
+			r.used[i] = true
+			return exchange, nil
+		}
+	}
+	return Exchange{}, errors.Errorf("no recorded exchange left for %s %s", request.Method, request.URL)
+}
+
+// normalizeBody returns the GraphQL request with the document formatted and generated values replaced, other bodies are returned unchanged
+func normalizeBody(body string) string {
+	var request struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if err := json.Unmarshal([]byte(body), &request); err != nil || request.Query == "" {
+		return body
+	}
+
+	document, err := parser.ParseQuery(&ast.Source{Input: request.Query})
+	if err != nil {
+		return body
+	}
+	for _, operation := range document.Operations {
+		for _, selection := range operation.SelectionSet {
+			field, ok := selection.(*ast.Field)
+			if !ok {
+				continue
+			}
+			if name, generated := generatedInputFields[field.Name]; generated {
+				replaceGeneratedFields(field.Arguments, name)
+			}
+		}
+	}
+
+	query := &bytes.Buffer{}
+	formatter.NewFormatter(query).FormatQueryDocument(document)
+	variables, err := json.Marshal(request.Variables)
+	if err != nil {
+		return body
+	}
+	return query.String() + string(variables)
+}
+
+func replaceGeneratedFields(arguments ast.ArgumentList, name string) {
+	for _, argument := range arguments {
+		if argument.Value == nil || argument.Value.Kind != ast.ObjectValue {
+			continue
+		}
+		for _, child := range argument.Value.Children {
+			if child.Name == name {
+				child.Value = &ast.Value{Kind: ast.StringValue, Raw: generatedValue}
+			}
+		}
+	}
+}
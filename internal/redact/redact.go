@@ -0,0 +1,117 @@
+// Package redact removes credentials and tokens from HTTP exchanges with Director and its tokens endpoint before they're logged or stored.
+package redact
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces credentials and tokens
+const Redacted = "REDACTED"
+
+// sensitiveHeaders are replaced as a whole
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"} //nolint:gochecknoglobals
+
+// sensitiveFields are replaced in JSON bodies at any depth, in form bodies and in query parameters.
+// They cover OAuth client credentials, issued access tokens and one-time tokens for runtimes.
+var sensitiveFields = map[string]bool{ //nolint:gochecknoglobals
+	"client_secret":    true,
+	"client_assertion": true,
+	"access_token":     true,
+	"refresh_token":    true,
+	"id_token":         true,
+	"token":            true,
+	"raw":              true,
+	"rawEncoded":       true,
+}
+
+// Header returns a copy of the header with credentials replaced
+func Header(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	redacted := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, Redacted)
+		}
+	}
+	return redacted
+}
+
+// URL returns the URL with credentials in query parameters replaced
+func URL(u *url.URL) string {
+	query := u.Query()
+	if !redactValues(query) {
+		return u.String()
+	}
+
+	redacted := *u
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
+
+// Body returns the JSON or form body with credentials and tokens replaced, other bodies are returned unchanged
+func Body(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil || !redactValues(values) {
+			return string(body)
+		}
+		return values.Encode()
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") || json.Valid(body):
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil || !redactJSON(value) {
+			return string(body)
+		}
+		redacted, err := json.Marshal(value)
+		if err != nil {
+			return string(body)
+		}
+		return string(redacted)
+	default:
+		return string(body)
+	}
+}
+
+func redactValues(values url.Values) bool {
+	redacted := false
+	for key := range values {
+		if sensitiveFields[key] {
+			values.Set(key, Redacted)
+			redacted = true
+		}
+	}
+	return redacted
+}
+
+// redactJSON replaces sensitive fields in place and tells if anything was replaced
+func redactJSON(value interface{}) bool {
+	redacted := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if _, isString := field.(string); isString && sensitiveFields[key] {
+				v[key] = Redacted
+				redacted = true
+				continue
+			}
+			redacted = redactJSON(field) || redacted
+		}
+	case []interface{}:
+		for _, item := range v {
+			redacted = redactJSON(item) || redacted
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,67 @@
+package redact
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	t.Run("should redact credentials in headers", func(t *testing.T) {
+		// given
+		header := http.Header{"Authorization": {"Bearer secret"}, "Tenant": {"tenant"}}
+
+		// when
+		redacted := Header(header)
+
+		// then
+		assert.Equal(t, Redacted, redacted.Get("Authorization"))
+		assert.Equal(t, "tenant", redacted.Get("Tenant"))
+		assert.Equal(t, "Bearer secret", header.Get("Authorization"))
+	})
+
+	t.Run("should redact credentials in form body", func(t *testing.T) {
+		// when
+		redacted := Body("application/x-www-form-urlencoded", []byte("grant_type=client_credentials&client_id=id&client_secret=secret"))
+
+		// then
+		values, err := url.ParseQuery(redacted)
+		require.NoError(t, err)
+		assert.Equal(t, Redacted, values.Get("client_secret"))
+		assert.Equal(t, "id", values.Get("client_id"))
+	})
+
+	t.Run("should redact tokens in nested JSON body", func(t *testing.T) {
+		// when
+		redacted := Body("application/json", []byte(`{"data":{"result":{"token":"secret","connectorURL":"https://connector"}}}`))
+
+		// then
+		assert.JSONEq(t, `{"data":{"result":{"token":"REDACTED","connectorURL":"https://connector"}}}`, redacted)
+	})
+
+	t.Run("should redact credentials in query parameters", func(t *testing.T) {
+		// given
+		u, err := url.Parse("https://director/oauth2/token?access_token=secret&scope=runtime")
+		require.NoError(t, err)
+
+		// when
+		redacted := URL(u)
+
+		// then
+		assert.Equal(t, "https://director/oauth2/token?access_token=REDACTED&scope=runtime", redacted)
+	})
+
+	t.Run("should keep bodies without credentials unchanged", func(t *testing.T) {
+		// given
+		body := `{"query":"query { result: runtime(id: \"id\") { id } }"}`
+
+		// when
+		redacted := Body("application/json", []byte(body))
+
+		// then
+		assert.Equal(t, body, redacted)
+	})
+}
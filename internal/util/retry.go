@@ -15,6 +15,10 @@ func RetryOnError(interval time.Duration, count int, errMsgFmt string, function
 			return nil
 		}
 		logrus.Errorf(errMsgFmt, err.Error())
+		// retrying makes no sense while calls to Director are short-circuited
+		if err.Reason() == apperrors.ErrDirectorCircuitOpen {
+			return err
+		}
 		time.Sleep(interval)
 	}
 	return err
@@ -18,6 +18,22 @@ func TestRetryOnError(t *testing.T) {
 		// then
 		require.NoError(t, err)
 	})
+
+	t.Run("should not retry function when Director circuit is open", func(t *testing.T) {
+		// given
+		calls := 0
+		function := func() apperrors.AppError {
+			calls++
+			return apperrors.Unavailable("circuit open").SetReason(apperrors.ErrDirectorCircuitOpen)
+		}
+
+		// when
+		err := RetryOnError(1, 3, "function call returned error: %s", function)
+
+		// then
+		require.Error(t, err)
+		require.Equal(t, 1, calls)
+	})
 }
 
 type tester struct {
@@ -1,20 +1,27 @@
 package main
 
 import (
-	"crypto/tls"
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/kyma-project/compass-manager/api/v1beta1"
 	"github.com/kyma-project/compass-manager/controllers"
 	"github.com/kyma-project/compass-manager/controllers/metrics"
+	"github.com/kyma-project/compass-manager/internal/api"
+	"github.com/kyma-project/compass-manager/internal/apperrors"
+	"github.com/kyma-project/compass-manager/internal/connector"
 	"github.com/kyma-project/compass-manager/internal/director"
+	"github.com/kyma-project/compass-manager/internal/directorconfig"
 	"github.com/kyma-project/compass-manager/internal/graphql"
 	"github.com/kyma-project/compass-manager/internal/oauth"
+	"github.com/kyma-project/compass-manager/internal/recording"
 	kyma "github.com/kyma-project/lifecycle-manager/api/v1beta2"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -22,8 +29,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	k8slabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
-	"k8s.io/apimachinery/pkg/util/yaml"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -40,28 +47,49 @@ var (
 )
 
 type config struct {
-	Address                      string `envconfig:"default=127.0.0.1:3000"`
-	APIEndpoint                  string `envconfig:"default=/graphql"`
-	SkipDirectorCertVerification bool   `envconfig:"default=false"`
-	DirectorURL                  string `envconfig:"APP_DIRECTOR_URL,default=https://compass-gateway-auth-oauth.cmp-main.dev.kyma.cloud.sap/director/graphql"`
-	DirectorOAuthPath            string `envconfig:"APP_DIRECTOR_OAUTH_PATH,default=./dev/director.yaml"`
-	ConnectorURLPattern          string `envconfig:"APP_CONNECTOR_URL_PATTERN,default=kyma.cloud.sap/connector/graphql"`
-	EnabledRegistration          bool   `envconfig:"APP_ENABLED_REGISTRATION,default=false"`
-	DryRun                       bool   `envconfig:"APP_DRYRUN,default=false"`
+	Address     string `envconfig:"default=127.0.0.1:3000"`
+	APIEndpoint string `envconfig:"default=/graphql"`
+	// Config holds the settings of the Director, cmctl reads the same envs
+	directorconfig.Config
+	EnabledRegistration bool `envconfig:"APP_ENABLED_REGISTRATION,default=false"`
+	// ConnectorTokenValidation makes the Connector of every Director checked, over verified TLS, to accept one-time tokens of the Director before they're written to Runtimes
+	ConnectorTokenValidation bool `envconfig:"APP_CONNECTOR_TOKEN_VALIDATION,default=false"`
+	// RegisterInSubaccount registers runtimes under the subaccount tenant of the Kyma instead of its Global Account
+	RegisterInSubaccount bool `envconfig:"APP_REGISTER_IN_SUBACCOUNT,default=false"`
+	// DefaultFormations are Compass formations, separated with commas, every registered runtime is assigned to
+	DefaultFormations []string `envconfig:"APP_DEFAULT_FORMATIONS,optional"`
+	DryRun            bool     `envconfig:"APP_DRYRUN,default=false"`
+	// DirectorBreakerFailureThreshold is the number of consecutive failed Director calls that opens the circuit breaker, 0 disables the breaker
+	DirectorBreakerFailureThreshold int           `envconfig:"APP_DIRECTOR_BREAKER_FAILURE_THRESHOLD,default=5"`
+	DirectorBreakerOpenTimeout      time.Duration `envconfig:"APP_DIRECTOR_BREAKER_OPEN_TIMEOUT,default=30s"`
+	// Client-side request budget toward Director, 0 disables the given limit
+	DirectorRateLimit              float64 `envconfig:"APP_DIRECTOR_RATE_LIMIT,default=10"`
+	DirectorRateBurst              int     `envconfig:"APP_DIRECTOR_RATE_BURST,default=20"`
+	DirectorAccountRateLimit       float64 `envconfig:"APP_DIRECTOR_ACCOUNT_RATE_LIMIT,default=2"`
+	DirectorAccountRateBurst       int     `envconfig:"APP_DIRECTOR_ACCOUNT_RATE_BURST,default=5"`
+	DirectorMaxConcurrentMutations int     `envconfig:"APP_DIRECTOR_MAX_CONCURRENT_MUTATIONS,default=5"`
+	// DirectorSchemaCheck makes readiness fail until operations sent to Director are validated against its schema
+	DirectorSchemaCheck              bool          `envconfig:"APP_DIRECTOR_SCHEMA_CHECK,default=true"`
+	DirectorSchemaCheckRetryInterval time.Duration `envconfig:"APP_DIRECTOR_SCHEMA_CHECK_RETRY_INTERVAL,default=30s"`
+	// AssignmentsReadInterval is how often formations and applications of registered runtimes are read into mapping statuses, 0 disables reading them
+	AssignmentsReadInterval time.Duration `envconfig:"APP_ASSIGNMENTS_READ_INTERVAL,default=10m"`
+	// StuckKymaThreshold is how long a mapping can be Processing or Failed before its Kyma is counted as stuck, 0 disables the metric
+	StuckKymaThreshold time.Duration `envconfig:"APP_STUCK_KYMA_THRESHOLD,default=15m"`
+	// MetricsKymaNames labels cm_states and cm_actions with Kyma names, otherwise states are counted by global account in cm_mapping_states
+	MetricsKymaNames bool `envconfig:"APP_METRICS_KYMA_NAMES,default=true"`
+	// Exchanges with Director and its tokens endpoint are recorded to, or replayed from, the directory, with credentials redacted
+	DirectorRecordDir string `envconfig:"APP_DIRECTOR_RECORD_DIR,optional"`
+	DirectorReplayDir string `envconfig:"APP_DIRECTOR_REPLAY_DIR,optional"`
+	// Levels of Director request logs, the request and response bodies are logged too at the trace level
+	LogLevel                      string `envconfig:"APP_LOG_LEVEL,default=info"`
+	DirectorRequestLogLevel       string `envconfig:"APP_DIRECTOR_REQUEST_LOG_LEVEL,default=debug"`
+	DirectorFailedRequestLogLevel string `envconfig:"APP_DIRECTOR_FAILED_REQUEST_LOG_LEVEL,default=warn"`
 }
 
 func (c *config) String() string {
-	return fmt.Sprintf("Address: %s, APIEndpoint: %s, DirectorURL: %s, SkipDirectorCertVerification: %v, DirectorOAuthPath: %s",
+	return fmt.Sprintf("Address: %s, APIEndpoint: %s, DirectorURL: %s, SkipDirectorCertVerification: %v, DirectorAuthMode: %s, DirectorOAuthPath: %s, DirectorCertPath: %s",
 		c.Address, c.APIEndpoint, c.DirectorURL,
-		c.SkipDirectorCertVerification, c.DirectorOAuthPath)
-}
-
-type DirectorOAuth struct {
-	Data struct {
-		ClientID       string `json:"client_id"`
-		ClientSecret   string `json:"client_secret"`
-		TokensEndpoint string `json:"tokens_endpoint"`
-	} `json:"data"`
+		c.SkipDirectorCertVerification, c.DirectorAuthMode, c.DirectorOAuthPath, c.DirectorCertPath)
 }
 
 func init() {
@@ -92,6 +120,9 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	directorsCfg, err := directorconfig.Load(cfg.Config)
+	exitOnError(err, "Failed to load Directors config")
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
@@ -100,7 +131,7 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "2647ec81.kyma-project.io",
-		Cache:                  setCacheOptions(),
+		Cache:                  setCacheOptions(secretNamespaces(directorsCfg, cfg)),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -108,9 +139,17 @@ func main() {
 	}
 
 	log := logrus.New()
-	log.SetLevel(logrus.InfoLevel)
+	logLevel, err := logrus.ParseLevel(cfg.LogLevel)
+	exitOnError(err, "Failed to parse log level")
+	log.SetLevel(logLevel)
+
+	var metricsOpts []metrics.Option
+	if !cfg.MetricsKymaNames {
+		metricsOpts = append(metricsOpts, metrics.WithoutKymaNames())
+	}
+	metrics := metrics.NewMetrics(metricsOpts...)
 
-	directorClient, err := newDirectorClient(cfg)
+	directorRegistry, err := newDirectorRegistry(directorsCfg, cfg, mgr, log, metrics)
 	if err != nil {
 		setupLog.Error(err, "unable to create Director Client")
 		os.Exit(1)
@@ -118,19 +157,24 @@ func main() {
 
 	var compassRegistrator controllers.Registrator
 	var runtimeAgentConfigurator controllers.Configurator
+	var directors controllers.Directors = directorRegistry
 
 	if cfg.DryRun {
+		directors = nil
 		dry := controllers.NewDryRunner(log)
 		compassRegistrator = dry
 		runtimeAgentConfigurator = dry
 	} else {
-		compassRegistrator = controllers.NewCompassRegistrator(directorClient, log)
-		runtimeAgentConfigurator = controllers.NewRuntimeAgentConfigurator(directorClient, cfg.ConnectorURLPattern, log)
+		compassRegistrator = controllers.NewCompassRegistrator(directorRegistry, log)
+		var connectorClient connector.Client
+		if cfg.ConnectorTokenValidation {
+			connectorClient = connector.NewConnectorClient(nil)
+		}
+		runtimeAgentConfigurator = controllers.NewRuntimeAgentConfigurator(directorRegistry, log, connectorClient)
 	}
 
 	requeueTime := time.Second * 5              //nolint:mnd
 	requeueTimeForKubeconfig := time.Minute * 3 //nolint:mnd
-	metrics := metrics.NewMetrics()
 
 	compassManagerReconciler := controllers.NewCompassManagerReconciler(
 		mgr,
@@ -140,13 +184,44 @@ func main() {
 		requeueTime,
 		requeueTimeForKubeconfig,
 		cfg.EnabledRegistration,
+		cfg.RegisterInSubaccount,
+		cfg.DefaultFormations,
 		cfg.DryRun,
 		metrics,
+		directors,
 	)
 	if err = compassManagerReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "CompassManager")
 		os.Exit(1)
 	}
+
+	// Runtimes are read from Director by the API unless it's a dry run
+	var apiDirectors *director.Registry
+	if !cfg.DryRun {
+		apiDirectors = directorRegistry
+	}
+	// State changes of mappings are streamed to API subscribers from the informer of the controller
+	mappingEvents := api.NewMappingEvents(log)
+	mappingInformer, err := mgr.GetCache().GetInformer(context.Background(), &v1beta1.CompassManagerMapping{})
+	if err == nil {
+		_, err = mappingInformer.AddEventHandler(mappingEvents)
+	}
+	if err != nil {
+		setupLog.Error(err, "unable to watch Compass Manager Mappings for GraphQL API subscriptions")
+		os.Exit(1)
+	}
+	apiResolver := api.NewResolver(mgr.GetAPIReader(), compassManagerReconciler, apiDirectors, mappingEvents, "kcp-system", log)
+	apiAuth := api.NewKubernetesAuth(mgr.GetClient(), "kcp-system")
+	if err := mgr.Add(api.NewServer(cfg.Address, cfg.APIEndpoint, apiResolver, apiAuth, log)); err != nil {
+		setupLog.Error(err, "unable to set up GraphQL API")
+		os.Exit(1)
+	}
+	if !cfg.MetricsKymaNames {
+		metrics.RegisterMappingStates(mgr.GetClient(), "kcp-system", controllers.LabelGlobalAccountID)
+	}
+	if cfg.StuckKymaThreshold > 0 {
+		metrics.RegisterStuckKymas(mgr.GetClient(), "kcp-system", cfg.StuckKymaThreshold)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -157,6 +232,19 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if cfg.DirectorSchemaCheck && !cfg.DryRun {
+		if err := addDirectorSchemaChecks(mgr, directorRegistry, cfg.DirectorSchemaCheckRetryInterval); err != nil {
+			setupLog.Error(err, "unable to set up Director schema check")
+			os.Exit(1)
+		}
+	}
+	if cfg.AssignmentsReadInterval > 0 && !cfg.DryRun {
+		assignmentsReader := controllers.NewAssignmentsReader(mgr.GetClient(), log, directorRegistry, cfg.AssignmentsReadInterval)
+		if err := mgr.Add(assignmentsReader); err != nil {
+			setupLog.Error(err, "unable to set up reading of Compass assignments")
+			os.Exit(1)
+		}
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -165,31 +253,195 @@ func main() {
 	}
 }
 
-func newDirectorClient(config config) (director.Client, error) {
-	file, err := os.ReadFile(config.DirectorOAuthPath)
+// secretNamespaces returns the namespaces of Secrets read by Compass Manager, including Secrets with OAuth credentials of the Directors
+func secretNamespaces(directorsCfg directorconfig.Directors, config config) []string {
+	namespaces := []string{"kcp-system"}
+	for _, directorCfg := range directorsCfg.Directors {
+		cfg := directorCfg.Apply(config.Config)
+		if cfg.DirectorAuthMode != directorconfig.AuthModeOAuth || cfg.DirectorOAuthSecretName == "" || slices.Contains(namespaces, cfg.DirectorOAuthSecretNamespace) {
+			continue
+		}
+		namespaces = append(namespaces, cfg.DirectorOAuthSecretNamespace)
+	}
+	return namespaces
+}
+
+// newDirectorRegistry creates a Director client for every Director of the config
+func newDirectorRegistry(directorsCfg directorconfig.Directors, config config, mgr ctrl.Manager, log *logrus.Logger, metrics metrics.Metrics) (*director.Registry, error) {
+	return directorsCfg.NewRegistry(config.Config, func(name string, directorCfg directorconfig.Config) (director.Endpoint, error) {
+		endpointCfg := config
+		endpointCfg.Config = directorCfg
+		return newDirectorEndpoint(name, endpointCfg, mgr, log, metrics)
+	})
+}
+
+// newDirectorEndpoint creates a Director client with its own circuit breaker and request budget
+func newDirectorEndpoint(name string, config config, mgr ctrl.Manager, log *logrus.Logger, metrics metrics.Metrics) (director.Endpoint, error) {
+	endpoint := director.Endpoint{
+		Name:                name,
+		ConnectorURLPattern: config.ConnectorURLPattern,
+	}
+
+	var opts []director.Option
+	if config.DirectorBreakerFailureThreshold > 0 {
+		endpoint.Breaker = director.NewCircuitBreaker(config.DirectorBreakerFailureThreshold, config.DirectorBreakerOpenTimeout, func(state director.BreakerState) {
+			metrics.UpdateDirectorCircuitState(name, state)
+		})
+		opts = append(opts, director.WithCircuitBreaker(endpoint.Breaker))
+	}
+	opts = append(opts, director.WithBatchSize(config.DirectorBatchSize))
+	opts = append(opts, director.WithThrottle(director.NewThrottle(director.ThrottleConfig{
+		RequestsPerSecond:        config.DirectorRateLimit,
+		Burst:                    config.DirectorRateBurst,
+		AccountRequestsPerSecond: config.DirectorAccountRateLimit,
+		AccountBurst:             config.DirectorAccountRateBurst,
+		MaxConcurrentMutations:   config.DirectorMaxConcurrentMutations,
+	}, func(limit string, waited time.Duration) {
+		metrics.ObserveDirectorThrottleWait(name, limit, waited)
+	})))
+	opts = append(opts, director.WithObserver(director.Observer{
+		OnRequest: func(operation string, duration time.Duration) {
+			metrics.ObserveDirectorRequest(name, operation, duration)
+		},
+		OnError: func(operation string, err apperrors.AppError) {
+			metrics.IncDirectorError(name, operation, err)
+		},
+		OnOneTimeToken: func() {
+			metrics.IncOneTimeTokens(name)
+		},
+	}))
+	tokenFetchObserver := oauth.WithFetchObserver(func(duration time.Duration, err apperrors.AppError) {
+		metrics.ObserveTokenFetch(name, duration, err)
+	})
+
+	transport, err := newDirectorTransport(name, config, log)
 	if err != nil {
-		return nil, errors.Wrap(err, "Failed to open director config")
+		return director.Endpoint{}, err
 	}
 
-	cfg := DirectorOAuth{}
-	err = yaml.Unmarshal(file, &cfg)
+	client, err := newDirectorClient(name, config, mgr, log, transport, tokenFetchObserver, opts...)
 	if err != nil {
-		return nil, errors.Wrap(err, "Failed to unmarshal director config")
+		return director.Endpoint{}, err
 	}
+	endpoint.Client = client
+
+	return endpoint, nil
+}
 
-	gqlClient := graphql.NewGraphQLClient(config.DirectorURL, true, config.SkipDirectorCertVerification)
-	oauthClient := oauth.NewOauthClient(newHTTPClient(config.SkipDirectorCertVerification), cfg.Data.ClientID, cfg.Data.ClientSecret, cfg.Data.TokensEndpoint)
+// addDirectorSchemaChecks validates the schema of every Director at startup, readiness fails until it's compatible
+func addDirectorSchemaChecks(mgr ctrl.Manager, directors *director.Registry, retryInterval time.Duration) error {
+	for _, endpoint := range directors.Endpoints() {
+		check := director.NewSchemaCheck(endpoint.Client, retryInterval)
+		if err := mgr.Add(check); err != nil {
+			return err
+		}
 
-	return director.NewDirectorClient(gqlClient, oauthClient), nil
+		checkName := "director-schema"
+		if endpoint.Name != "" {
+			checkName += "-" + endpoint.Name
+		}
+		if err := mgr.AddReadyzCheck(checkName, check.Check); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func newHTTPClient(skipCertVerification bool) *http.Client {
-	return &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: skipCertVerification},
-		},
-		Timeout: 30 * time.Second, //nolint:mnd
+// newDirectorTransport returns the wrapper of transports to Director and its tokens endpoint recording or replaying exchanges.
+// Every Director has its own subdirectory when multiple Directors are configured.
+func newDirectorTransport(name string, config config, log *logrus.Logger) (func(http.RoundTripper) http.RoundTripper, error) {
+	dir := func(dir string) string {
+		if name == "" {
+			return dir
+		}
+		return filepath.Join(dir, name)
+	}
+
+	switch {
+	case config.DirectorRecordDir != "" && config.DirectorReplayDir != "":
+		return nil, errors.New("Director exchanges can't be recorded and replayed at the same time")
+	case config.DirectorRecordDir != "":
+		recorder, err := recording.NewRecorder(dir(config.DirectorRecordDir), log)
+		if err != nil {
+			return nil, err
+		}
+		log.Warnf("Recording Director exchanges to %s", dir(config.DirectorRecordDir))
+		return recorder.Wrap, nil
+	case config.DirectorReplayDir != "":
+		replayer, err := recording.NewReplayer(dir(config.DirectorReplayDir))
+		if err != nil {
+			return nil, err
+		}
+		log.Warnf("Replaying Director exchanges from %s", dir(config.DirectorReplayDir))
+		return replayer.Wrap, nil
+	default:
+		return func(transport http.RoundTripper) http.RoundTripper { return transport }, nil
+	}
+}
+
+func newDirectorClient(name string, config config, mgr ctrl.Manager, log *logrus.Logger, transport func(http.RoundTripper) http.RoundTripper, tokenFetchObserver oauth.Option, opts ...director.Option) (director.Client, error) {
+	gqlOpts, err := newGraphQLOptions(config, log, transport)
+	if err != nil {
+		return nil, err
+	}
+
+	var oauthOpts []oauth.Option
+	if config.DirectorAuthMode == directorconfig.AuthModeOAuth {
+		oauthOpts, err = directorconfig.NewOAuthOptions(config.Config)
+		if err != nil {
+			return nil, err
+		}
+		oauthOpts = append(oauthOpts, tokenFetchObserver)
+		if config.DirectorOAuthSecretName != "" {
+			return newReloadableOAuthDirectorClient(name, config, mgr, log, transport, gqlOpts, oauthOpts, opts...)
+		}
 	}
+	return directorconfig.NewClient(config.Config, transport, gqlOpts, oauthOpts, opts...)
+}
+
+// newReloadableOAuthDirectorClient creates a client with OAuth credentials kept in sync with a Secret.
+// Readiness fails while the credentials are missing, invalid or rejected by the tokens endpoint.
+func newReloadableOAuthDirectorClient(name string, config config, mgr ctrl.Manager, log *logrus.Logger, transport func(http.RoundTripper) http.RoundTripper, gqlOpts []graphql.Option, oauthOpts []oauth.Option, opts ...director.Option) (director.Client, error) {
+	oauthClient := oauth.NewReloadableOauthClient(directorconfig.NewHTTPClient(config.SkipDirectorCertVerification, transport), oauthOpts...)
+
+	secret := types.NamespacedName{Name: config.DirectorOAuthSecretName, Namespace: config.DirectorOAuthSecretNamespace}
+	checkName := "director-credentials"
+	if name != "" {
+		checkName += "-" + name
+	}
+
+	credentialsReconciler := controllers.NewDirectorCredentialsReconciler(mgr.GetClient(), log, secret, config.DirectorOAuthSecretKey, oauth.AuthMethod(config.DirectorOAuthAuthMethod), oauthClient)
+	credentialsReconciler.Name = checkName
+	if err := credentialsReconciler.SetupWithManager(mgr); err != nil {
+		return nil, errors.Wrap(err, "Failed to set up Director credentials controller")
+	}
+
+	err := mgr.AddReadyzCheck(checkName, func(_ *http.Request) error {
+		return oauthClient.CredentialsValid()
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to set up Director credentials ready check")
+	}
+
+	gqlClient := graphql.NewGraphQLClient(config.DirectorURL, true, config.SkipDirectorCertVerification, gqlOpts...)
+
+	return director.NewDirectorClient(gqlClient, oauthClient, opts...), nil
+}
+
+func newGraphQLOptions(config config, log *logrus.Logger, transport func(http.RoundTripper) http.RoundTripper) ([]graphql.Option, error) {
+	level, err := logrus.ParseLevel(config.DirectorRequestLogLevel)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to parse Director request log level")
+	}
+	failureLevel, err := logrus.ParseLevel(config.DirectorFailedRequestLogLevel)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to parse Director failed request log level")
+	}
+
+	return []graphql.Option{
+		graphql.WithTransport(transport),
+		graphql.WithLogger(log, level, failureLevel),
+	}, nil
 }
 
 func exitOnError(err error, context string) {
@@ -199,14 +451,18 @@ func exitOnError(err error, context string) {
 	}
 }
 
-func setCacheOptions() cache.Options {
+// setCacheOptions limits the cache to kcp-system, and Secrets to the namespaces they are read from
+func setCacheOptions(secretNamespaces []string) cache.Options {
+	secrets := map[string]cache.Config{}
+	for _, namespace := range secretNamespaces {
+		secrets[namespace] = cache.Config{}
+	}
+
 	return cache.Options{
 		ByObject: map[client.Object]cache.ByObject{
 			&corev1.Secret{}: {
-				Label: k8slabels.Everything(),
-				Namespaces: map[string]cache.Config{
-					"kcp-system": {},
-				},
+				Label:      k8slabels.Everything(),
+				Namespaces: secrets,
 			},
 			&kyma.Kyma{}: {
 				Namespaces: map[string]cache.Config{
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-project/compass-manager/internal/directorconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSetCacheOptions(t *testing.T) {
+	t.Run("should cache Secrets with OAuth credentials outside of kcp-system", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "directors.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`directors:
+- name: eu
+  default: true
+- name: us
+  oauthSecretName: us-credentials
+  oauthSecretNamespace: compass-system
+- name: cn
+  authMode: mtls
+  oauthSecretNamespace: ignored
+`), 0o600))
+		cfg := config{Config: directorconfig.Config{
+			DirectorsConfigPath:          path,
+			DirectorAuthMode:             directorconfig.AuthModeOAuth,
+			DirectorOAuthSecretName:      "director-credentials",
+			DirectorOAuthSecretNamespace: "director-system",
+		}}
+
+		directorsCfg, err := directorconfig.Load(cfg.Config)
+		require.NoError(t, err)
+
+		// when
+		options := setCacheOptions(secretNamespaces(directorsCfg, cfg))
+
+		// then
+		var namespaces []string
+		for object, byObject := range options.ByObject {
+			if _, ok := object.(*corev1.Secret); ok {
+				for namespace := range byObject.Namespaces {
+					namespaces = append(namespaces, namespace)
+				}
+			}
+		}
+		assert.ElementsMatch(t, []string{"kcp-system", "director-system", "compass-system"}, namespaces)
+	})
+
+	t.Run("should cache Secrets only in kcp-system without OAuth credentials in Secrets", func(t *testing.T) {
+		// given
+		cfg := config{Config: directorconfig.Config{DirectorAuthMode: directorconfig.AuthModeOAuth, DirectorOAuthSecretNamespace: "director-system"}}
+
+		directorsCfg, err := directorconfig.Load(cfg.Config)
+		require.NoError(t, err)
+
+		// when
+		namespaces := secretNamespaces(directorsCfg, cfg)
+
+		// then
+		assert.Equal(t, []string{"kcp-system"}, namespaces)
+	})
+}
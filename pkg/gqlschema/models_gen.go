@@ -2,8 +2,91 @@
 
 package gqlschema
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type Mapping struct {
+	KymaName        string            `json:"kymaName"`
+	GlobalAccount   string            `json:"globalAccount"`
+	Subaccount      *string           `json:"subaccount"`
+	RuntimeID       *string           `json:"runtimeID"`
+	Director        *string           `json:"director"`
+	Tenant          *string           `json:"tenant"`
+	Registered      bool              `json:"registered"`
+	Configured      bool              `json:"configured"`
+	State           *MappingState     `json:"state"`
+	RuntimeContexts []*RuntimeContext `json:"runtimeContexts"`
+	Formations      []string          `json:"formations"`
+}
+
+type MappingStateChange struct {
+	PreviousState *MappingState `json:"previousState"`
+	Mapping       *Mapping      `json:"mapping"`
+}
+
+type Runtime struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	Description      *string  `json:"description"`
+	Labels           Labels   `json:"labels"`
+	Formations       []string `json:"formations"`
+	ApplicationCount int      `json:"applicationCount"`
+	Applications     []string `json:"applications"`
+}
+
+type RuntimeContext struct {
+	ID         string `json:"id"`
+	Subaccount string `json:"subaccount"`
+}
+
 type RuntimeInput struct {
 	Name        string  `json:"name"`
 	Description *string `json:"description"`
 	Labels      Labels  `json:"labels"`
 }
+
+type MappingState string
+
+const (
+	MappingStateReady      MappingState = "Ready"
+	MappingStateProcessing MappingState = "Processing"
+	MappingStateFailed     MappingState = "Failed"
+)
+
+var AllMappingState = []MappingState{
+	MappingStateReady,
+	MappingStateProcessing,
+	MappingStateFailed,
+}
+
+func (e MappingState) IsValid() bool {
+	switch e {
+	case MappingStateReady, MappingStateProcessing, MappingStateFailed:
+		return true
+	}
+	return false
+}
+
+func (e MappingState) String() string {
+	return string(e)
+}
+
+func (e *MappingState) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MappingState(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MappingState", str)
+	}
+	return nil
+}
+
+func (e MappingState) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -85,13 +85,41 @@ func (c *Client) logf(format string, args ...interface{}) {
 // If the request fails or the server returns an error, the first error
 // will be returned.
 func (c *Client) Run(ctx context.Context, req *Request, resp interface{}) error {
+	gqlErrors, err := c.run(ctx, req, resp)
+	if err != nil {
+		return err
+	}
+	if len(gqlErrors) > 0 {
+		// return first error
+		return gqlErrors[0]
+	}
+	return nil
+}
+
+// RunPartial executes the query like Run, but returns the errors of all fields
+// instead of the first one. The data of fields that succeeded is unmarshalled
+// into the response object even when other fields failed.
+// The error is returned only if the request itself failed.
+func (c *Client) RunPartial(ctx context.Context, req *Request, resp interface{}) ([]FieldError, error) {
+	gqlErrors, err := c.run(ctx, req, resp)
+	if err != nil {
+		return nil, err
+	}
+	fieldErrors := make([]FieldError, 0, len(gqlErrors))
+	for _, gqlErr := range gqlErrors {
+		fieldErrors = append(fieldErrors, gqlErr)
+	}
+	return fieldErrors, nil
+}
+
+func (c *Client) run(ctx context.Context, req *Request, resp interface{}) ([]graphError, error) {
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return nil, ctx.Err()
 	default:
 	}
 	if len(req.files) > 0 && !c.useMultipartForm {
-		return errors.New("cannot send files with PostFields option")
+		return nil, errors.New("cannot send files with PostFields option")
 	}
 	if c.useMultipartForm {
 		return c.runWithPostFields(ctx, req, resp)
@@ -99,7 +127,7 @@ func (c *Client) Run(ctx context.Context, req *Request, resp interface{}) error
 	return c.runWithJSON(ctx, req, resp)
 }
 
-func (c *Client) runWithJSON(ctx context.Context, req *Request, resp interface{}) error {
+func (c *Client) runWithJSON(ctx context.Context, req *Request, resp interface{}) ([]graphError, error) {
 	var requestBody bytes.Buffer
 	requestBodyObj := struct {
 		Query     string                 `json:"query"`
@@ -109,7 +137,7 @@ func (c *Client) runWithJSON(ctx context.Context, req *Request, resp interface{}
 		Variables: req.vars,
 	}
 	if err := json.NewEncoder(&requestBody).Encode(requestBodyObj); err != nil {
-		return errors.Wrap(err, "encode body")
+		return nil, errors.Wrap(err, "encode body")
 	}
 	c.logf(">> variables: %v", req.vars)
 	c.logf(">> query: %s", req.q)
@@ -118,7 +146,7 @@ func (c *Client) runWithJSON(ctx context.Context, req *Request, resp interface{}
 	}
 	r, err := http.NewRequest(http.MethodPost, c.endpoint, &requestBody)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	r.Close = c.closeReq
 	r.Header.Set("Content-Type", "application/json; charset=utf-8")
@@ -132,7 +160,7 @@ func (c *Client) runWithJSON(ctx context.Context, req *Request, resp interface{}
 	r = r.WithContext(ctx)
 	res, err := c.httpClient.Do(r)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -142,49 +170,45 @@ func (c *Client) runWithJSON(ctx context.Context, req *Request, resp interface{}
 	}(res.Body)
 	var buf bytes.Buffer
 	if _, err := io.Copy(&buf, res.Body); err != nil {
-		return errors.Wrap(err, "reading body")
+		return nil, errors.Wrap(err, "reading body")
 	}
 	c.logf("<< %s", buf.String())
 	if err := json.NewDecoder(&buf).Decode(&gr); err != nil {
 		if res.StatusCode != http.StatusOK {
-			return fmt.Errorf("graphql: server returned a non-200 status code: %v", res.StatusCode)
+			return nil, fmt.Errorf("graphql: server returned a non-200 status code: %v", res.StatusCode)
 		}
-		return errors.Wrap(err, "decoding response")
-	}
-	if len(gr.Errors) > 0 {
-		// return first error
-		return gr.Errors[0]
+		return nil, errors.Wrap(err, "decoding response")
 	}
-	return nil
+	return gr.Errors, nil
 }
 
-func (c *Client) runWithPostFields(ctx context.Context, req *Request, resp interface{}) error {
+func (c *Client) runWithPostFields(ctx context.Context, req *Request, resp interface{}) ([]graphError, error) {
 	var requestBody bytes.Buffer
 	writer := multipart.NewWriter(&requestBody)
 	if err := writer.WriteField("query", req.q); err != nil {
-		return errors.Wrap(err, "write query field")
+		return nil, errors.Wrap(err, "write query field")
 	}
 	var variablesBuf bytes.Buffer
 	if len(req.vars) > 0 {
 		variablesField, err := writer.CreateFormField("variables")
 		if err != nil {
-			return errors.Wrap(err, "create variables field")
+			return nil, errors.Wrap(err, "create variables field")
 		}
 		if err := json.NewEncoder(io.MultiWriter(variablesField, &variablesBuf)).Encode(req.vars); err != nil {
-			return errors.Wrap(err, "encode variables")
+			return nil, errors.Wrap(err, "encode variables")
 		}
 	}
 	for i := range req.files {
 		part, err := writer.CreateFormFile(req.files[i].Field, req.files[i].Name)
 		if err != nil {
-			return errors.Wrap(err, "create form file")
+			return nil, errors.Wrap(err, "create form file")
 		}
 		if _, err := io.Copy(part, req.files[i].R); err != nil {
-			return errors.Wrap(err, "preparing file")
+			return nil, errors.Wrap(err, "preparing file")
 		}
 	}
 	if err := writer.Close(); err != nil {
-		return errors.Wrap(err, "close writer")
+		return nil, errors.Wrap(err, "close writer")
 	}
 	c.logf(">> variables: %s", variablesBuf.String())
 	c.logf(">> files: %d", len(req.files))
@@ -194,7 +218,7 @@ func (c *Client) runWithPostFields(ctx context.Context, req *Request, resp inter
 	}
 	r, err := http.NewRequest(http.MethodPost, c.endpoint, &requestBody)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	r.Close = c.closeReq
 	r.Header.Set("Content-Type", writer.FormDataContentType())
@@ -208,7 +232,7 @@ func (c *Client) runWithPostFields(ctx context.Context, req *Request, resp inter
 	r = r.WithContext(ctx)
 	res, err := c.httpClient.Do(r)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -218,20 +242,16 @@ func (c *Client) runWithPostFields(ctx context.Context, req *Request, resp inter
 	}(res.Body)
 	var buf bytes.Buffer
 	if _, err := io.Copy(&buf, res.Body); err != nil {
-		return errors.Wrap(err, "reading body")
+		return nil, errors.Wrap(err, "reading body")
 	}
 	c.logf("<< %s", buf.String())
 	if err := json.NewDecoder(&buf).Decode(&gr); err != nil {
 		if res.StatusCode != http.StatusOK {
-			return fmt.Errorf("graphql: server returned a non-200 status code: %v", res.StatusCode)
+			return nil, fmt.Errorf("graphql: server returned a non-200 status code: %v", res.StatusCode)
 		}
-		return errors.Wrap(err, "decoding response")
+		return nil, errors.Wrap(err, "decoding response")
 	}
-	if len(gr.Errors) > 0 {
-		// return first error
-		return gr.Errors[0]
-	}
-	return nil
+	return gr.Errors, nil
 }
 
 // WithHTTPClient specifies the underlying http.Client to use when
@@ -268,9 +288,16 @@ type ExtendedError interface {
 	Extensions() map[string]interface{}
 }
 
+// FieldError is an error located by its path in the response, e.g. the alias of the field that failed
+type FieldError interface {
+	ExtendedError
+	Path() []interface{}
+}
+
 type graphError struct {
 	Message         string                 `json:"message,omitempty"`
 	ErrorExtensions map[string]interface{} `json:"extensions,omitempty"`
+	ErrorPath       []interface{}          `json:"path,omitempty"`
 }
 
 func (e graphError) Error() string {
@@ -281,6 +308,10 @@ func (e graphError) Extensions() map[string]interface{} {
 	return e.ErrorExtensions
 }
 
+func (e graphError) Path() []interface{} {
+	return e.ErrorPath
+}
+
 type graphResponse struct {
 	Data   interface{}
 	Errors []graphError
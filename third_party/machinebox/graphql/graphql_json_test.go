@@ -141,6 +141,41 @@ func TestDoJSONErrWithExtensions(t *testing.T) {
 	is.Equal(err.(ExtendedError).Extensions()["code"], "400") //nolint: errorlint
 }
 
+func TestRunPartialJSON(t *testing.T) {
+	is := is.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := io.WriteString(w, `{
+			"data": {
+				"first": "yes",
+				"second": null,
+				"third": null
+			},
+			"errors": [{
+				"message": "second failed",
+				"path": ["second"],
+				"extensions": {"code": "404"}
+			}, {
+				"message": "third failed",
+				"path": ["third"]
+			}]
+		}`)
+		is.NoErr(err)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+	client := NewClient(srv.URL)
+	var responseData map[string]interface{}
+	fieldErrors, err := client.RunPartial(ctx, &Request{q: "query {}"}, &responseData)
+	is.NoErr(err)
+	is.Equal(responseData["first"], "yes")
+	is.Equal(len(fieldErrors), 2)
+	is.Equal(fieldErrors[0].Path(), []interface{}{"second"})
+	is.Equal(fieldErrors[0].Extensions()["code"], "404")
+	is.Equal(fieldErrors[1].Error(), "graphql: third failed")
+}
+
 func TestQueryJSON(t *testing.T) {
 	is := is.New(t)
 